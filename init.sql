@@ -18,6 +18,21 @@ VALUES
 ('prod_SSwz4STCQmCbUn', 20),
 ('prod_SSx2PQ18YrYpMz', 2);
 
+DROP TABLE IF EXISTS `o_stock_reservation`;
+
+CREATE TABLE `o_stock_reservation` (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    quantity INT UNSIGNED NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_order_product (order_id, product_id),
+    KEY idx_status_expires_at (status, expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 
 -- -- with optimistic locking
 -- CREATE DATABASE IF NOT EXISTS gorder;
@@ -0,0 +1,68 @@
+package actor
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey carries the calling service in gRPC metadata.
+const MetadataKey = "x-actor"
+
+type ctxKey struct{}
+
+var service atomic.Value
+
+// SetService names the running service, it is the actor when ctx carries none.
+func SetService(name string) {
+	service.Store(name)
+}
+
+// With records who is acting, e.g. the service an incoming call or message came from.
+func With(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// From returns the actor recorded in ctx, or the running service.
+func From(ctx context.Context) string {
+	if a, ok := ctx.Value(ctxKey{}).(string); ok {
+		return a
+	}
+	s, _ := service.Load().(string)
+	return s
+}
+
+// UnaryClientInterceptor tells the callee who is calling.
+func UnaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply any,
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if a := From(ctx); a != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, a)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// UnaryServerInterceptor records the caller sent by UnaryClientInterceptor as the actor.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(MetadataKey); len(v) > 0 {
+			ctx = With(ctx, v[0])
+		}
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,62 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Message is what travels through the broker, independent of the transport.
+type Message struct {
+	ID          string // consumers dedup by it
+	Key         string // partition key, the order id for order events, kafka keeps one key in order
+	Type        string // event type, empty for messages published before the envelope
+	Producer    string
+	ContentType string
+	Timestamp   time.Time
+	Headers     map[string]any // tracing and retry headers
+	Body        []byte
+}
+
+// Delivery is a received Message, the handler settles it with one of Ack, Nack or Retry.
+// Only the first call counts, later ones return ErrAlreadySettled.
+type Delivery interface {
+	Message() *Message
+	Queue() string
+	// Ack removes the message from the queue.
+	Ack() error
+	// Nack drops a message that can never be handled, e.g. it does not decode.
+	Nack() error
+	// Retry redelivers the message after a backoff, after rabbitmq.max-retry attempts it is parked in dlq.
+	Retry(ctx context.Context) error
+}
+
+// Handler handles one delivery and settles it.
+type Handler func(d Delivery)
+
+type Publisher interface {
+	// Publish sends msg to exchange, the default exchange "" routes it to the queue named key.
+	// It returns once the broker has taken the message, otherwise a *PublishError when the broker refused it.
+	Publish(ctx context.Context, exchange, key string, mandatory bool, msg *Message) error
+}
+
+// Subscription names the queue to consume, Exchange is empty for queues fed by the default exchange.
+type Subscription struct {
+	Queue      string
+	Exchange   string
+	AutoDelete bool
+}
+
+type Subscriber interface {
+	// Subscribe declares and binds the queue, then handles its deliveries until ctx is done.
+	Subscribe(ctx context.Context, sub Subscription, handler Handler) error
+}
+
+// Broker is both ends of a message broker.
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+// ErrAlreadySettled is returned when a delivery is acked, nacked or retried a second time.
+var ErrAlreadySettled = errors.New("delivery already settled")
@@ -0,0 +1,59 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/peiyouyao/gorder/common/genproto/eventpb"
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+type Encoding string
+
+const (
+	EncodingJSON     Encoding = "json"
+	EncodingProtobuf Encoding = "protobuf"
+)
+
+/*
+EncodingFor 返回 exchange 配置的编码, direct 路由的事件按队列名配置.
+rabbitmq.encoding.protobuf 中列出的用 protobuf, 其余用 rabbitmq.encoding.default, 默认 json.
+消费者按消息的 ContentType 解码, 所以要等所有消费者都升级后再把 exchange 切到 protobuf.
+*/
+func EncodingFor(exchange string) Encoding {
+	if slices.Contains(viper.GetStringSlice("rabbitmq.encoding.protobuf"), exchange) {
+		return EncodingProtobuf
+	}
+	if Encoding(viper.GetString("rabbitmq.encoding.default")) == EncodingProtobuf {
+		return EncodingProtobuf
+	}
+	return EncodingJSON
+}
+
+func marshalEnvelope(env *eventpb.Envelope, enc Encoding) (body []byte, contentType string, err error) {
+	if enc == EncodingProtobuf {
+		body, err = proto.Marshal(env)
+		return body, ContentTypeProtobuf, err
+	}
+	body, err = protojson.Marshal(env)
+	return body, ContentTypeJSON, err
+}
+
+// 没有 ContentType 的消息按 json 处理
+func unmarshalEnvelope(contentType string, body []byte, env *eventpb.Envelope) error {
+	switch contentType {
+	case ContentTypeProtobuf:
+		return proto.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, env)
+	case ContentTypeJSON, "":
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, env)
+	default:
+		return fmt.Errorf("unsupported content type %q", contentType)
+	}
+}
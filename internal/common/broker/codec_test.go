@@ -0,0 +1,69 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/genproto/eventpb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEnvelopeCodec(t *testing.T) {
+	env, err := NewEnvelope(EventOrderPaid, "event-1", "payment", testOccurredAt, testOrderProto())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		enc         Encoding
+		contentType string
+	}{
+		{EncodingJSON, ContentTypeJSON},
+		{EncodingProtobuf, ContentTypeProtobuf},
+	} {
+		t.Run(string(tc.enc), func(t *testing.T) {
+			body, contentType, err := marshalEnvelope(env, tc.enc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.contentType, contentType)
+
+			got := &eventpb.Envelope{}
+			require.NoError(t, unmarshalEnvelope(contentType, body, got))
+			assert.True(t, proto.Equal(env, got), "got %v", got)
+		})
+	}
+
+	// 没有 ContentType 的消息按 json 解码
+	body, _, err := marshalEnvelope(env, EncodingJSON)
+	require.NoError(t, err)
+	got := &eventpb.Envelope{}
+	require.NoError(t, unmarshalEnvelope("", body, got))
+	assert.True(t, proto.Equal(env, got))
+
+	assert.Error(t, unmarshalEnvelope("text/plain", body, &eventpb.Envelope{}))
+	// 按错误的格式解码会失败, 不会得到一个半对的 envelope
+	assert.Error(t, unmarshalEnvelope(ContentTypeProtobuf, body, &eventpb.Envelope{}))
+}
+
+func TestEncodingFor(t *testing.T) {
+	setEncoding := func(t *testing.T, def string, protobuf []string) {
+		oldDef, oldProtobuf := viper.Get("rabbitmq.encoding.default"), viper.Get("rabbitmq.encoding.protobuf")
+		t.Cleanup(func() {
+			viper.Set("rabbitmq.encoding.default", oldDef)
+			viper.Set("rabbitmq.encoding.protobuf", oldProtobuf)
+		})
+		viper.Set("rabbitmq.encoding.default", def)
+		viper.Set("rabbitmq.encoding.protobuf", protobuf)
+	}
+
+	// 默认配置全部用 json
+	for _, exchange := range []string{EventOrderCreated, EventOrderPaid, EventOrderRefunded} {
+		assert.Equal(t, EncodingJSON, EncodingFor(exchange), exchange)
+	}
+
+	setEncoding(t, "json", []string{EventOrderPaid})
+	assert.Equal(t, EncodingProtobuf, EncodingFor(EventOrderPaid))
+	assert.Equal(t, EncodingJSON, EncodingFor(EventOrderCreated))
+
+	setEncoding(t, "protobuf", nil)
+	assert.Equal(t, EncodingProtobuf, EncodingFor(EventOrderCreated))
+}
@@ -0,0 +1,322 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	reconnectInitialDelay = time.Duration(viper.GetInt("rabbitmq.reconnect.initial-delay")) * time.Second
+	reconnectMaxDelay     = time.Duration(viper.GetInt("rabbitmq.reconnect.max-delay")) * time.Second
+)
+
+// 等 confirm 超时的 publish 不会来取它的退回消息, 在 returned 里最多留这么久
+const returnedKeep = time.Minute
+
+/*
+Connection 维护到 rabbitmq 的连接, 是 Publisher 和 Subscriber 的 rabbitmq 实现.
+连接或 channel 断开后按退避重连, 重新声明 exchange 和 DLX, 通过 Subscribe 注册的消费者随之重启并重新声明队列.
+发布用单独的 channel, 开启 publisher confirm.
+*/
+type Connection struct {
+	address string
+
+	mu      sync.RWMutex
+	conn    *amqp.Connection
+	ch      *amqp.Channel // 消费者用
+	pub     *amqp.Channel // confirm 模式, PublishEvent 和 handleRetry 用
+	returns chan amqp.Return
+	ready   chan struct{} // 连上后关闭, 断开后换成新的
+	closing chan struct{}
+	done    chan struct{}
+
+	returnedMu sync.Mutex
+	returned   map[string]returnedMsg // 按 MessageId 暂存 broker 退回的 mandatory 消息
+}
+
+type returnedMsg struct {
+	amqp.Return
+	at time.Time
+}
+
+// Connect blocks until the first connection is up, later drops are recovered in the background.
+func Connect(user, password, host, port string) *Connection {
+	c := &Connection{
+		address:  fmt.Sprintf("amqp://%s:%s@%s:%s", user, password, host, port),
+		ready:    make(chan struct{}),
+		closing:  make(chan struct{}),
+		returned: make(map[string]returnedMsg),
+		done:     make(chan struct{}),
+	}
+	go c.run()
+	<-c.ready
+	return c
+}
+
+// Close stops reconnecting and closes the connection, call it after the consumers are drained.
+func (c *Connection) Close() error {
+	close(c.closing)
+	<-c.done
+	return nil
+}
+
+// Channel waits for a healthy consumer channel.
+func (c *Connection) Channel(ctx context.Context) (*amqp.Channel, error) {
+	ch, _, _, err := c.channels(ctx)
+	return ch, err
+}
+
+/*
+publish 等 broker confirm 后才返回.
+mandatory 消息没有匹配的队列时 broker 先发 basic.return 再 ack, 两者在同一个协程里按顺序分发,
+所以收到 ack 时被退回的消息已经在 returns 里了.
+*/
+func (c *Connection) publish(ctx context.Context, exchange, key string, mandatory bool, msg amqp.Publishing) error {
+	_, pub, returns, err := c.channels(ctx)
+	if err != nil {
+		return err
+	}
+	fail := func(reason PublishFailure, replyText string, err error) error {
+		return &PublishError{
+			Reason:     reason,
+			Exchange:   exchange,
+			RoutingKey: key,
+			MessageID:  msg.MessageId,
+			ReplyText:  replyText,
+			Err:        err,
+		}
+	}
+
+	confirm, err := pub.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, false, msg)
+	if err != nil {
+		return err
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		if mandatory {
+			// 已经退回的消息没人会再来取
+			c.takeReturned(returns, msg.MessageId)
+		}
+		return fail(PublishUnconfirmed, "", err)
+	}
+	if !acked {
+		return fail(PublishNacked, "", nil)
+	}
+	if mandatory {
+		if r, ok := c.takeReturned(returns, msg.MessageId); ok {
+			return fail(PublishUnroutable, r.ReplyText, nil)
+		}
+	}
+	return nil
+}
+
+func (c *Connection) takeReturned(returns <-chan amqp.Return, msgID string) (amqp.Return, bool) {
+	c.returnedMu.Lock()
+	defer c.returnedMu.Unlock()
+	now := time.Now()
+	for drained := false; !drained; {
+		select {
+		case r, ok := <-returns:
+			if !ok { // channel 已关闭
+				drained = true
+				break
+			}
+			c.returned[r.MessageId] = returnedMsg{Return: r, at: now}
+		default:
+			drained = true
+		}
+	}
+	r, ok := c.returned[msgID]
+	delete(c.returned, msgID)
+	// 超时之后才到的退回消息
+	maps.DeleteFunc(c.returned, func(_ string, r returnedMsg) bool {
+		return now.Sub(r.at) > returnedKeep
+	})
+	return r.Return, ok
+}
+
+// impl Publisher
+func (c *Connection) Publish(ctx context.Context, exchange, key string, mandatory bool, msg *Message) error {
+	if exchange == "" {
+		// 消费者上线前发布的消息留在队列里
+		ch, err := c.Channel(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err = ch.QueueDeclare(key, true, false, false, false, nil); err != nil {
+			return err
+		}
+	}
+	return c.publish(ctx, exchange, key, mandatory, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.ID,
+		Type:         msg.Type,
+		AppId:        msg.Producer,
+		Timestamp:    msg.Timestamp,
+		Headers:      msg.Headers,
+		Body:         msg.Body,
+	})
+}
+
+// impl Subscriber
+func (c *Connection) Subscribe(ctx context.Context, sub Subscription, handler Handler) error {
+	if handler == nil {
+		panic("nil handler")
+	}
+	return c.consume(ctx, sub.Queue, func(ctx context.Context, ch *amqp.Channel) error {
+		q, err := ch.QueueDeclare(sub.Queue, true, false, sub.AutoDelete, false, nil)
+		if err != nil {
+			return err
+		}
+		if sub.Exchange != "" {
+			if err = ch.QueueBind(q.Name, "", sub.Exchange, false, nil); err != nil {
+				return err
+			}
+		}
+		r := NewConsumerRunner(ch, q, handler)
+		r.publish = c.publish
+		return r.Run(ctx)
+	})
+}
+
+/*
+consume 在每次连上后用新的 channel 调用 listen, listen 应该声明队列并一直消费到 ctx 结束.
+连接断开时 listen 返回, 等重连后再调用; ctx 结束后返回 nil, Close 之后返回 amqp.ErrClosed.
+*/
+func (c *Connection) consume(ctx context.Context, name string, listen func(ctx context.Context, ch *amqp.Channel) error) error {
+	for {
+		ch, err := c.Channel(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err = listen(ctx, ch); err != nil {
+			logrus.WithField("consumer", name).Warnf("Listen fail err=%v", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		logrus.WithField("consumer", name).Warn("Consumer stopped, restart after reconnect")
+		// 避免在连接状态更新前空转
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectInitialDelay):
+		}
+	}
+}
+
+func (c *Connection) channels(ctx context.Context) (*amqp.Channel, *amqp.Channel, chan amqp.Return, error) {
+	for {
+		c.mu.RLock()
+		ready, ch, pub, returns := c.ready, c.ch, c.pub, c.returns
+		c.mu.RUnlock()
+
+		select {
+		case <-ready:
+			if !ch.IsClosed() && !pub.IsClosed() {
+				return ch, pub, returns, nil
+			}
+			// 已经断开, run 还没来得及换掉 ready
+			select {
+			case <-ctx.Done():
+				return nil, nil, nil, ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+			}
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		case <-c.closing:
+			return nil, nil, nil, amqp.ErrClosed
+		}
+	}
+}
+
+func (c *Connection) run() {
+	defer close(c.done)
+	delay := reconnectInitialDelay
+	for {
+		conn, ch, pub, err := c.dial()
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"retry_in": delay,
+				"err":      err.Error(),
+			}).Warn("RabbitMQ connect fail")
+			select {
+			case <-c.closing:
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, reconnectMaxDelay)
+			continue
+		}
+		delay = reconnectInitialDelay
+
+		connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+		pubClosed := pub.NotifyClose(make(chan *amqp.Error, 1))
+		// 缓冲要大于同时在等 confirm 的 mandatory 消息数, 否则 broker 的 ack 会堵在 return 后面
+		returns := pub.NotifyReturn(make(chan amqp.Return, 1024))
+
+		c.returnedMu.Lock()
+		clear(c.returned)
+		c.returnedMu.Unlock()
+
+		c.mu.Lock()
+		c.conn, c.ch, c.pub, c.returns = conn, ch, pub, returns
+		close(c.ready)
+		c.mu.Unlock()
+		logrus.Info("RabbitMQ connected")
+
+		var reason *amqp.Error
+		select {
+		case <-c.closing:
+			_ = pub.Close()
+			_ = ch.Close()
+			_ = conn.Close()
+			return
+		case reason = <-connClosed:
+		case reason = <-chClosed:
+		case reason = <-pubClosed:
+		}
+		logrus.WithField("reason", reason).Warn("RabbitMQ connection lost, reconnecting")
+
+		c.mu.Lock()
+		c.ready = make(chan struct{})
+		c.mu.Unlock()
+		// 只断了一个 channel 时连接还在, 整个重建
+		_ = conn.Close()
+	}
+}
+
+func (c *Connection) dial() (conn *amqp.Connection, ch, pub *amqp.Channel, err error) {
+	if conn, err = amqp.Dial(c.address); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	if ch, err = conn.Channel(); err != nil {
+		return
+	}
+	if err = declareTopology(ch); err != nil {
+		return
+	}
+	if pub, err = conn.Channel(); err != nil {
+		return
+	}
+	err = pub.Confirm(false)
+	return
+}
@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+ConsumerRunner 用 Workers 个协程并发消费一个队列, 每个消费者最多 Prefetch 条未 ack 的消息.
+ctx 结束后先 cancel 消费者不再接收新消息, 已经推送过来的消息处理完 Run 才返回,
+之后再关闭 channel, 不会丢掉处理到一半的消息.
+*/
+type ConsumerRunner struct {
+	Workers  int
+	Prefetch int
+
+	ch      *amqp.Channel
+	q       amqp.Queue
+	handler Handler
+	publish publishFunc // Delivery.Retry 用
+}
+
+// publishFunc 发布一条消息, 返回 nil 表示 broker 已经收下, 见 Connection.publish
+type publishFunc func(ctx context.Context, exchange, key string, mandatory bool, msg amqp.Publishing) error
+
+func NewConsumerRunner(ch *amqp.Channel, q amqp.Queue, handler Handler) *ConsumerRunner {
+	if ch == nil {
+		panic("nil channel")
+	}
+	if handler == nil {
+		panic("nil handler")
+	}
+	return &ConsumerRunner{
+		Workers:  viper.GetInt("rabbitmq.consumer.workers"),
+		Prefetch: viper.GetInt("rabbitmq.consumer.prefetch"),
+		ch:       ch,
+		q:        q,
+		handler:  handler,
+		// 没有 confirm, Connection 会换成等 confirm 的 publish
+		publish: func(ctx context.Context, exchange, key string, mandatory bool, msg amqp.Publishing) error {
+			return ch.PublishWithContext(ctx, exchange, key, mandatory, false, msg)
+		},
+	}
+}
+
+// Run blocks until ctx is done and every delivery already received has been handled.
+func (r *ConsumerRunner) Run(ctx context.Context) error {
+	workers := max(r.Workers, 1)
+	if r.Prefetch > 0 {
+		// global=false, 只对之后在这个 channel 上创建的消费者生效
+		if err := r.ch.Qos(r.Prefetch, 0, false); err != nil {
+			return err
+		}
+	}
+
+	tag := r.q.Name + "-" + uuid.NewString()
+	msgs, err := r.ch.Consume(r.q.Name, tag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// msgs 在已推送的消息取完后关闭, worker 随之退出
+			if err := r.ch.Cancel(tag, false); err != nil {
+				logrus.WithField("q_name", r.q.Name).Warnf("Cancel consumer fail err=%v", err)
+			}
+		case <-stop:
+		}
+	}()
+
+	logrus.WithFields(logrus.Fields{
+		"q_name":   r.q.Name,
+		"workers":  workers,
+		"prefetch": r.Prefetch,
+	}).Info("Consumer started")
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range msgs {
+				r.handler(newRabbitDelivery(r.ch, r.publish, d, r.q.Name))
+			}
+		}()
+	}
+	wg.Wait()
+
+	logrus.WithField("q_name", r.q.Name).Info("Consumer drained")
+	return nil
+}
+
+// impl Delivery
+type rabbitDelivery struct {
+	ch      *amqp.Channel
+	publish publishFunc
+	d       amqp.Delivery
+	queue   string
+	msg     *Message
+	settled atomic.Bool
+}
+
+func newRabbitDelivery(ch *amqp.Channel, publish publishFunc, d amqp.Delivery, queue string) *rabbitDelivery {
+	if d.Headers == nil {
+		d.Headers = amqp.Table{}
+	}
+	return &rabbitDelivery{
+		ch:      ch,
+		publish: publish,
+		d:       d,
+		queue:   queue,
+		msg: &Message{
+			ID:          d.MessageId,
+			Type:        d.Type,
+			Producer:    d.AppId,
+			ContentType: d.ContentType,
+			Timestamp:   d.Timestamp,
+			Headers:     d.Headers,
+			Body:        d.Body,
+		},
+	}
+}
+
+func (r *rabbitDelivery) Message() *Message {
+	return r.msg
+}
+
+func (r *rabbitDelivery) Queue() string {
+	return r.queue
+}
+
+func (r *rabbitDelivery) Ack() error {
+	if !r.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return r.d.Ack(false)
+}
+
+func (r *rabbitDelivery) Nack() error {
+	if !r.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return r.d.Nack(false, false)
+}
+
+// Retry 等 broker 确认收下延迟队列里的副本后再 ack, 没有确认就 requeue, 消息不会丢
+func (r *rabbitDelivery) Retry(ctx context.Context) error {
+	if !r.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	if err := handleRetry(ctx, r.ch, r.publish, &r.d, r.queue); err != nil {
+		_ = r.d.Nack(false, true)
+		return err
+	}
+	return r.d.Ack(false)
+}
@@ -0,0 +1,261 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/handler/redis"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	dedupKeyPrefix  = "gorder:mq:processed:"
+	dedupProcessing = "processing"
+	dedupDone       = "done"
+)
+
+var (
+	dedupTTL   = time.Duration(viper.GetInt("rabbitmq.dedup.ttl")) * time.Second
+	dedupLease = time.Duration(viper.GetInt("rabbitmq.dedup.lease")) * time.Second
+)
+
+var (
+	// ErrMessageInProgress means another delivery of the same message is still being handled.
+	ErrMessageInProgress = errors.New("message is being processed")
+	// ErrUnprocessable marks a message that can never be handled, Dedup.Handle drops it instead of retrying.
+	ErrUnprocessable = errors.New("unprocessable message")
+)
+
+// Unprocessable wraps err with ErrUnprocessable.
+func Unprocessable(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnprocessable, err)
+}
+
+/*
+Dedup 在 redis 中记录消费者已处理的 MessageId,
+outbox relay 重复投递, mq 重投, Delivery.Retry 重发的同一条消息只处理一次
+*/
+type Dedup struct {
+	consumer string
+	store    dedupStore
+}
+
+// NewDedup keeps the claims in redis, or in the process with the memory driver.
+func NewDedup(consumer string) *Dedup {
+	if viper.GetString("broker.driver") == DriverMemory {
+		return NewMemoryDedup(consumer)
+	}
+	return newDedup(consumer, redisDedupStore{})
+}
+
+// NewMemoryDedup keeps the claims in the process, for tests and the memory driver.
+func NewMemoryDedup(consumer string) *Dedup {
+	return newDedup(consumer, &memDedupStore{keys: make(map[string]memDedupKey)})
+}
+
+func newDedup(consumer string, store dedupStore) *Dedup {
+	if consumer == "" {
+		panic("empty consumer")
+	}
+	return &Dedup{consumer: consumer, store: store}
+}
+
+/*
+Handle 处理 d 并结算它, 同一条消息只处理一次:
+重复的消息直接 ack, 另一次投递还在处理中时 Delivery.Retry 稍后重投;
+fn 成功后标记 Done 并 ack, 失败时先释放 claim, 错误是 ErrUnprocessable 时 nack, 否则 Delivery.Retry.
+*/
+func (d *Dedup) Handle(ctx context.Context, delivery Delivery, fn func(ctx context.Context) error) {
+	msg := delivery.Message()
+	if err := d.handle(ctx, delivery, fn); err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"q_name": delivery.Queue(),
+			"q_msg":  msg,
+			"err":    err.Error(),
+		}).Warn("MQ consume fail")
+		_ = delivery.Nack()
+		return
+	}
+	logrus.WithContext(ctx).Info("MQ consume ok")
+	// Retry 过的消息已经结算, 这里的 ack 不起作用
+	_ = delivery.Ack()
+}
+
+func (d *Dedup) handle(ctx context.Context, delivery Delivery, fn func(ctx context.Context) error) error {
+	msg := delivery.Message()
+	first, err := d.Claim(ctx, msg)
+	if err != nil {
+		// 同一条消息的另一次投递还在处理中, 稍后重投
+		return d.retry(ctx, delivery)
+	}
+	if !first {
+		logrus.WithContext(ctx).WithField("msg_id", msg.ID).Info("Skip duplicate msg")
+		return nil
+	}
+
+	if err = fn(ctx); err != nil {
+		d.Release(ctx, msg)
+		if errors.Is(err, ErrUnprocessable) {
+			return err
+		}
+		return d.retry(ctx, delivery)
+	}
+	d.Done(ctx, msg)
+	return nil
+}
+
+func (d *Dedup) retry(ctx context.Context, delivery Delivery) error {
+	err := delivery.Retry(ctx)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"msg_id": delivery.Message().ID,
+			"err":    err.Error(),
+		}).Warn("Retry fail")
+	}
+	return err
+}
+
+// Claim 返回 false 表示消息已经处理过, ack 后跳过即可.
+// 另一次投递还在处理中时返回 ErrMessageInProgress, 交给 Delivery.Retry 稍后重投.
+func (d *Dedup) Claim(ctx context.Context, msg *Message) (bool, error) {
+	if msg.ID == "" {
+		return true, nil
+	}
+	key := d.key(msg.ID)
+	ok, err := d.store.SetNX(ctx, key, dedupProcessing, dedupLease)
+	if err != nil {
+		// redis 不可用时退化为至少一次投递
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"consumer": d.consumer,
+			"msg_id":   msg.ID,
+			"err":      err.Error(),
+		}).Warn("Dedup claim fail, consume anyway")
+		return true, nil
+	}
+	if ok {
+		return true, nil
+	}
+
+	state, err := d.store.Get(ctx, key)
+	if err == nil && state == dedupDone {
+		return false, nil
+	}
+	return false, ErrMessageInProgress
+}
+
+// Done marks the message processed, later deliveries of it are skipped for rabbitmq.dedup.ttl.
+func (d *Dedup) Done(ctx context.Context, msg *Message) {
+	if msg.ID == "" {
+		return
+	}
+	if err := d.store.Set(ctx, d.key(msg.ID), dedupDone, dedupTTL); err != nil {
+		logrus.WithContext(ctx).Warnf("Dedup done fail consumer=%s msg_id=%s err=%v", d.consumer, msg.ID, err)
+	}
+}
+
+// Release drops the claim so a retried delivery is handled again, call it before Delivery.Retry.
+func (d *Dedup) Release(ctx context.Context, msg *Message) {
+	if msg.ID == "" {
+		return
+	}
+	if err := d.store.Del(ctx, d.key(msg.ID)); err != nil {
+		logrus.WithContext(ctx).Warnf("Dedup release fail consumer=%s msg_id=%s err=%v", d.consumer, msg.ID, err)
+	}
+}
+
+func (d *Dedup) key(msgID string) string {
+	return dedupKeyPrefix + d.consumer + ":" + msgID
+}
+
+// dedupStore 是 Dedup 用到的 redis 命令, 测试时换成内存实现
+type dedupStore interface {
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+type redisDedupStore struct{}
+
+func (redisDedupStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return redis.TrySetNX(ctx, redis.LocaClient(), key, value, ttl)
+}
+
+func (redisDedupStore) Get(ctx context.Context, key string) (string, error) {
+	return redis.Get(ctx, redis.LocaClient(), key)
+}
+
+func (redisDedupStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return redis.Set(ctx, redis.LocaClient(), key, value, ttl)
+}
+
+func (redisDedupStore) Del(ctx context.Context, key string) error {
+	return redis.Del(ctx, redis.LocaClient(), key)
+}
+
+// impl dedupStore
+type memDedupStore struct {
+	mu   sync.Mutex
+	keys map[string]memDedupKey
+}
+
+type memDedupKey struct {
+	value    string
+	expireAt time.Time // 零值不过期
+}
+
+func (s *memDedupStore) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+	s.set(key, value, ttl)
+	return true, nil
+}
+
+func (s *memDedupStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.get(key)
+	if !ok {
+		return "", redis.ErrNil
+	}
+	return v, nil
+}
+
+func (s *memDedupStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(key, value, ttl)
+	return nil
+}
+
+func (s *memDedupStore) Del(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+// 调用方持有 s.mu, 过期的 key 在读到时删除
+func (s *memDedupStore) get(key string) (string, bool) {
+	k, ok := s.keys[key]
+	if ok && !k.expireAt.IsZero() && time.Now().After(k.expireAt) {
+		delete(s.keys, key)
+		return "", false
+	}
+	return k.value, ok
+}
+
+func (s *memDedupStore) set(key, value string, ttl time.Duration) {
+	k := memDedupKey{value: value}
+	if ttl > 0 {
+		k.expireAt = time.Now().Add(ttl)
+	}
+	s.keys[key] = k
+}
@@ -0,0 +1,146 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 内存实现加上可以模拟 redis 不可用
+type failingDedupStore struct {
+	dedupStore
+	err error
+}
+
+func (s *failingDedupStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.dedupStore.SetNX(ctx, key, value, ttl)
+}
+
+func newTestDedup() (*Dedup, *failingDedupStore) {
+	store := &failingDedupStore{dedupStore: &memDedupStore{keys: map[string]memDedupKey{}}}
+	return newDedup("test", store), store
+}
+
+// impl Delivery, 记录第一次结算
+type testDelivery struct {
+	msg     *Message
+	settled string
+}
+
+func (d *testDelivery) Message() *Message { return d.msg }
+func (d *testDelivery) Queue() string     { return "q" }
+func (d *testDelivery) Ack() error        { return d.settle("ack") }
+func (d *testDelivery) Nack() error       { return d.settle("nack") }
+
+func (d *testDelivery) Retry(context.Context) error {
+	return d.settle("retry")
+}
+
+func (d *testDelivery) settle(how string) error {
+	if d.settled != "" {
+		return ErrAlreadySettled
+	}
+	d.settled = how
+	return nil
+}
+
+func TestDedup_Claim(t *testing.T) {
+	ctx := context.Background()
+	d, store := newTestDedup()
+	msg := &Message{ID: "1"}
+
+	first, err := d.Claim(ctx, msg)
+	require.NoError(t, err)
+	assert.True(t, first)
+	_, err = d.Claim(ctx, msg)
+	assert.ErrorIs(t, err, ErrMessageInProgress)
+
+	d.Release(ctx, msg)
+	first, err = d.Claim(ctx, msg)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	d.Done(ctx, msg)
+	first, err = d.Claim(ctx, msg)
+	require.NoError(t, err)
+	assert.False(t, first)
+	state, err := store.Get(ctx, d.key("1"))
+	require.NoError(t, err)
+	assert.Equal(t, dedupDone, state)
+
+	// 其他消费者有自己的记录
+	other := newDedup("other", store)
+	first, err = other.Claim(ctx, msg)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	// 没有 id 的消息和 redis 不可用时都照常处理
+	first, err = d.Claim(ctx, &Message{})
+	require.NoError(t, err)
+	assert.True(t, first)
+	store.err = errors.New("redis down")
+	first, err = d.Claim(ctx, msg)
+	require.NoError(t, err)
+	assert.True(t, first)
+}
+
+func TestDedup_Handle(t *testing.T) {
+	ctx := context.Background()
+	handle := func(d *Dedup, id string, err error) (*testDelivery, int) {
+		delivery := &testDelivery{msg: &Message{ID: id}}
+		calls := 0
+		d.Handle(ctx, delivery, func(context.Context) error {
+			calls++
+			return err
+		})
+		return delivery, calls
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		d, _ := newTestDedup()
+		delivery, calls := handle(d, "1", nil)
+		assert.Equal(t, "ack", delivery.settled)
+		assert.Equal(t, 1, calls)
+
+		delivery, calls = handle(d, "1", nil)
+		assert.Equal(t, "ack", delivery.settled)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("in_progress", func(t *testing.T) {
+		d, _ := newTestDedup()
+		_, err := d.Claim(ctx, &Message{ID: "1"})
+		require.NoError(t, err)
+		delivery, calls := handle(d, "1", nil)
+		assert.Equal(t, "retry", delivery.settled)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		d, _ := newTestDedup()
+		delivery, _ := handle(d, "1", errors.New("db down"))
+		assert.Equal(t, "retry", delivery.settled)
+
+		// claim 已释放, 重投的消息会再处理
+		delivery, calls := handle(d, "1", nil)
+		assert.Equal(t, "ack", delivery.settled)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("unprocessable", func(t *testing.T) {
+		d, _ := newTestDedup()
+		delivery, _ := handle(d, "1", Unprocessable(errors.New("bad body")))
+		assert.Equal(t, "nack", delivery.settled)
+
+		// 从 dlq replay 的同一条消息会再处理
+		_, calls := handle(d, "1", nil)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,228 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// handleRetry 记下消息该回到的地方 (默认 exchange 和失败的队列名), dlq 里的消息靠它 replay
+const (
+	amqpOriginalExchangeHeaderKey   = "x-original-exchange"
+	amqpOriginalRoutingKeyHeaderKey = "x-original-routing-key"
+)
+
+// DeadLetter is a message parked in dlq.
+type DeadLetter struct {
+	MessageID   string         `json:"message_id"`
+	Exchange    string         `json:"exchange"`
+	RoutingKey  string         `json:"routing_key"`
+	RetryCount  int64          `json:"retry_count"`
+	TraceID     string         `json:"trace_id,omitempty"`
+	DeadAt      time.Time      `json:"dead_at"`
+	ContentType string         `json:"content_type"`
+	Headers     map[string]any `json:"headers,omitempty"`
+	Body        []byte         `json:"body"`
+}
+
+// Selection picks dlq messages by MessageId, All picks every message.
+type Selection struct {
+	All bool     `json:"all"`
+	IDs []string `json:"ids"`
+}
+
+var (
+	ErrEmptySelection = errors.New("select messages by ids or all")
+	ErrNegativeLimit  = errors.New("negative limit")
+)
+
+func (s Selection) Validate() error {
+	if !s.All && len(s.IDs) == 0 {
+		return ErrEmptySelection
+	}
+	return nil
+}
+
+func (s Selection) match(d *amqp.Delivery) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.IDs {
+		if id != "" && id == d.MessageId {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+DLQ 查看和处理进入 dlq 的消息.
+每次操作单独开一个 channel, 把队列里的消息 get 出来不 ack, 处理完后关掉 channel,
+没有被 ack 的消息会按原顺序回到 dlq.
+*/
+type DLQ struct {
+	conn *amqp.Connection
+}
+
+func NewDLQ(conn *amqp.Connection) *DLQ {
+	if conn == nil {
+		panic("nil conn")
+	}
+	return &DLQ{conn: conn}
+}
+
+// DialDLQ opens a connection of its own, for tools that do not run a consumer.
+func DialDLQ(user, password, host, port string) (*DLQ, func() error, error) {
+	conn, err := amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s", user, password, host, port))
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewDLQ(conn), conn.Close, nil
+}
+
+// List returns at most limit messages from the head of dlq, limit 0 means all of them.
+func (q *DLQ) List(ctx context.Context, limit int) (res []*DeadLetter, err error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNegativeLimit, limit)
+	}
+	err = q.scan(ctx, limit, func(_ *amqp.Channel, d *amqp.Delivery) (bool, error) {
+		res = append(res, toDeadLetter(ctx, d))
+		return false, nil
+	})
+	return
+}
+
+// Export writes every message in dlq to w as JSON lines, the messages stay in dlq.
+func (q *DLQ) Export(ctx context.Context, w io.Writer) (n int, err error) {
+	enc := json.NewEncoder(w)
+	err = q.scan(ctx, 0, func(_ *amqp.Channel, d *amqp.Delivery) (bool, error) {
+		if err := enc.Encode(toDeadLetter(ctx, d)); err != nil {
+			return false, err
+		}
+		n++
+		return false, nil
+	})
+	return
+}
+
+// Replay publishes the selected messages back to their original exchange and routing key
+// with a fresh retry count, then removes them from dlq.
+func (q *DLQ) Replay(ctx context.Context, sel Selection) (n int, err error) {
+	err = q.scan(ctx, 0, func(ch *amqp.Channel, d *amqp.Delivery) (bool, error) {
+		if !sel.match(d) {
+			return false, nil
+		}
+		exchange, key, ok := originalDestination(d)
+		if !ok {
+			logrus.WithContext(ctx).WithField("msg_id", d.MessageId).Warn("DLQ msg has no original destination, skip")
+			return false, nil
+		}
+		headers := amqp.Table{}
+		for k, v := range d.Headers {
+			headers[k] = v
+		}
+		delete(headers, amqpRetryHeaderKey)
+		if err := ch.PublishWithContext(ctx, exchange, key, false, false, amqp.Publishing{
+			MessageId:    d.MessageId,
+			Type:         d.Type,
+			AppId:        d.AppId,
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			Body:         d.Body,
+			DeliveryMode: amqp.Persistent,
+		}); err != nil {
+			return false, err
+		}
+		n++
+		return true, nil
+	})
+	return
+}
+
+// Purge drops the selected messages from dlq.
+func (q *DLQ) Purge(ctx context.Context, sel Selection) (n int, err error) {
+	if sel.All {
+		ch, err := q.conn.Channel()
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = ch.Close() }()
+		return ch.QueuePurge(dlq, false)
+	}
+	err = q.scan(ctx, 0, func(_ *amqp.Channel, d *amqp.Delivery) (bool, error) {
+		if !sel.match(d) {
+			return false, nil
+		}
+		n++
+		return true, nil
+	})
+	return
+}
+
+// fn 返回 true 时 ack, 消息从 dlq 中移除
+func (q *DLQ) scan(ctx context.Context, limit int, fn func(ch *amqp.Channel, d *amqp.Delivery) (bool, error)) error {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return err
+	}
+	// 关闭 channel 时未 ack 的消息全部回到 dlq
+	defer func() { _ = ch.Close() }()
+
+	for i := 0; limit <= 0 || i < limit; i++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		d, ok, err := ch.Get(dlq, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		remove, err := fn(ch, &d)
+		if err != nil {
+			return err
+		}
+		if remove {
+			if err = d.Ack(false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func toDeadLetter(ctx context.Context, d *amqp.Delivery) *DeadLetter {
+	exchange, key, _ := originalDestination(d)
+	retryCnt, _ := d.Headers[amqpRetryHeaderKey].(int64)
+	traceCtx := propagation.TraceContext{}.Extract(ctx, RabbitMQHeaderCarrier(d.Headers))
+	var traceID string
+	if sc := trace.SpanContextFromContext(traceCtx); sc.HasTraceID() {
+		traceID = sc.TraceID().String()
+	}
+	return &DeadLetter{
+		MessageID:   d.MessageId,
+		Exchange:    exchange,
+		RoutingKey:  key,
+		RetryCount:  retryCnt,
+		TraceID:     traceID,
+		DeadAt:      d.Timestamp,
+		ContentType: d.ContentType,
+		Headers:     d.Headers,
+		Body:        d.Body,
+	}
+}
+
+func originalDestination(d *amqp.Delivery) (exchange, key string, ok bool) {
+	exchange, ok1 := d.Headers[amqpOriginalExchangeHeaderKey].(string)
+	key, ok2 := d.Headers[amqpOriginalRoutingKeyHeaderKey].(string)
+	return exchange, key, ok1 && ok2
+}
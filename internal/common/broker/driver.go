@@ -0,0 +1,41 @@
+package broker
+
+import (
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// broker.driver 的取值
+const (
+	DriverRabbitMQ = "rabbitmq"
+	DriverMemory   = "memory"
+)
+
+var (
+	memoryOnce sync.Once
+	memory     *Memory
+)
+
+/*
+Open 按 broker.driver 返回服务用的 Broker 和关闭它的函数, 消费者 drain 完后再关闭.
+rabbitmq 在 kafka.enabled 时把 kafka.events 交给 kafka, group 是服务名;
+memory 不连任何中间件, 同一个进程里的服务共用一个 Memory, 给单进程开发模式用.
+*/
+func Open(group string) (Broker, func() error) {
+	if viper.GetString("broker.driver") == DriverMemory {
+		memoryOnce.Do(func() { memory = NewMemory() })
+		return memory, func() error { return nil }
+	}
+	conn := Connect(
+		viper.GetString("rabbitmq.user"),
+		viper.GetString("rabbitmq.password"),
+		viper.GetString("rabbitmq.host"),
+		viper.GetString("rabbitmq.port"),
+	)
+	b, closeKafka := WithKafka(conn, group)
+	return b, func() error {
+		_ = closeKafka()
+		return conn.Close()
+	}
+}
@@ -0,0 +1,206 @@
+package broker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/eventpb"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	ErrUnknownEventType   = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrInvalidEnvelope    = errors.New("invalid event envelope")
+)
+
+// Upcaster turns an envelope of one schema version into the next version in place.
+type Upcaster func(env *eventpb.Envelope) error
+
+/*
+EventSchema 描述一种事件的当前版本.
+消费时旧版本的 envelope 按 Upcasters[v], Upcasters[v+1] ... 依次升级到 Version,
+滚动发布期间新旧版本的生产者可以共存; 比当前版本还新的消息说明消费者还没升级, 直接拒绝进 dlq, 升级后再 replay.
+*/
+type EventSchema struct {
+	Version   int32
+	Upcasters map[int32]Upcaster // key 是升级前的版本
+	Validate  func(env *eventpb.Envelope) error
+}
+
+var schemas = map[string]*EventSchema{}
+
+func RegisterSchema(eventType string, s *EventSchema) {
+	if s == nil || s.Version < 1 {
+		panic("invalid schema for " + eventType)
+	}
+	schemas[eventType] = s
+}
+
+func init() {
+	// v1: 没有 envelope 的 order json, v2: envelope + orderpb.Order
+	for _, t := range []string{EventOrderCreated, EventOrderPaid, EventOrderCancelled, EventOrderExpired, EventOrderRejected, EventOrderRefunded} {
+		RegisterSchema(t, &EventSchema{
+			Version:   2,
+			Upcasters: map[int32]Upcaster{1: upcastLegacyOrder},
+			Validate:  validateOrderPayload,
+		})
+	}
+}
+
+// NewEnvelope wraps an order payload, body is an *orderpb.Order, an entity.Order or anything that marshals to order json.
+func NewEnvelope(eventType, eventID, producer string, occurredAt time.Time, body any) (*eventpb.Envelope, error) {
+	s, ok := schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	o, err := orderPayload(body)
+	if err != nil {
+		return nil, err
+	}
+	env := &eventpb.Envelope{
+		EventID:       eventID,
+		EventType:     eventType,
+		SchemaVersion: s.Version,
+		OccurredAt:    occurredAt.UnixMilli(),
+		Producer:      producer,
+		CorrelationID: o.ID,
+		Payload:       &eventpb.Envelope_Order{Order: o},
+	}
+	return env, validate(env, eventType, s)
+}
+
+/*
+DecodeEvent 解析并校验消息里的 envelope, 升级到当前版本后返回.
+按 ContentType 解码 json 或 protobuf; 没有 Type 的消息是 envelope 之前发布的 json, 当作 v1 处理.
+*/
+func DecodeEvent(msg *Message, eventType string) (*eventpb.Envelope, error) {
+	s, ok := schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	env := &eventpb.Envelope{}
+	if msg.Type == "" {
+		env = legacyEnvelope(msg, eventType)
+	} else if msg.Type != eventType {
+		return nil, fmt.Errorf("%w: message type %q, want %q", ErrInvalidEnvelope, msg.Type, eventType)
+	} else if err := unmarshalEnvelope(msg.ContentType, msg.Body, env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+
+	if env.EventType != eventType {
+		return nil, fmt.Errorf("%w: event type %q, want %q", ErrInvalidEnvelope, env.EventType, eventType)
+	}
+	if env.SchemaVersion < 1 || env.SchemaVersion > s.Version {
+		return nil, fmt.Errorf("%w: %s v%d, current v%d", ErrUnsupportedVersion, eventType, env.SchemaVersion, s.Version)
+	}
+	for env.SchemaVersion < s.Version {
+		up, ok := s.Upcasters[env.SchemaVersion]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upcaster for %s v%d", ErrUnsupportedVersion, eventType, env.SchemaVersion)
+		}
+		if err := up(env); err != nil {
+			return nil, fmt.Errorf("upcast %s v%d: %w", eventType, env.SchemaVersion, err)
+		}
+		env.SchemaVersion++
+	}
+	return env, validate(env, eventType, s)
+}
+
+func validate(env *eventpb.Envelope, eventType string, s *EventSchema) error {
+	switch {
+	case env.EventID == "":
+		return fmt.Errorf("%w: empty event id", ErrInvalidEnvelope)
+	case env.EventType != eventType:
+		return fmt.Errorf("%w: event type %q, want %q", ErrInvalidEnvelope, env.EventType, eventType)
+	case env.SchemaVersion != s.Version:
+		return fmt.Errorf("%w: %s v%d, current v%d", ErrUnsupportedVersion, eventType, env.SchemaVersion, s.Version)
+	case env.OccurredAt <= 0:
+		return fmt.Errorf("%w: empty occurred at", ErrInvalidEnvelope)
+	case env.Producer == "":
+		return fmt.Errorf("%w: empty producer", ErrInvalidEnvelope)
+	}
+	if s.Validate != nil {
+		return s.Validate(env)
+	}
+	return nil
+}
+
+func legacyEnvelope(msg *Message, eventType string) *eventpb.Envelope {
+	id := msg.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	occurredAt := msg.Timestamp
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	producer := msg.Producer
+	if producer == "" {
+		producer = "unknown"
+	}
+	return &eventpb.Envelope{
+		EventID:       id,
+		EventType:     eventType,
+		SchemaVersion: 1,
+		OccurredAt:    occurredAt.UnixMilli(),
+		Producer:      producer,
+		LegacyPayload: msg.Body,
+	}
+}
+
+// v1 -> v2
+func upcastLegacyOrder(env *eventpb.Envelope) error {
+	o := &orderpb.Order{}
+	// order json 的字段名和 orderpb.Order 的 proto 字段名一致
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(env.LegacyPayload, o); err != nil {
+		return err
+	}
+	env.Payload = &eventpb.Envelope_Order{Order: o}
+	env.LegacyPayload = nil
+	if env.CorrelationID == "" {
+		env.CorrelationID = o.ID
+	}
+	return nil
+}
+
+func validateOrderPayload(env *eventpb.Envelope) error {
+	o := env.GetOrder()
+	if o == nil {
+		return fmt.Errorf("%w: missing order payload", ErrInvalidEnvelope)
+	}
+	if o.ID == "" {
+		return fmt.Errorf("%w: empty order id", ErrInvalidEnvelope)
+	}
+	return nil
+}
+
+func orderPayload(body any) (*orderpb.Order, error) {
+	switch o := body.(type) {
+	case *orderpb.Order:
+		return o, nil
+	case *entity.Order:
+		return convert.OrderEntityToProto(o), nil
+	case entity.Order:
+		return convert.OrderEntityToProto(&o), nil
+	}
+	b, ok := body.(json.RawMessage)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	o := &orderpb.Order{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
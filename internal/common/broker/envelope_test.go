@@ -0,0 +1,162 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/eventpb"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOccurredAt = time.UnixMilli(1700000000000)
+
+func testOrderProto() *orderpb.Order {
+	return &orderpb.Order{
+		ID:          "order-1",
+		CustomerID:  "customer-1",
+		Status:      constants.OrderStatusPaid,
+		PaymentLink: "https://pay.example/1",
+		Items:       []*orderpb.Item{{ID: "item-1", Name: "apple", Quantity: 2, PriceID: "price-1"}},
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	o := testOrderProto()
+	for _, tc := range []struct {
+		name      string
+		eventType string
+		body      any
+		wantErr   error
+	}{
+		{name: "proto", eventType: EventOrderPaid, body: o},
+		{name: "entity", eventType: EventOrderPaid, body: entity.NewOrder(o.ID, o.CustomerID, o.Status, o.PaymentLink, nil)},
+		{name: "entity_value", eventType: EventOrderPaid, body: *entity.NewOrder(o.ID, o.CustomerID, o.Status, o.PaymentLink, nil)},
+		{name: "json", eventType: EventOrderCreated, body: json.RawMessage(`{"ID":"order-1","CustomerID":"customer-1","Extra":1}`)},
+		{name: "unknown_type", eventType: "order.lost", body: o, wantErr: ErrUnknownEventType},
+		{name: "no_order_id", eventType: EventOrderPaid, body: &orderpb.Order{CustomerID: "customer-1"}, wantErr: ErrInvalidEnvelope},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := NewEnvelope(tc.eventType, "event-1", "payment", testOccurredAt, tc.body)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "event-1", env.EventID)
+			assert.Equal(t, tc.eventType, env.EventType)
+			assert.Equal(t, int32(2), env.SchemaVersion)
+			assert.Equal(t, testOccurredAt.UnixMilli(), env.OccurredAt)
+			assert.Equal(t, "order-1", env.CorrelationID)
+			assert.Equal(t, "customer-1", env.GetOrder().CustomerID)
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	encode := func(env *eventpb.Envelope, enc Encoding) *Message {
+		body, contentType, err := marshalEnvelope(env, enc)
+		require.NoError(t, err)
+		return &Message{ID: env.EventID, Type: env.EventType, ContentType: contentType, Body: body}
+	}
+	current, err := NewEnvelope(EventOrderPaid, "event-1", "payment", testOccurredAt, testOrderProto())
+	require.NoError(t, err)
+	newer, err := NewEnvelope(EventOrderPaid, "event-1", "payment", testOccurredAt, testOrderProto())
+	require.NoError(t, err)
+	newer.SchemaVersion = 3
+	noOrder, err := NewEnvelope(EventOrderPaid, "event-1", "payment", testOccurredAt, testOrderProto())
+	require.NoError(t, err)
+	noOrder.Payload = nil
+
+	// envelope 之前发布的 entity.Order json, 那时还没有时间字段
+	legacyBody := []byte(`{"ID":"order-1","CustomerID":"customer-1","Status":"paid","PaymentLink":"https://pay.example/1","Items":null}`)
+	legacy := &Message{ID: "legacy-1", Producer: "payment", Timestamp: testOccurredAt, Body: legacyBody}
+
+	for _, tc := range []struct {
+		name      string
+		msg       *Message
+		eventType string
+		wantErr   error
+	}{
+		{name: "json", msg: encode(current, EncodingJSON), eventType: EventOrderPaid},
+		{name: "protobuf", msg: encode(current, EncodingProtobuf), eventType: EventOrderPaid},
+		{name: "legacy_v1", msg: legacy, eventType: EventOrderPaid},
+		{name: "unknown_schema", msg: encode(current, EncodingJSON), eventType: "order.lost", wantErr: ErrUnknownEventType},
+		{name: "other_type", msg: encode(current, EncodingJSON), eventType: EventOrderCreated, wantErr: ErrInvalidEnvelope},
+		{name: "newer_version", msg: encode(newer, EncodingJSON), eventType: EventOrderPaid, wantErr: ErrUnsupportedVersion},
+		{name: "missing_order", msg: encode(noOrder, EncodingJSON), eventType: EventOrderPaid, wantErr: ErrInvalidEnvelope},
+		{
+			name:      "invalid_body",
+			msg:       &Message{ID: "event-1", Type: EventOrderPaid, ContentType: ContentTypeJSON, Body: []byte("{")},
+			eventType: EventOrderPaid,
+			wantErr:   ErrInvalidEnvelope,
+		},
+		{
+			name:      "unsupported_content_type",
+			msg:       &Message{ID: "event-1", Type: EventOrderPaid, ContentType: "text/plain", Body: []byte("x")},
+			eventType: EventOrderPaid,
+			wantErr:   ErrInvalidEnvelope,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := DecodeEvent(tc.msg, tc.eventType)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), env.SchemaVersion)
+			assert.Equal(t, "order-1", env.GetOrder().ID)
+			assert.Equal(t, "customer-1", env.GetOrder().CustomerID)
+			assert.Equal(t, constants.OrderStatusPaid, env.GetOrder().Status)
+			assert.Equal(t, "order-1", env.CorrelationID)
+			assert.Equal(t, "payment", env.Producer)
+			assert.Equal(t, testOccurredAt.UnixMilli(), env.OccurredAt)
+		})
+	}
+}
+
+func TestUpcastLegacyOrder(t *testing.T) {
+	env := &eventpb.Envelope{
+		SchemaVersion: 1,
+		LegacyPayload: []byte(`{"ID":"order-1","CustomerID":"customer-1","Status":"paid","Items":[{"ID":"item-1","Quantity":2}],"Unknown":true}`),
+	}
+	require.NoError(t, upcastLegacyOrder(env))
+	assert.Nil(t, env.LegacyPayload)
+	assert.Equal(t, "order-1", env.CorrelationID)
+	assert.Equal(t, "paid", env.GetOrder().Status)
+	require.Len(t, env.GetOrder().Items, 1)
+	assert.Equal(t, int32(2), env.GetOrder().Items[0].Quantity)
+
+	// 已有的 CorrelationID 不变
+	env = &eventpb.Envelope{CorrelationID: "c-1", LegacyPayload: []byte(`{"ID":"order-1"}`)}
+	require.NoError(t, upcastLegacyOrder(env))
+	assert.Equal(t, "c-1", env.CorrelationID)
+
+	assert.Error(t, upcastLegacyOrder(&eventpb.Envelope{LegacyPayload: []byte("not json")}))
+}
+
+func TestValidateOrderPayload(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		env     *eventpb.Envelope
+		wantErr bool
+	}{
+		{name: "ok", env: &eventpb.Envelope{Payload: &eventpb.Envelope_Order{Order: &orderpb.Order{ID: "order-1"}}}},
+		{name: "no_payload", env: &eventpb.Envelope{}, wantErr: true},
+		{name: "no_order_id", env: &eventpb.Envelope{Payload: &eventpb.Envelope_Order{Order: &orderpb.Order{}}}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOrderPayload(tc.env)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidEnvelope)
+		})
+	}
+}
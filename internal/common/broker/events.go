@@ -2,20 +2,26 @@ package broker
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/peiyouyao/gorder/common/util"
-	"github.com/rabbitmq/amqp091-go"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 const (
-	EventOrderCreated = "order.created"
-	EventOrderPaid    = "order.paid"
+	EventOrderCreated   = "order.created"
+	EventOrderPaid      = "order.paid"
+	EventOrderCancelled = "order.cancelled"
+	EventOrderExpired   = "order.expired"
+	EventOrderRejected  = "order.rejected"
+	EventOrderRefunded  = "order.refunded"
 )
 
+var publishConfirmTimeout = time.Duration(viper.GetInt("rabbitmq.publish.confirm-timeout")) * time.Second
+
 type RoutingType string
 
 const (
@@ -24,14 +30,34 @@ const (
 )
 
 type PublishEventReq struct {
-	Channel  *amqp091.Channel
-	Routing  RoutingType
-	Queue    string
-	Exchange string
-	Body     any
+	Publisher Publisher
+	Routing   RoutingType
+	Queue     string
+	Exchange  string
+	// Body is the payload, PublishEvent wraps it in an eventpb.Envelope typed by the queue or exchange name
+	// and encodes it as configured by EncodingFor.
+	Body any
+	// Producer is the service name recorded in the envelope.
+	Producer string
+	// OccurredAt is when the event was raised, now when zero.
+	OccurredAt time.Time
+	// MessageID lets consumers drop duplicates, a random one is used when empty.
+	// Pass a stable id when the same event may be published more than once.
+	MessageID string
+	// Mandatory makes the broker return the message when no queue is bound to it,
+	// PublishEvent then fails with an unroutable PublishError.
+	Mandatory bool
+	// ConfirmTimeout bounds the wait for the broker ack, rabbitmq.publish.confirm-timeout when zero.
+	ConfirmTimeout time.Duration
 }
 
 func PublishEvent(ctx context.Context, p *PublishEventReq) (err error) {
+	if p.MessageID == "" {
+		p.MessageID = uuid.NewString()
+	}
+	if p.OccurredAt.IsZero() {
+		p.OccurredAt = time.Now()
+	}
 	_, dlog := logPublishing(ctx, p)
 	defer dlog(&err)
 
@@ -54,62 +80,83 @@ func PublishEvent(ctx context.Context, p *PublishEventReq) (err error) {
 }
 
 func direct(ctx context.Context, p *PublishEventReq) (err error) {
-	if _, err = p.Channel.QueueDeclare(p.Queue, true, false, false, false, nil); err != nil {
-		return
-	}
-
-	jsonBody, err := json.Marshal(p.Body)
+	msg, err := newMessage(ctx, p, p.Queue)
 	if err != nil {
 		return
 	}
-
-	return doPublish(ctx, p.Channel, p.Exchange, p.Queue, false, false, amqp091.Publishing{
-		ContentType:  "application/json",
-		DeliveryMode: amqp091.Persistent,
-		Body:         jsonBody,
-		Headers:      InjectRabbitMQHeaders(ctx),
-	})
+	return doPublish(ctx, p, p.Exchange, p.Queue, msg)
 }
 
 func fout(ctx context.Context, p *PublishEventReq) (err error) {
-	jsonBody, err := json.Marshal(p.Body)
+	msg, err := newMessage(ctx, p, p.Exchange)
 	if err != nil {
 		return
 	}
+	return doPublish(ctx, p, p.Exchange, "", msg)
+}
 
-	return doPublish(ctx, p.Channel, p.Exchange, "", false, false, amqp091.Publishing{
-		ContentType:  "application/json",
-		DeliveryMode: amqp091.Persistent,
-		Body:         jsonBody,
-		Headers:      InjectRabbitMQHeaders(ctx),
-	})
+// eventType 是 direct 的队列名或 fanout 的 exchange 名
+func newMessage(ctx context.Context, p *PublishEventReq, eventType string) (*Message, error) {
+	env, err := NewEnvelope(eventType, p.MessageID, p.Producer, p.OccurredAt, p.Body)
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := marshalEnvelope(env, EncodingFor(eventType))
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID:          p.MessageID,
+		Key:         env.CorrelationID,
+		Type:        eventType,
+		Producer:    p.Producer,
+		ContentType: contentType,
+		Timestamp:   p.OccurredAt,
+		Headers:     InjectRabbitMQHeaders(ctx),
+		Body:        body,
+	}, nil
 }
 
-func doPublish(ctx context.Context, ch *amqp091.Channel, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) error {
-	if err := ch.PublishWithContext(ctx, exchange, key, mandatory, immediate, msg); err != nil {
-		logrus.WithContext(ctx).WithFields(logrus.Fields{
-			"exchange": exchange,
-			"key":      key,
-			"q_msg":    msg,
-		}).Warn("Publish event fail")
-		return err
+func doPublish(ctx context.Context, p *PublishEventReq, exchange, key string, msg *Message) (err error) {
+	defer func() {
+		if err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"exchange": exchange,
+				"key":      key,
+				"msg_id":   msg.ID,
+				"err":      err.Error(),
+			}).Warn("Publish event fail")
+		}
+	}()
+
+	timeout := p.ConfirmTimeout
+	if timeout <= 0 {
+		timeout = publishConfirmTimeout
 	}
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return p.Publisher.Publish(ctx, exchange, key, p.Mandatory, msg)
 }
 
 func check(p *PublishEventReq) error {
-	if p.Channel == nil {
-		return errors.New("nil channel")
+	if p.Publisher == nil {
+		return errors.New("nil publisher")
+	}
+	if p.Producer == "" {
+		return errors.New("empty producer")
 	}
 	return nil
 }
 
 func logPublishing(ctx context.Context, p *PublishEventReq) (logrus.Fields, func(*error)) {
 	fields := logrus.Fields{
-		"queue":    p.Queue,
-		"routing":  p.Routing,
-		"exchange": p.Exchange,
-		"body":     util.MarshalStringWithoutErr(p.Body),
+		"queue":     p.Queue,
+		"routing":   p.Routing,
+		"exchange":  p.Exchange,
+		"mandatory": p.Mandatory,
+		"msg_id":    p.MessageID,
+		"producer":  p.Producer,
+		"body":      util.MarshalStringWithoutErr(p.Body),
 	}
 	start := time.Now()
 	return fields, func(err *error) {
@@ -0,0 +1,376 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	kafkaMessageIDHeader   = "message-id"
+	kafkaTypeHeader        = "type"
+	kafkaProducerHeader    = "producer"
+	kafkaContentTypeHeader = "content-type"
+	// 重试的 record 到这个时间 (unix 毫秒) 之后才处理
+	kafkaRetryAtHeader = "x-retry-at"
+
+	kafkaDLQTopic = "dlq"
+)
+
+// kafkaWriter and kafkaReader are the parts of kafka-go the adapter uses, tests swap in an in-memory log.
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type kafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+/*
+Kafka 是 Publisher 和 Subscriber 的 kafka 实现.
+事件类型就是 topic, record 的 key 是 Message.Key (订单 id), 同一订单的事件落在同一个分区里, 按发布顺序消费.
+每个服务一个 consumer group, 同一服务的多个实例分摊分区; 一个分区的 record 逐条处理, Ack / Nack 后提交 offset.
+Retry 把 record 写到本 group 的重试 topic retry.<group>.<topic> 并带上 x-retry-at, 第 MaxRetry 次写到 dlq.
+kafka 没有路由, mandatory 不起作用.
+*/
+type Kafka struct {
+	Group    string
+	MaxRetry int64
+	Backoff  func(retryCnt int64) time.Duration
+
+	writer    kafkaWriter
+	newReader func(topic, group string) kafkaReader
+}
+
+func NewKafka(brokers []string, group string) *Kafka {
+	if len(brokers) == 0 {
+		panic("empty kafka brokers")
+	}
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// 每次发布都同步等 ack, 不用攒批
+		BatchTimeout:           10 * time.Millisecond,
+		AllowAutoTopicCreation: true,
+	}
+	return newKafka(group, writer, func(topic, group string) kafkaReader {
+		return kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			GroupID:     group,
+			Topic:       topic,
+			StartOffset: kafka.FirstOffset,
+		})
+	})
+}
+
+func newKafka(group string, writer kafkaWriter, newReader func(topic, group string) kafkaReader) *Kafka {
+	if group == "" {
+		panic("empty consumer group")
+	}
+	return &Kafka{
+		Group:     group,
+		MaxRetry:  maxRetryCnt,
+		Backoff:   retryDelay,
+		writer:    writer,
+		newReader: newReader,
+	}
+}
+
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
+
+// impl Publisher, direct 事件的 exchange 为空, topic 是 key
+func (k *Kafka) Publish(ctx context.Context, exchange, key string, _ bool, msg *Message) error {
+	topic := exchange
+	if topic == "" {
+		topic = key
+	}
+	return k.write(ctx, topic, msg)
+}
+
+func (k *Kafka) write(ctx context.Context, topic string, msg *Message) error {
+	err := k.writer.WriteMessages(ctx, toRecord(ctx, topic, msg))
+	if err == nil {
+		return nil
+	}
+	reason := PublishNacked
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		reason = PublishUnconfirmed
+	}
+	return &PublishError{
+		Reason:     reason,
+		Exchange:   topic,
+		RoutingKey: msg.Key,
+		MessageID:  msg.ID,
+		Err:        err,
+	}
+}
+
+// impl Subscriber, 同时消费事件 topic 和本 group 的重试 topic
+func (k *Kafka) Subscribe(ctx context.Context, sub Subscription, handler Handler) error {
+	if handler == nil {
+		panic("nil handler")
+	}
+	topic := sub.Exchange
+	if topic == "" {
+		topic = sub.Queue
+	}
+	queue := sub.Queue
+	if queue == "" {
+		queue = topic
+	}
+
+	topics := []string{topic, k.retryTopic(topic)}
+	errs := make(chan error, len(topics))
+	for _, t := range topics {
+		go func() {
+			errs <- k.consume(ctx, t, topic, queue, handler)
+		}()
+	}
+	var err error
+	for range topics {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (k *Kafka) consume(ctx context.Context, topic, origin, queue string, handler Handler) error {
+	r := k.newReader(topic, k.Group)
+	defer func() {
+		_ = r.Close()
+	}()
+	logrus.WithFields(logrus.Fields{
+		"topic": topic,
+		"group": k.Group,
+	}).Info("Kafka consumer started")
+
+	for {
+		rec, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		// 没提交 offset, 重启后会再收到
+		if !waitRetryAt(ctx, rec) {
+			return nil
+		}
+		handler(&kafkaDelivery{
+			k:      k,
+			r:      r,
+			rec:    rec,
+			origin: origin,
+			queue:  queue,
+			msg:    fromRecord(rec),
+		})
+	}
+}
+
+func (k *Kafka) retryTopic(topic string) string {
+	return fmt.Sprintf("retry.%s.%s", k.Group, topic)
+}
+
+func waitRetryAt(ctx context.Context, rec kafka.Message) bool {
+	for _, h := range rec.Headers {
+		if h.Key != kafkaRetryAtHeader {
+			continue
+		}
+		at, err := strconv.ParseInt(string(h.Value), 10, 64)
+		if err != nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(time.Until(time.UnixMilli(at))):
+		}
+	}
+	return true
+}
+
+// impl Delivery
+type kafkaDelivery struct {
+	k       *Kafka
+	r       kafkaReader
+	rec     kafka.Message
+	origin  string // 事件 topic, 重试 topic 里的 record 也记原来的
+	queue   string
+	msg     *Message
+	settled atomic.Bool
+}
+
+func (d *kafkaDelivery) Message() *Message {
+	return d.msg
+}
+
+func (d *kafkaDelivery) Queue() string {
+	return d.queue
+}
+
+func (d *kafkaDelivery) Ack() error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return d.r.CommitMessages(context.Background(), d.rec)
+}
+
+// Nack 提交 offset 跳过这条 record
+func (d *kafkaDelivery) Nack() error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return d.r.CommitMessages(context.Background(), d.rec)
+}
+
+// Retry 写不进重试 topic 时不提交 offset, 分区重新分配后会再收到
+func (d *kafkaDelivery) Retry(ctx context.Context) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	msg := cloneMessage(d.msg)
+	retryCnt, _ := msg.Headers[amqpRetryHeaderKey].(int64)
+	retryCnt++
+	msg.Headers[amqpRetryHeaderKey] = retryCnt
+	delete(msg.Headers, kafkaRetryAtHeader)
+
+	topic := kafkaDLQTopic
+	if retryCnt < d.k.MaxRetry {
+		topic = d.k.retryTopic(d.origin)
+		msg.Headers[kafkaRetryAtHeader] = strconv.FormatInt(time.Now().Add(d.k.Backoff(retryCnt)).UnixMilli(), 10)
+		logrus.WithFields(logrus.Fields{
+			"retry_msg_id": msg.ID,
+			"retry_cnt":    retryCnt,
+			"retry_topic":  topic,
+		}).Warn("Retrying")
+	} else {
+		msg.Headers[amqpOriginalExchangeHeaderKey] = d.origin
+		msg.Timestamp = time.Now()
+	}
+	if err := d.k.write(ctx, topic, msg); err != nil {
+		return err
+	}
+	return d.r.CommitMessages(context.Background(), d.rec)
+}
+
+// impl propagation.TextMapCarrier
+type KafkaHeaderCarrier []kafka.Header
+
+func (c *KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *KafkaHeaderCarrier) Set(key, value string) {
+	for i := range *c {
+		if (*c)[i].Key == key {
+			(*c)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c = append(*c, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c *KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c))
+	for i, h := range *c {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+func InjectKafkaHeaders(ctx context.Context) []kafka.Header {
+	var carrier KafkaHeaderCarrier
+	otel.GetTextMapPropagator().Inject(ctx, &carrier)
+	return carrier
+}
+
+func ExtractKafkaHeaders(ctx context.Context, headers []kafka.Header) context.Context {
+	carrier := KafkaHeaderCarrier(headers)
+	return otel.GetTextMapPropagator().Extract(ctx, &carrier)
+}
+
+// 消息自带的 header (发布时注入的 trace context, 重试次数) 优先于 ctx 中的
+func toRecord(ctx context.Context, topic string, msg *Message) kafka.Message {
+	headers := KafkaHeaderCarrier(InjectKafkaHeaders(ctx))
+	for _, key := range slices.Sorted(maps.Keys(msg.Headers)) {
+		switch v := msg.Headers[key].(type) {
+		case string:
+			headers.Set(key, v)
+		case []byte:
+			headers.Set(key, string(v))
+		default:
+			headers.Set(key, fmt.Sprint(v))
+		}
+	}
+	headers.Set(kafkaMessageIDHeader, msg.ID)
+	headers.Set(kafkaTypeHeader, msg.Type)
+	headers.Set(kafkaProducerHeader, msg.Producer)
+	headers.Set(kafkaContentTypeHeader, msg.ContentType)
+
+	return kafka.Message{
+		Topic:   topic,
+		Key:     []byte(msg.Key),
+		Value:   msg.Body,
+		Headers: headers,
+		Time:    msg.Timestamp,
+	}
+}
+
+func fromRecord(rec kafka.Message) *Message {
+	msg := &Message{
+		Key:       string(rec.Key),
+		Timestamp: rec.Time,
+		Headers:   make(map[string]any, len(rec.Headers)),
+		Body:      rec.Value,
+	}
+	for _, h := range rec.Headers {
+		v := string(h.Value)
+		switch h.Key {
+		case kafkaMessageIDHeader:
+			msg.ID = v
+		case kafkaTypeHeader:
+			msg.Type = v
+		case kafkaProducerHeader:
+			msg.Producer = v
+		case kafkaContentTypeHeader:
+			msg.ContentType = v
+		case amqpRetryHeaderKey:
+			n, _ := strconv.ParseInt(v, 10, 64)
+			msg.Headers[h.Key] = n
+		default:
+			msg.Headers[h.Key] = v
+		}
+	}
+	return msg
+}
+
+// WithKafka 在 kafka.enabled 时把 kafka.events 中的事件交给 kafka, 其余的仍然走 b.
+func WithKafka(b Broker, group string) (Broker, func() error) {
+	if !viper.GetBool("kafka.enabled") {
+		return b, func() error { return nil }
+	}
+	k := NewKafka(viper.GetStringSlice("kafka.brokers"), group)
+	return NewMux(b).Route(k, viper.GetStringSlice("kafka.events")...), k.Close
+}
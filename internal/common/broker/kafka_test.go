@@ -0,0 +1,210 @@
+package broker
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// memLog 是测试用的 kafka, 每个 topic 固定几个分区, 和 kafka.Writer 一样按 key 的 hash 选分区
+type memLog struct {
+	partitions int
+
+	mu     sync.Mutex
+	topics map[string][][]kafka.Message
+}
+
+func newMemLog(partitions int) *memLog {
+	return &memLog{
+		partitions: partitions,
+		topics:     make(map[string][][]kafka.Message),
+	}
+}
+
+func (l *memLog) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	parts := make([]int, l.partitions)
+	for i := range parts {
+		parts[i] = i
+	}
+	for _, msg := range msgs {
+		if l.topics[msg.Topic] == nil {
+			l.topics[msg.Topic] = make([][]kafka.Message, l.partitions)
+		}
+		msg.Partition = (&kafka.Hash{}).Balance(msg, parts...)
+		msg.Offset = int64(len(l.topics[msg.Topic][msg.Partition]))
+		l.topics[msg.Topic][msg.Partition] = append(l.topics[msg.Topic][msg.Partition], msg)
+	}
+	return nil
+}
+
+func (l *memLog) Close() error {
+	return nil
+}
+
+func (l *memLog) records(topic string) [][]kafka.Message {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.topics[topic]
+}
+
+func (l *memLog) newReader(topic, _ string) kafkaReader {
+	return &memReader{log: l, topic: topic, next: make([]int64, l.partitions)}
+}
+
+// memReader 是 group 里唯一的成员, 读所有分区
+type memReader struct {
+	log   *memLog
+	topic string
+	next  []int64
+}
+
+func (r *memReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.log.mu.Lock()
+		for p, recs := range r.log.topics[r.topic] {
+			if r.next[p] < int64(len(recs)) {
+				rec := recs[r.next[p]]
+				r.next[p]++
+				r.log.mu.Unlock()
+				return rec, nil
+			}
+		}
+		r.log.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (r *memReader) CommitMessages(_ context.Context, _ ...kafka.Message) error {
+	return nil
+}
+
+func (r *memReader) Close() error {
+	return nil
+}
+
+func newTestKafka(l *memLog) *Kafka {
+	k := newKafka("test", l, l.newReader)
+	k.MaxRetry = 3
+	k.Backoff = func(int64) time.Duration { return time.Millisecond }
+	return k
+}
+
+func TestKafka_SameOrderSamePartition(t *testing.T) {
+	l := newMemLog(4)
+	k := newTestKafka(l)
+	orders := []string{"order-a", "order-b", "order-c"}
+	for i := range 5 {
+		for _, o := range orders {
+			require.NoError(t, k.Publish(context.Background(), EventOrderPaid, "", true, &Message{
+				ID:  o + "-" + strconv.Itoa(i),
+				Key: o,
+			}))
+		}
+	}
+
+	for _, o := range orders {
+		var ids []string
+		partition := -1
+		for p, recs := range l.records(EventOrderPaid) {
+			for _, rec := range recs {
+				if string(rec.Key) != o {
+					continue
+				}
+				if partition == -1 {
+					partition = p
+				}
+				assert.Equal(t, partition, p, "events of %s span partitions", o)
+				ids = append(ids, fromRecord(rec).ID)
+			}
+		}
+		assert.Equal(t, []string{o + "-0", o + "-1", o + "-2", o + "-3", o + "-4"}, ids)
+	}
+}
+
+func TestKafka_TraceHeaders(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(prev)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	rec := toRecord(ctx, EventOrderPaid, &Message{ID: "1", Key: "order-a", Type: EventOrderPaid, ContentType: ContentTypeJSON})
+	got := trace.SpanContextFromContext(ExtractKafkaHeaders(context.Background(), rec.Headers))
+	assert.Equal(t, sc.TraceID(), got.TraceID())
+	assert.Equal(t, sc.SpanID(), got.SpanID())
+
+	msg := fromRecord(rec)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, "order-a", msg.Key)
+	assert.Equal(t, EventOrderPaid, msg.Type)
+	assert.Equal(t, ContentTypeJSON, msg.ContentType)
+}
+
+func TestKafka_RetryThenDLQ(t *testing.T) {
+	l := newMemLog(1)
+	k := newTestKafka(l)
+	ctx, cancel := context.WithCancel(context.Background())
+	got := make(chan *Message, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- k.Subscribe(ctx, Subscription{Queue: "q", Exchange: EventOrderPaid}, func(d Delivery) {
+			got <- d.Message()
+			assert.NoError(t, d.Retry(ctx))
+			assert.ErrorIs(t, d.Ack(), ErrAlreadySettled)
+		})
+	}()
+
+	require.NoError(t, k.Publish(ctx, EventOrderPaid, "", true, &Message{ID: "1", Key: "order-a"}))
+	// 第一次投递加上 MaxRetry-1 次重试
+	for range k.MaxRetry {
+		assert.Equal(t, "1", receive(t, got).ID)
+	}
+	require.Eventually(t, func() bool {
+		recs := l.records(kafkaDLQTopic)
+		return len(recs) == 1 && len(recs[0]) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Len(t, l.records(k.retryTopic(EventOrderPaid))[0], int(k.MaxRetry-1))
+	dead := fromRecord(l.records(kafkaDLQTopic)[0][0])
+	assert.Equal(t, int64(3), dead.Headers[amqpRetryHeaderKey])
+	assert.Equal(t, EventOrderPaid, dead.Headers[amqpOriginalExchangeHeaderKey])
+	assert.Equal(t, "order-a", dead.Key)
+}
+
+func TestMux_Route(t *testing.T) {
+	l := newMemLog(1)
+	m := newTestMemory()
+	mux := NewMux(m).Route(newTestKafka(l), EventOrderCreated)
+
+	require.NoError(t, mux.Publish(context.Background(), "", EventOrderCreated, true, &Message{ID: "1", Key: "order-a"}))
+	assert.Len(t, l.records(EventOrderCreated)[0], 1)
+
+	got, stop := subscribe(t, m, Subscription{Queue: "q", Exchange: EventOrderPaid}, func(d Delivery) { _ = d.Ack() })
+	defer stop()
+	require.NoError(t, mux.Publish(context.Background(), EventOrderPaid, "", true, &Message{ID: "2"}))
+	assert.Equal(t, "2", receive(t, got).ID)
+	assert.Nil(t, l.records(EventOrderPaid))
+}
@@ -0,0 +1,246 @@
+package broker
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+/*
+Memory 是进程内的 Publisher 和 Subscriber, 给单元测试和所有服务跑在一个进程里的开发模式用.
+有名字的 exchange 都按 fanout 投递给绑定的队列, 默认 exchange "" 按队列名投递; 消息只在内存里, 进程退出就没了.
+Retry 按 Backoff 延迟后放回原队列, 第 MaxRetry 次进入 DeadLetters.
+*/
+type Memory struct {
+	Workers  int
+	MaxRetry int64
+	Backoff  func(retryCnt int64) time.Duration
+
+	mu       sync.Mutex
+	queues   map[string]*memQueue
+	bindings map[string][]*memQueue // exchange -> queues
+	dead     []*Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		Workers:  viper.GetInt("rabbitmq.consumer.workers"),
+		MaxRetry: maxRetryCnt,
+		Backoff:  retryDelay,
+		queues:   make(map[string]*memQueue),
+		bindings: make(map[string][]*memQueue),
+	}
+}
+
+// impl Publisher
+func (m *Memory) Publish(ctx context.Context, exchange, key string, mandatory bool, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	var targets []*memQueue
+	if exchange == "" {
+		// 和 rabbitmq 实现一样, 直接发到队列时先声明队列
+		targets = []*memQueue{m.declare(key, false)}
+	} else {
+		targets = slices.Clone(m.bindings[exchange])
+	}
+	m.mu.Unlock()
+
+	if len(targets) == 0 {
+		if mandatory {
+			return &PublishError{
+				Reason:     PublishUnroutable,
+				Exchange:   exchange,
+				RoutingKey: key,
+				MessageID:  msg.ID,
+				ReplyText:  "NO_ROUTE",
+			}
+		}
+		return nil
+	}
+	for _, q := range targets {
+		q.push(cloneMessage(msg))
+	}
+	return nil
+}
+
+// impl Subscriber
+func (m *Memory) Subscribe(ctx context.Context, sub Subscription, handler Handler) error {
+	if handler == nil {
+		panic("nil handler")
+	}
+	m.mu.Lock()
+	q := m.declare(sub.Queue, sub.AutoDelete)
+	if sub.Exchange != "" && !slices.Contains(m.bindings[sub.Exchange], q) {
+		m.bindings[sub.Exchange] = append(m.bindings[sub.Exchange], q)
+	}
+	m.mu.Unlock()
+	if q.autoDelete {
+		defer m.delete(q)
+	}
+
+	var wg sync.WaitGroup
+	for range max(m.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msg, ok := q.pop(ctx)
+				if !ok {
+					return
+				}
+				handler(&memDelivery{m: m, q: q, msg: msg})
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// DeadLetters returns the messages that used up their retries.
+func (m *Memory) DeadLetters() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.dead)
+}
+
+// 调用方持有 m.mu, 空名字和 rabbitmq 一样生成一个
+func (m *Memory) declare(name string, autoDelete bool) *memQueue {
+	if name == "" {
+		name = "amq.gen-" + uuid.NewString()
+	}
+	if q, ok := m.queues[name]; ok {
+		return q
+	}
+	q := &memQueue{
+		name:       name,
+		autoDelete: autoDelete,
+		ready:      make(chan struct{}, 1),
+	}
+	m.queues[name] = q
+	return q
+}
+
+func (m *Memory) delete(q *memQueue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.queues, q.name)
+	for exchange, qs := range m.bindings {
+		m.bindings[exchange] = slices.DeleteFunc(qs, func(b *memQueue) bool { return b == q })
+	}
+}
+
+type memQueue struct {
+	name       string
+	autoDelete bool
+
+	mu    sync.Mutex
+	msgs  []*Message
+	ready chan struct{} // 有消息时通知一个等待的 worker
+}
+
+func (q *memQueue) push(msg *Message) {
+	q.mu.Lock()
+	q.msgs = append(q.msgs, msg)
+	q.mu.Unlock()
+	q.notify()
+}
+
+func (q *memQueue) pop(ctx context.Context) (*Message, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.msgs) > 0 {
+			msg := q.msgs[0]
+			q.msgs = q.msgs[1:]
+			more := len(q.msgs) > 0
+			q.mu.Unlock()
+			if more {
+				q.notify()
+			}
+			return msg, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *memQueue) notify() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// impl Delivery
+type memDelivery struct {
+	m       *Memory
+	q       *memQueue
+	msg     *Message
+	settled atomic.Bool
+}
+
+func (d *memDelivery) Message() *Message {
+	return d.msg
+}
+
+func (d *memDelivery) Queue() string {
+	return d.q.name
+}
+
+func (d *memDelivery) Ack() error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return nil
+}
+
+// Nack 直接丢掉, 和 rabbitmq 中 reject 且不 requeue 一样
+func (d *memDelivery) Nack() error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return nil
+}
+
+func (d *memDelivery) Retry(_ context.Context) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	msg := cloneMessage(d.msg)
+	retryCnt, _ := msg.Headers[amqpRetryHeaderKey].(int64)
+	retryCnt++
+	msg.Headers[amqpRetryHeaderKey] = retryCnt
+
+	if retryCnt >= d.m.MaxRetry {
+		msg.Timestamp = time.Now()
+		d.m.mu.Lock()
+		d.m.dead = append(d.m.dead, msg)
+		d.m.mu.Unlock()
+		return nil
+	}
+	time.AfterFunc(d.m.Backoff(retryCnt), func() {
+		d.q.push(msg)
+	})
+	return nil
+}
+
+func cloneMessage(msg *Message) *Message {
+	c := *msg
+	c.Headers = maps.Clone(msg.Headers)
+	if c.Headers == nil {
+		c.Headers = map[string]any{}
+	}
+	return &c
+}
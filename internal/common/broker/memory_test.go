@@ -0,0 +1,102 @@
+package broker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemory() *Memory {
+	m := NewMemory()
+	m.Workers = 1
+	m.MaxRetry = 3
+	m.Backoff = func(int64) time.Duration { return time.Millisecond }
+	return m
+}
+
+// subscribe 在后台消费, 返回收到的消息和停止消费的函数
+func subscribe(t *testing.T, m *Memory, sub Subscription, handle func(d Delivery)) (<-chan *Message, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	got := make(chan *Message, 16)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, m.Subscribe(ctx, sub, func(d Delivery) {
+			got <- d.Message()
+			handle(d)
+		}))
+	}()
+	// 等队列声明并绑定好
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		q, ok := m.queues[sub.Queue]
+		return ok && (sub.Exchange == "" || slices.Contains(m.bindings[sub.Exchange], q))
+	}, time.Second, time.Millisecond)
+	return got, func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func receive(t *testing.T, got <-chan *Message) *Message {
+	select {
+	case msg := <-got:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestMemory_Fanout(t *testing.T) {
+	m := newTestMemory()
+	ack := func(d Delivery) { assert.NoError(t, d.Ack()) }
+	a, stopA := subscribe(t, m, Subscription{Queue: "a." + EventOrderPaid, Exchange: EventOrderPaid}, ack)
+	defer stopA()
+	b, stopB := subscribe(t, m, Subscription{Queue: "b." + EventOrderPaid, Exchange: EventOrderPaid}, ack)
+	defer stopB()
+
+	require.NoError(t, m.Publish(context.Background(), EventOrderPaid, "", true, &Message{ID: "1", Body: []byte("x")}))
+	assert.Equal(t, "1", receive(t, a).ID)
+	assert.Equal(t, "1", receive(t, b).ID)
+}
+
+func TestMemory_Unroutable(t *testing.T) {
+	m := newTestMemory()
+	err := m.Publish(context.Background(), EventOrderPaid, "", true, &Message{ID: "1"})
+	assert.True(t, IsUnroutable(err))
+	assert.NoError(t, m.Publish(context.Background(), EventOrderPaid, "", false, &Message{ID: "1"}))
+}
+
+func TestMemory_DirectBeforeSubscribe(t *testing.T) {
+	m := newTestMemory()
+	require.NoError(t, m.Publish(context.Background(), "", EventOrderCreated, true, &Message{ID: "1"}))
+
+	got, stop := subscribe(t, m, Subscription{Queue: EventOrderCreated}, func(d Delivery) { _ = d.Ack() })
+	defer stop()
+	assert.Equal(t, "1", receive(t, got).ID)
+}
+
+func TestMemory_RetryThenDeadLetter(t *testing.T) {
+	m := newTestMemory()
+	got, stop := subscribe(t, m, Subscription{Queue: "q", Exchange: EventOrderPaid}, func(d Delivery) {
+		assert.NoError(t, d.Retry(context.Background()))
+		assert.ErrorIs(t, d.Ack(), ErrAlreadySettled)
+	})
+	defer stop()
+
+	require.NoError(t, m.Publish(context.Background(), EventOrderPaid, "", true, &Message{ID: "1"}))
+	// 第一次投递加上 MaxRetry-1 次重试
+	for range m.MaxRetry {
+		assert.Equal(t, "1", receive(t, got).ID)
+	}
+	require.Eventually(t, func() bool { return len(m.DeadLetters()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(3), m.DeadLetters()[0].Headers[amqpRetryHeaderKey])
+}
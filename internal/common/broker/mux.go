@@ -0,0 +1,58 @@
+package broker
+
+import "context"
+
+/*
+Mux 按事件类型选择 broker, 没有 Route 过的事件走 Default.
+direct 事件的类型是队列名, fanout 事件的类型是 exchange 名.
+*/
+type Mux struct {
+	Default Broker
+	routes  map[string]Broker
+}
+
+func NewMux(def Broker) *Mux {
+	if def == nil {
+		panic("nil default broker")
+	}
+	return &Mux{
+		Default: def,
+		routes:  make(map[string]Broker),
+	}
+}
+
+// Route sends the events to b, call it before publishing or subscribing.
+func (m *Mux) Route(b Broker, events ...string) *Mux {
+	if b == nil {
+		panic("nil broker")
+	}
+	for _, e := range events {
+		m.routes[e] = b
+	}
+	return m
+}
+
+// impl Publisher
+func (m *Mux) Publish(ctx context.Context, exchange, key string, mandatory bool, msg *Message) error {
+	event := exchange
+	if event == "" {
+		event = key
+	}
+	return m.pick(event).Publish(ctx, exchange, key, mandatory, msg)
+}
+
+// impl Subscriber
+func (m *Mux) Subscribe(ctx context.Context, sub Subscription, handler Handler) error {
+	event := sub.Exchange
+	if event == "" {
+		event = sub.Queue
+	}
+	return m.pick(event).Subscribe(ctx, sub, handler)
+}
+
+func (m *Mux) pick(event string) Broker {
+	if b, ok := m.routes[event]; ok {
+		return b
+	}
+	return m.Default
+}
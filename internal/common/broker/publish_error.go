@@ -0,0 +1,58 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+)
+
+type PublishFailure string
+
+const (
+	// PublishUnroutable means a mandatory message matched no queue and was returned by the broker.
+	PublishUnroutable PublishFailure = "unroutable"
+	// PublishNacked means the broker refused to take responsibility for the message.
+	PublishNacked PublishFailure = "nacked"
+	// PublishUnconfirmed means no confirm arrived before the timeout, the message may or may not be stored.
+	PublishUnconfirmed PublishFailure = "unconfirmed"
+)
+
+// PublishError is returned by PublishEvent when the broker did not accept the message.
+type PublishError struct {
+	Reason     PublishFailure
+	Exchange   string
+	RoutingKey string
+	MessageID  string
+	ReplyText  string // set for unroutable messages
+	Err        error
+}
+
+func (e *PublishError) Error() string {
+	msg := fmt.Sprintf("publish %s exchange=%q key=%q msg_id=%s", e.Reason, e.Exchange, e.RoutingKey, e.MessageID)
+	if e.ReplyText != "" {
+		msg += " reply=" + e.ReplyText
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+func IsUnroutable(err error) bool {
+	return publishFailure(err) == PublishUnroutable
+}
+
+func IsNacked(err error) bool {
+	return publishFailure(err) == PublishNacked
+}
+
+func publishFailure(err error) PublishFailure {
+	var pe *PublishError
+	if errors.As(err, &pe) {
+		return pe.Reason
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishError(t *testing.T) {
+	unroutable := &PublishError{
+		Reason:    PublishUnroutable,
+		Exchange:  EventOrderPaid,
+		MessageID: "m1",
+		ReplyText: "NO_ROUTE",
+	}
+	assert.Equal(t, `publish unroutable exchange="order.paid" key="" msg_id=m1 reply=NO_ROUTE`, unroutable.Error())
+	assert.True(t, IsUnroutable(unroutable))
+	assert.False(t, IsNacked(unroutable))
+	// 包装过也能识别
+	assert.True(t, IsUnroutable(fmt.Errorf("publish order.paid: %w", unroutable)))
+
+	nacked := &PublishError{Reason: PublishNacked, RoutingKey: EventOrderCreated, MessageID: "m2"}
+	assert.True(t, IsNacked(nacked))
+	assert.False(t, IsUnroutable(nacked))
+
+	unconfirmed := &PublishError{Reason: PublishUnconfirmed, MessageID: "m3", Err: context.DeadlineExceeded}
+	assert.ErrorIs(t, unconfirmed, context.DeadlineExceeded)
+	assert.Contains(t, unconfirmed.Error(), "publish unconfirmed")
+	assert.Contains(t, unconfirmed.Error(), context.DeadlineExceeded.Error())
+	assert.False(t, IsUnroutable(unconfirmed))
+	assert.False(t, IsNacked(unconfirmed))
+
+	assert.False(t, IsUnroutable(errors.New("boom")))
+	assert.False(t, IsNacked(nil))
+}
+
+func TestConnection_TakeReturned(t *testing.T) {
+	c := &Connection{returned: make(map[string]returnedMsg)}
+	returns := make(chan amqp.Return, 4)
+	returns <- amqp.Return{MessageId: "m1", ReplyText: "NO_ROUTE"}
+	returns <- amqp.Return{MessageId: "m2", ReplyText: "NO_ROUTE"}
+
+	// 按 MessageId 取, 其他的留给各自的 publish
+	r, ok := c.takeReturned(returns, "m2")
+	assert.True(t, ok)
+	assert.Equal(t, "m2", r.MessageId)
+	assert.Len(t, c.returned, 1)
+
+	_, ok = c.takeReturned(returns, "m3")
+	assert.False(t, ok)
+
+	r, ok = c.takeReturned(returns, "m1")
+	assert.True(t, ok)
+	assert.Equal(t, "NO_ROUTE", r.ReplyText)
+	assert.Empty(t, c.returned)
+
+	// publish 超时后才到的退回消息过一段时间被清掉
+	c.returned["late"] = returnedMsg{Return: amqp.Return{MessageId: "late"}, at: time.Now().Add(-2 * returnedKeep)}
+	_, ok = c.takeReturned(returns, "m4")
+	assert.False(t, ok)
+	assert.Empty(t, c.returned)
+
+	// channel 关闭后不会阻塞
+	close(returns)
+	_, ok = c.takeReturned(returns, "m1")
+	assert.False(t, ok)
+}
@@ -2,53 +2,32 @@ package broker
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	_ "github.com/peiyouyao/gorder/common/config"
 	amqp "github.com/rabbitmq/amqp091-go"
-	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
 )
 
 const (
-	dlx                = "dlx"
-	dlq                = "dlq"
-	amqpRetryHeaderKey = "x-retry-count"
+	dlx = "dlx"
+	dlq = "dlq"
 )
 
-var (
-	maxRetryCnt = viper.GetInt64("rabbitmq.max-retry")
-)
-
-func Connect(user, password, host, port string) (*amqp.Channel, func() error) {
-	address := fmt.Sprintf("amqp://%s:%s@%s:%s", user, password, host, port)
-	conn, err := amqp.Dial(address)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	ch, err := conn.Channel()
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	err = ch.ExchangeDeclare(EventOrderCreated, "direct", true, false, false, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	err = ch.ExchangeDeclare(EventOrderPaid, "fanout", true, false, false, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	err = createDLX(ch)
-	if err != nil {
-		logrus.Fatal(err)
+// 每次(重)连后声明, 队列由各消费者在 Listen 里声明
+func declareTopology(ch *amqp.Channel) (err error) {
+	for _, exchange := range []struct{ name, kind string }{
+		{EventOrderCreated, "direct"},
+		{EventOrderPaid, "fanout"},
+		{EventOrderCancelled, "fanout"},
+		{EventOrderExpired, "fanout"},
+		{EventOrderRejected, "fanout"},
+		{EventOrderRefunded, "fanout"},
+	} {
+		if err = ch.ExchangeDeclare(exchange.name, exchange.kind, true, false, false, false, nil); err != nil {
+			return
+		}
 	}
-	return ch, conn.Close
+	return createDLX(ch)
 }
 
 func createDLX(ch *amqp.Channel) (err error) {
@@ -71,49 +50,6 @@ func createDLX(ch *amqp.Channel) (err error) {
 	return
 }
 
-// confirm consumer **receive** a message
-func HandleRetry(ctx context.Context, ch *amqp.Channel, d *amqp.Delivery) (err error) {
-	start := time.Now()
-	defer func() {
-		if err != nil {
-			logrus.WithField("q_msg_id", d.MessageId).Error("Msg move to DLQ Retry fail")
-		} else {
-			logrus.WithField("retry_time_cost", time.Since(start)).Info("Retry ok")
-		}
-	}()
-
-	if d.Headers == nil {
-		d.Headers = amqp.Table{}
-	}
-	retryCnt, ok := d.Headers[amqpRetryHeaderKey].(int64)
-	if !ok {
-		retryCnt = 0
-	}
-	retryCnt++
-	d.Headers[amqpRetryHeaderKey] = retryCnt
-
-	publishing := amqp.Publishing{
-		Headers:      d.Headers,
-		ContentType:  "application/json",
-		Body:         d.Body,
-		DeliveryMode: amqp.Persistent,
-	}
-
-	if retryCnt >= maxRetryCnt {
-		err = ch.PublishWithContext(ctx, "", dlq, false, false, publishing)
-		return
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"retry_msg_id": d.MessageId,
-		"retry_cnt":    retryCnt,
-	}).Warn("Retrying")
-
-	time.Sleep(time.Second * time.Duration(retryCnt))
-	err = ch.PublishWithContext(ctx, d.Exchange, d.RoutingKey, false, false, publishing)
-	return
-}
-
 // impl propagation.TextMapCarrier
 type RabbitMQHeaderCarrier map[string]interface{}
 
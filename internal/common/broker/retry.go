@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const amqpRetryHeaderKey = "x-retry-count"
+
+var (
+	maxRetryCnt = viper.GetInt64("rabbitmq.max-retry")
+
+	retryInitialDelay = time.Duration(viper.GetInt("rabbitmq.retry.initial-delay")) * time.Second
+	retryMaxDelay     = time.Duration(viper.GetInt("rabbitmq.retry.max-delay")) * time.Second
+	retryMultiplier   = viper.GetFloat64("rabbitmq.retry.multiplier")
+	retryJitter       = viper.GetFloat64("rabbitmq.retry.jitter")
+)
+
+/*
+handleRetry 把从 queue 收到的消息放进本次重试对应的延迟队列, 不阻塞消费协程.
+延迟队列没有消费者, 消息 TTL 到期后经默认 exchange 被 dead-letter 回 queue,
+不经过原来的 fanout exchange, 绑定在上面的其他队列不会再收到一次.
+超过 rabbitmq.max-retry 的消息进入 dlq. 两种都用 publish 发布, Connection 下会等 publisher confirm.
+*/
+func handleRetry(ctx context.Context, ch *amqp.Channel, publish publishFunc, d *amqp.Delivery, queue string) (err error) {
+	start := time.Now()
+	defer func() {
+		if err != nil {
+			logrus.WithField("q_msg_id", d.MessageId).Error("Msg move to DLQ Retry fail")
+		} else {
+			logrus.WithField("retry_time_cost", time.Since(start)).Info("Retry ok")
+		}
+	}()
+
+	retryCnt, ok := d.Headers[amqpRetryHeaderKey].(int64)
+	if !ok {
+		retryCnt = 0
+	}
+	retryCnt++
+	d.Headers[amqpRetryHeaderKey] = retryCnt
+	// dlq replay 时也只发回这个队列
+	d.Headers[amqpOriginalExchangeHeaderKey] = ""
+	d.Headers[amqpOriginalRoutingKeyHeaderKey] = queue
+
+	// 保留 MessageId, 消费者按它去重
+	publishing := amqp.Publishing{
+		MessageId:    d.MessageId,
+		Type:         d.Type,
+		AppId:        d.AppId,
+		Headers:      d.Headers,
+		ContentType:  d.ContentType,
+		Body:         d.Body,
+		DeliveryMode: amqp.Persistent,
+	}
+
+	if retryCnt >= maxRetryCnt {
+		publishing.Timestamp = time.Now()
+		err = publish(ctx, "", dlq, true, publishing)
+		return
+	}
+
+	delay := retryDelay(retryCnt)
+	logrus.WithFields(logrus.Fields{
+		"retry_msg_id": d.MessageId,
+		"retry_cnt":    retryCnt,
+		"retry_delay":  delay,
+	}).Warn("Retrying")
+
+	q, err := declareRetryQueue(ch, queue, retryCnt)
+	if err != nil {
+		return
+	}
+	publishing.Expiration = strconv.FormatInt(delay.Milliseconds(), 10)
+	err = publish(ctx, "", q, true, publishing)
+	return
+}
+
+// 每个 (队列, 第几次重试) 一个延迟队列, 队列里消息的 TTL 只差 jitter,
+// 过期只在队头检查, 这样不会有消息被一个长得多的 TTL 挡住
+func declareRetryQueue(ch *amqp.Channel, queue string, retryCnt int64) (string, error) {
+	name := fmt.Sprintf("retry.%s.%d", queue, retryCnt)
+	_, err := ch.QueueDeclare(name, true, false, false, false, retryQueueArgs(queue))
+	return name, err
+}
+
+// 默认 exchange 是 direct 的, 按 routing key 投递给同名队列
+func retryQueueArgs(queue string) amqp.Table {
+	return amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queue,
+	}
+}
+
+// initial-delay * multiplier^(retryCnt-1), capped by max-delay, then +-jitter
+func retryDelay(retryCnt int64) time.Duration {
+	delay := float64(retryInitialDelay) * math.Pow(retryMultiplier, float64(retryCnt-1))
+	if retryMaxDelay > 0 && delay > float64(retryMaxDelay) {
+		delay = float64(retryMaxDelay)
+	}
+	delay *= 1 + retryJitter*(2*rand.Float64()-1)
+	return time.Duration(delay)
+}
@@ -0,0 +1,46 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setRetryBackoff(t *testing.T, initial, maxDelay time.Duration, multiplier, jitter float64) {
+	oldInitial, oldMax, oldMultiplier, oldJitter := retryInitialDelay, retryMaxDelay, retryMultiplier, retryJitter
+	t.Cleanup(func() {
+		retryInitialDelay, retryMaxDelay, retryMultiplier, retryJitter = oldInitial, oldMax, oldMultiplier, oldJitter
+	})
+	retryInitialDelay, retryMaxDelay, retryMultiplier, retryJitter = initial, maxDelay, multiplier, jitter
+}
+
+func TestRetryDelay(t *testing.T) {
+	setRetryBackoff(t, time.Second, 10*time.Second, 2, 0)
+	for retryCnt, want := range map[int64]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second, // capped
+		9: 10 * time.Second,
+	} {
+		assert.Equal(t, want, retryDelay(retryCnt), "retry %d", retryCnt)
+	}
+}
+
+func TestRetryDelay_Jitter(t *testing.T) {
+	setRetryBackoff(t, time.Second, 0, 2, 0.2)
+	for range 100 {
+		d := retryDelay(3)
+		assert.GreaterOrEqual(t, d, 3200*time.Millisecond)
+		assert.LessOrEqual(t, d, 4800*time.Millisecond)
+	}
+}
+
+// 延迟队列到期后只回到失败的队列, 不经过原来的 fanout exchange
+func TestRetryQueueArgs(t *testing.T) {
+	args := retryQueueArgs("stock." + EventOrderCancelled)
+	assert.Equal(t, "", args["x-dead-letter-exchange"])
+	assert.Equal(t, "stock."+EventOrderCancelled, args["x-dead-letter-routing-key"])
+}
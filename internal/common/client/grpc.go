@@ -6,6 +6,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/discovery"
 	"github.com/peiyouyao/gorder/common/genproto/orderpb"
 	"github.com/peiyouyao/gorder/common/genproto/stockpb"
@@ -62,6 +63,7 @@ func grpcDialOpts() []grpc.DialOption {
 	return []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(actor.UnaryClientInterceptor),
 	}
 }
 
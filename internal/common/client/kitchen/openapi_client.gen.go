@@ -0,0 +1,846 @@
+// Package kitchen provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package kitchen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// GetTickets request
+	GetTickets(ctx context.Context, params *GetTicketsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTicketsOrderIdBump request
+	PostTicketsOrderIdBump(ctx context.Context, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTicketsOrderIdClaimWithBody request with any body
+	PostTicketsOrderIdClaimWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTicketsOrderIdClaim(ctx context.Context, orderId string, body PostTicketsOrderIdClaimJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTicketsOrderIdItemsDoneWithBody request with any body
+	PostTicketsOrderIdItemsDoneWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTicketsOrderIdItemsDone(ctx context.Context, orderId string, body PostTicketsOrderIdItemsDoneJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTicketsOrderIdRejectWithBody request with any body
+	PostTicketsOrderIdRejectWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTicketsOrderIdReject(ctx context.Context, orderId string, body PostTicketsOrderIdRejectJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetTickets(ctx context.Context, params *GetTicketsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTicketsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdBump(ctx context.Context, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdBumpRequest(c.Server, orderId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdClaimWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdClaimRequestWithBody(c.Server, orderId, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdClaim(ctx context.Context, orderId string, body PostTicketsOrderIdClaimJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdClaimRequest(c.Server, orderId, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdItemsDoneWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdItemsDoneRequestWithBody(c.Server, orderId, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdItemsDone(ctx context.Context, orderId string, body PostTicketsOrderIdItemsDoneJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdItemsDoneRequest(c.Server, orderId, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdRejectWithBody(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdRejectRequestWithBody(c.Server, orderId, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTicketsOrderIdReject(ctx context.Context, orderId string, body PostTicketsOrderIdRejectJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTicketsOrderIdRejectRequest(c.Server, orderId, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetTicketsRequest generates requests for GetTickets
+func NewGetTicketsRequest(server string, params *GetTicketsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tickets")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostTicketsOrderIdBumpRequest generates requests for PostTicketsOrderIdBump
+func NewPostTicketsOrderIdBumpRequest(server string, orderId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tickets/%s/bump", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostTicketsOrderIdClaimRequest calls the generic PostTicketsOrderIdClaim builder with application/json body
+func NewPostTicketsOrderIdClaimRequest(server string, orderId string, body PostTicketsOrderIdClaimJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTicketsOrderIdClaimRequestWithBody(server, orderId, "application/json", bodyReader)
+}
+
+// NewPostTicketsOrderIdClaimRequestWithBody generates requests for PostTicketsOrderIdClaim with any type of body
+func NewPostTicketsOrderIdClaimRequestWithBody(server string, orderId string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tickets/%s/claim", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostTicketsOrderIdItemsDoneRequest calls the generic PostTicketsOrderIdItemsDone builder with application/json body
+func NewPostTicketsOrderIdItemsDoneRequest(server string, orderId string, body PostTicketsOrderIdItemsDoneJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTicketsOrderIdItemsDoneRequestWithBody(server, orderId, "application/json", bodyReader)
+}
+
+// NewPostTicketsOrderIdItemsDoneRequestWithBody generates requests for PostTicketsOrderIdItemsDone with any type of body
+func NewPostTicketsOrderIdItemsDoneRequestWithBody(server string, orderId string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tickets/%s/items-done", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostTicketsOrderIdRejectRequest calls the generic PostTicketsOrderIdReject builder with application/json body
+func NewPostTicketsOrderIdRejectRequest(server string, orderId string, body PostTicketsOrderIdRejectJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTicketsOrderIdRejectRequestWithBody(server, orderId, "application/json", bodyReader)
+}
+
+// NewPostTicketsOrderIdRejectRequestWithBody generates requests for PostTicketsOrderIdReject with any type of body
+func NewPostTicketsOrderIdRejectRequestWithBody(server string, orderId string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tickets/%s/reject", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetTicketsWithResponse request
+	GetTicketsWithResponse(ctx context.Context, params *GetTicketsParams, reqEditors ...RequestEditorFn) (*GetTicketsResponse, error)
+
+	// PostTicketsOrderIdBumpWithResponse request
+	PostTicketsOrderIdBumpWithResponse(ctx context.Context, orderId string, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdBumpResponse, error)
+
+	// PostTicketsOrderIdClaimWithBodyWithResponse request with any body
+	PostTicketsOrderIdClaimWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdClaimResponse, error)
+
+	PostTicketsOrderIdClaimWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdClaimJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdClaimResponse, error)
+
+	// PostTicketsOrderIdItemsDoneWithBodyWithResponse request with any body
+	PostTicketsOrderIdItemsDoneWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdItemsDoneResponse, error)
+
+	PostTicketsOrderIdItemsDoneWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdItemsDoneJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdItemsDoneResponse, error)
+
+	// PostTicketsOrderIdRejectWithBodyWithResponse request with any body
+	PostTicketsOrderIdRejectWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdRejectResponse, error)
+
+	PostTicketsOrderIdRejectWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdRejectJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdRejectResponse, error)
+}
+
+type GetTicketsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTicketsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTicketsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTicketsOrderIdBumpResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTicketsOrderIdBumpResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTicketsOrderIdBumpResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTicketsOrderIdClaimResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTicketsOrderIdClaimResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTicketsOrderIdClaimResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTicketsOrderIdItemsDoneResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTicketsOrderIdItemsDoneResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTicketsOrderIdItemsDoneResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTicketsOrderIdRejectResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTicketsOrderIdRejectResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTicketsOrderIdRejectResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetTicketsWithResponse request returning *GetTicketsResponse
+func (c *ClientWithResponses) GetTicketsWithResponse(ctx context.Context, params *GetTicketsParams, reqEditors ...RequestEditorFn) (*GetTicketsResponse, error) {
+	rsp, err := c.GetTickets(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTicketsResponse(rsp)
+}
+
+// PostTicketsOrderIdBumpWithResponse request returning *PostTicketsOrderIdBumpResponse
+func (c *ClientWithResponses) PostTicketsOrderIdBumpWithResponse(ctx context.Context, orderId string, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdBumpResponse, error) {
+	rsp, err := c.PostTicketsOrderIdBump(ctx, orderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdBumpResponse(rsp)
+}
+
+// PostTicketsOrderIdClaimWithBodyWithResponse request with arbitrary body returning *PostTicketsOrderIdClaimResponse
+func (c *ClientWithResponses) PostTicketsOrderIdClaimWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdClaimResponse, error) {
+	rsp, err := c.PostTicketsOrderIdClaimWithBody(ctx, orderId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdClaimResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTicketsOrderIdClaimWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdClaimJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdClaimResponse, error) {
+	rsp, err := c.PostTicketsOrderIdClaim(ctx, orderId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdClaimResponse(rsp)
+}
+
+// PostTicketsOrderIdItemsDoneWithBodyWithResponse request with arbitrary body returning *PostTicketsOrderIdItemsDoneResponse
+func (c *ClientWithResponses) PostTicketsOrderIdItemsDoneWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdItemsDoneResponse, error) {
+	rsp, err := c.PostTicketsOrderIdItemsDoneWithBody(ctx, orderId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdItemsDoneResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTicketsOrderIdItemsDoneWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdItemsDoneJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdItemsDoneResponse, error) {
+	rsp, err := c.PostTicketsOrderIdItemsDone(ctx, orderId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdItemsDoneResponse(rsp)
+}
+
+// PostTicketsOrderIdRejectWithBodyWithResponse request with arbitrary body returning *PostTicketsOrderIdRejectResponse
+func (c *ClientWithResponses) PostTicketsOrderIdRejectWithBodyWithResponse(ctx context.Context, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdRejectResponse, error) {
+	rsp, err := c.PostTicketsOrderIdRejectWithBody(ctx, orderId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdRejectResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTicketsOrderIdRejectWithResponse(ctx context.Context, orderId string, body PostTicketsOrderIdRejectJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTicketsOrderIdRejectResponse, error) {
+	rsp, err := c.PostTicketsOrderIdReject(ctx, orderId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTicketsOrderIdRejectResponse(rsp)
+}
+
+// ParseGetTicketsResponse parses an HTTP response from a GetTicketsWithResponse call
+func ParseGetTicketsResponse(rsp *http.Response) (*GetTicketsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetTicketsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTicketsOrderIdBumpResponse parses an HTTP response from a PostTicketsOrderIdBumpWithResponse call
+func ParsePostTicketsOrderIdBumpResponse(rsp *http.Response) (*PostTicketsOrderIdBumpResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTicketsOrderIdBumpResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTicketsOrderIdClaimResponse parses an HTTP response from a PostTicketsOrderIdClaimWithResponse call
+func ParsePostTicketsOrderIdClaimResponse(rsp *http.Response) (*PostTicketsOrderIdClaimResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTicketsOrderIdClaimResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTicketsOrderIdItemsDoneResponse parses an HTTP response from a PostTicketsOrderIdItemsDoneWithResponse call
+func ParsePostTicketsOrderIdItemsDoneResponse(rsp *http.Response) (*PostTicketsOrderIdItemsDoneResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTicketsOrderIdItemsDoneResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTicketsOrderIdRejectResponse parses an HTTP response from a PostTicketsOrderIdRejectWithResponse call
+func ParsePostTicketsOrderIdRejectResponse(rsp *http.Response) (*PostTicketsOrderIdRejectResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTicketsOrderIdRejectResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
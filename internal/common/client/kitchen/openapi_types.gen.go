@@ -0,0 +1,83 @@
+// Package kitchen provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package kitchen
+
+import (
+	"time"
+)
+
+// ClaimTicketRequest defines model for ClaimTicketRequest.
+type ClaimTicketRequest struct {
+	Staff string `json:"staff"`
+
+	// Station station a queued ticket is started on
+	Station *string `json:"station,omitempty"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Message *string `json:"message,omitempty"`
+}
+
+// MarkItemsDoneRequest defines model for MarkItemsDoneRequest.
+type MarkItemsDoneRequest struct {
+	ItemIds []string `json:"item_ids"`
+}
+
+// RejectTicketRequest defines model for RejectTicketRequest.
+type RejectTicketRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Response defines model for Response.
+type Response struct {
+	Data    map[string]interface{} `json:"data"`
+	Errno   int                    `json:"errno"`
+	Message string                 `json:"message"`
+	TraceId string                 `json:"trace_id"`
+}
+
+// Ticket defines model for Ticket.
+type Ticket struct {
+	ClaimedBy  string       `json:"claimed_by"`
+	CustomerId string       `json:"customer_id"`
+	Items      []TicketItem `json:"items"`
+	OrderId    string       `json:"order_id"`
+	PaidAt     time.Time    `json:"paid_at"`
+	PlatedAt   *time.Time   `json:"plated_at,omitempty"`
+
+	// PrepTime seconds
+	PrepTime     int64      `json:"prep_time"`
+	ReadyAt      *time.Time `json:"ready_at,omitempty"`
+	RejectReason *string    `json:"reject_reason,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+
+	// Station kitchen instance and station, like kitchen-host-1
+	Station string `json:"station"`
+
+	// Status queued, cooking, plated or rejected
+	Status string `json:"status"`
+}
+
+// TicketItem defines model for TicketItem.
+type TicketItem struct {
+	Done     bool   `json:"done"`
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+// GetTicketsParams defines parameters for GetTickets.
+type GetTicketsParams struct {
+	Limit *int32 `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostTicketsOrderIdClaimJSONRequestBody defines body for PostTicketsOrderIdClaim for application/json ContentType.
+type PostTicketsOrderIdClaimJSONRequestBody = ClaimTicketRequest
+
+// PostTicketsOrderIdItemsDoneJSONRequestBody defines body for PostTicketsOrderIdItemsDone for application/json ContentType.
+type PostTicketsOrderIdItemsDoneJSONRequestBody = MarkItemsDoneRequest
+
+// PostTicketsOrderIdRejectJSONRequestBody defines body for PostTicketsOrderIdReject for application/json ContentType.
+type PostTicketsOrderIdRejectJSONRequestBody = RejectTicketRequest
@@ -89,17 +89,46 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// GetCustomerCustomerIdOrders request
+	GetCustomerCustomerIdOrders(ctx context.Context, customerId string, params *GetCustomerCustomerIdOrdersParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// PostCustomerCustomerIdOrdersWithBody request with any body
-	PostCustomerCustomerIdOrdersWithBody(ctx context.Context, customerId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+	PostCustomerCustomerIdOrdersWithBody(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
-	PostCustomerCustomerIdOrders(ctx context.Context, customerId string, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+	PostCustomerCustomerIdOrders(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// GetCustomerCustomerIdOrdersOrderId request
 	GetCustomerCustomerIdOrdersOrderId(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostCustomerCustomerIdOrdersOrderIdCancelWithBody request with any body
+	PostCustomerCustomerIdOrdersOrderIdCancelWithBody(ctx context.Context, customerId string, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostCustomerCustomerIdOrdersOrderIdCancel(ctx context.Context, customerId string, orderId string, body PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdEvents request
+	GetCustomerCustomerIdOrdersOrderIdEvents(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdHistory request
+	GetCustomerCustomerIdOrdersOrderIdHistory(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdSaga request
+	GetCustomerCustomerIdOrdersOrderIdSaga(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetCustomerCustomerIdOrders(ctx context.Context, customerId string, params *GetCustomerCustomerIdOrdersParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCustomerCustomerIdOrdersRequest(c.Server, customerId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-func (c *Client) PostCustomerCustomerIdOrdersWithBody(ctx context.Context, customerId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostCustomerCustomerIdOrdersRequestWithBody(c.Server, customerId, contentType, body)
+func (c *Client) PostCustomerCustomerIdOrdersWithBody(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostCustomerCustomerIdOrdersRequestWithBody(c.Server, customerId, params, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +139,8 @@ func (c *Client) PostCustomerCustomerIdOrdersWithBody(ctx context.Context, custo
 	return c.Client.Do(req)
 }
 
-func (c *Client) PostCustomerCustomerIdOrders(ctx context.Context, customerId string, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostCustomerCustomerIdOrdersRequest(c.Server, customerId, body)
+func (c *Client) PostCustomerCustomerIdOrders(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostCustomerCustomerIdOrdersRequest(c.Server, customerId, params, body)
 	if err != nil {
 		return nil, err
 	}
@@ -134,19 +163,199 @@ func (c *Client) GetCustomerCustomerIdOrdersOrderId(ctx context.Context, custome
 	return c.Client.Do(req)
 }
 
+func (c *Client) PostCustomerCustomerIdOrdersOrderIdCancelWithBody(ctx context.Context, customerId string, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostCustomerCustomerIdOrdersOrderIdCancelRequestWithBody(c.Server, customerId, orderId, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostCustomerCustomerIdOrdersOrderIdCancel(ctx context.Context, customerId string, orderId string, body PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostCustomerCustomerIdOrdersOrderIdCancelRequest(c.Server, customerId, orderId, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCustomerCustomerIdOrdersOrderIdEvents(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCustomerCustomerIdOrdersOrderIdEventsRequest(c.Server, customerId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCustomerCustomerIdOrdersOrderIdHistory(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCustomerCustomerIdOrdersOrderIdHistoryRequest(c.Server, customerId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCustomerCustomerIdOrdersOrderIdSaga(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCustomerCustomerIdOrdersOrderIdSagaRequest(c.Server, customerId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetCustomerCustomerIdOrdersRequest generates requests for GetCustomerCustomerIdOrders
+func NewGetCustomerCustomerIdOrdersRequest(server string, customerId string, params *GetCustomerCustomerIdOrdersParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "customer_id", runtime.ParamLocationPath, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/customer/%s/orders", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.CreatedAfter != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "created_after", runtime.ParamLocationQuery, *params.CreatedAfter); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.CreatedBefore != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "created_before", runtime.ParamLocationQuery, *params.CreatedBefore); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewPostCustomerCustomerIdOrdersRequest calls the generic PostCustomerCustomerIdOrders builder with application/json body
-func NewPostCustomerCustomerIdOrdersRequest(server string, customerId string, body PostCustomerCustomerIdOrdersJSONRequestBody) (*http.Request, error) {
+func NewPostCustomerCustomerIdOrdersRequest(server string, customerId string, params *PostCustomerCustomerIdOrdersParams, body PostCustomerCustomerIdOrdersJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewPostCustomerCustomerIdOrdersRequestWithBody(server, customerId, "application/json", bodyReader)
+	return NewPostCustomerCustomerIdOrdersRequestWithBody(server, customerId, params, "application/json", bodyReader)
 }
 
 // NewPostCustomerCustomerIdOrdersRequestWithBody generates requests for PostCustomerCustomerIdOrders with any type of body
-func NewPostCustomerCustomerIdOrdersRequestWithBody(server string, customerId string, contentType string, body io.Reader) (*http.Request, error) {
+func NewPostCustomerCustomerIdOrdersRequestWithBody(server string, customerId string, params *PostCustomerCustomerIdOrdersParams, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
@@ -178,6 +387,21 @@ func NewPostCustomerCustomerIdOrdersRequestWithBody(server string, customerId st
 
 	req.Header.Add("Content-Type", contentType)
 
+	if params != nil {
+
+		if params.IdempotencyKey != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam0)
+		}
+
+	}
+
 	return req, nil
 }
 
@@ -222,139 +446,533 @@ func NewGetCustomerCustomerIdOrdersOrderIdRequest(server string, customerId stri
 	return req, nil
 }
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
-}
-
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+// NewPostCustomerCustomerIdOrdersOrderIdCancelRequest calls the generic PostCustomerCustomerIdOrdersOrderIdCancel builder with application/json body
+func NewPostCustomerCustomerIdOrdersOrderIdCancelRequest(server string, customerId string, orderId string, body PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
-
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
-		newBaseURL, err := url.Parse(baseURL)
-		if err != nil {
-			return err
-		}
-		c.Server = newBaseURL.String()
-		return nil
-	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostCustomerCustomerIdOrdersOrderIdCancelRequestWithBody(server, customerId, orderId, "application/json", bodyReader)
 }
 
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// PostCustomerCustomerIdOrdersWithBodyWithResponse request with any body
-	PostCustomerCustomerIdOrdersWithBodyWithResponse(ctx context.Context, customerId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error)
+// NewPostCustomerCustomerIdOrdersOrderIdCancelRequestWithBody generates requests for PostCustomerCustomerIdOrdersOrderIdCancel with any type of body
+func NewPostCustomerCustomerIdOrdersOrderIdCancelRequestWithBody(server string, customerId string, orderId string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
 
-	PostCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error)
+	var pathParam0 string
 
-	// GetCustomerCustomerIdOrdersOrderIdWithResponse request
-	GetCustomerCustomerIdOrdersOrderIdWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdResponse, error)
-}
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "customer_id", runtime.ParamLocationPath, customerId)
+	if err != nil {
+		return nil, err
+	}
 
-type PostCustomerCustomerIdOrdersResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *Response
-	JSONDefault  *Error
-}
+	var pathParam1 string
 
-// Status returns HTTPResponse.Status
-func (r PostCustomerCustomerIdOrdersResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PostCustomerCustomerIdOrdersResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
-
-type GetCustomerCustomerIdOrdersOrderIdResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *Response
-	JSONDefault  *Error
-}
 
-// Status returns HTTPResponse.Status
-func (r GetCustomerCustomerIdOrdersOrderIdResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+	operationPath := fmt.Sprintf("/customer/%s/orders/%s/cancel", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r GetCustomerCustomerIdOrdersOrderIdResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-// PostCustomerCustomerIdOrdersWithBodyWithResponse request with arbitrary body returning *PostCustomerCustomerIdOrdersResponse
-func (c *ClientWithResponses) PostCustomerCustomerIdOrdersWithBodyWithResponse(ctx context.Context, customerId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error) {
-	rsp, err := c.PostCustomerCustomerIdOrdersWithBody(ctx, customerId, contentType, body, reqEditors...)
+	req, err := http.NewRequest("POST", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
-	return ParsePostCustomerCustomerIdOrdersResponse(rsp)
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
 }
 
-func (c *ClientWithResponses) PostCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error) {
-	rsp, err := c.PostCustomerCustomerIdOrders(ctx, customerId, body, reqEditors...)
+// NewGetCustomerCustomerIdOrdersOrderIdEventsRequest generates requests for GetCustomerCustomerIdOrdersOrderIdEvents
+func NewGetCustomerCustomerIdOrdersOrderIdEventsRequest(server string, customerId string, orderId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "customer_id", runtime.ParamLocationPath, customerId)
 	if err != nil {
 		return nil, err
 	}
-	return ParsePostCustomerCustomerIdOrdersResponse(rsp)
-}
 
-// GetCustomerCustomerIdOrdersOrderIdWithResponse request returning *GetCustomerCustomerIdOrdersOrderIdResponse
-func (c *ClientWithResponses) GetCustomerCustomerIdOrdersOrderIdWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdResponse, error) {
-	rsp, err := c.GetCustomerCustomerIdOrdersOrderId(ctx, customerId, orderId, reqEditors...)
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetCustomerCustomerIdOrdersOrderIdResponse(rsp)
-}
 
-// ParsePostCustomerCustomerIdOrdersResponse parses an HTTP response from a PostCustomerCustomerIdOrdersWithResponse call
-func ParsePostCustomerCustomerIdOrdersResponse(rsp *http.Response) (*PostCustomerCustomerIdOrdersResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
+	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PostCustomerCustomerIdOrdersResponse{
+	operationPath := fmt.Sprintf("/customer/%s/orders/%s/events", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetCustomerCustomerIdOrdersOrderIdHistoryRequest generates requests for GetCustomerCustomerIdOrdersOrderIdHistory
+func NewGetCustomerCustomerIdOrdersOrderIdHistoryRequest(server string, customerId string, orderId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "customer_id", runtime.ParamLocationPath, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/customer/%s/orders/%s/history", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetCustomerCustomerIdOrdersOrderIdSagaRequest generates requests for GetCustomerCustomerIdOrdersOrderIdSaga
+func NewGetCustomerCustomerIdOrdersOrderIdSagaRequest(server string, customerId string, orderId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "customer_id", runtime.ParamLocationPath, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "order_id", runtime.ParamLocationPath, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/customer/%s/orders/%s/saga", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetCustomerCustomerIdOrdersWithResponse request
+	GetCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, params *GetCustomerCustomerIdOrdersParams, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersResponse, error)
+
+	// PostCustomerCustomerIdOrdersWithBodyWithResponse request with any body
+	PostCustomerCustomerIdOrdersWithBodyWithResponse(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error)
+
+	PostCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdWithResponse request
+	GetCustomerCustomerIdOrdersOrderIdWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdResponse, error)
+
+	// PostCustomerCustomerIdOrdersOrderIdCancelWithBodyWithResponse request with any body
+	PostCustomerCustomerIdOrdersOrderIdCancelWithBodyWithResponse(ctx context.Context, customerId string, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersOrderIdCancelResponse, error)
+
+	PostCustomerCustomerIdOrdersOrderIdCancelWithResponse(ctx context.Context, customerId string, orderId string, body PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersOrderIdCancelResponse, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdEventsWithResponse request
+	GetCustomerCustomerIdOrdersOrderIdEventsWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdEventsResponse, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdHistoryWithResponse request
+	GetCustomerCustomerIdOrdersOrderIdHistoryWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdHistoryResponse, error)
+
+	// GetCustomerCustomerIdOrdersOrderIdSagaWithResponse request
+	GetCustomerCustomerIdOrdersOrderIdSagaWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdSagaResponse, error)
+}
+
+type GetCustomerCustomerIdOrdersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCustomerCustomerIdOrdersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCustomerCustomerIdOrdersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostCustomerCustomerIdOrdersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostCustomerCustomerIdOrdersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostCustomerCustomerIdOrdersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCustomerCustomerIdOrdersOrderIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCustomerCustomerIdOrdersOrderIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCustomerCustomerIdOrdersOrderIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostCustomerCustomerIdOrdersOrderIdCancelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostCustomerCustomerIdOrdersOrderIdCancelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostCustomerCustomerIdOrdersOrderIdCancelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCustomerCustomerIdOrdersOrderIdEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCustomerCustomerIdOrdersOrderIdEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCustomerCustomerIdOrdersOrderIdEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCustomerCustomerIdOrdersOrderIdHistoryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCustomerCustomerIdOrdersOrderIdHistoryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCustomerCustomerIdOrdersOrderIdHistoryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCustomerCustomerIdOrdersOrderIdSagaResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCustomerCustomerIdOrdersOrderIdSagaResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCustomerCustomerIdOrdersOrderIdSagaResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetCustomerCustomerIdOrdersWithResponse request returning *GetCustomerCustomerIdOrdersResponse
+func (c *ClientWithResponses) GetCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, params *GetCustomerCustomerIdOrdersParams, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersResponse, error) {
+	rsp, err := c.GetCustomerCustomerIdOrders(ctx, customerId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCustomerCustomerIdOrdersResponse(rsp)
+}
+
+// PostCustomerCustomerIdOrdersWithBodyWithResponse request with arbitrary body returning *PostCustomerCustomerIdOrdersResponse
+func (c *ClientWithResponses) PostCustomerCustomerIdOrdersWithBodyWithResponse(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error) {
+	rsp, err := c.PostCustomerCustomerIdOrdersWithBody(ctx, customerId, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostCustomerCustomerIdOrdersResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostCustomerCustomerIdOrdersWithResponse(ctx context.Context, customerId string, params *PostCustomerCustomerIdOrdersParams, body PostCustomerCustomerIdOrdersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersResponse, error) {
+	rsp, err := c.PostCustomerCustomerIdOrders(ctx, customerId, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostCustomerCustomerIdOrdersResponse(rsp)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdWithResponse request returning *GetCustomerCustomerIdOrdersOrderIdResponse
+func (c *ClientWithResponses) GetCustomerCustomerIdOrdersOrderIdWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdResponse, error) {
+	rsp, err := c.GetCustomerCustomerIdOrdersOrderId(ctx, customerId, orderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCustomerCustomerIdOrdersOrderIdResponse(rsp)
+}
+
+// PostCustomerCustomerIdOrdersOrderIdCancelWithBodyWithResponse request with arbitrary body returning *PostCustomerCustomerIdOrdersOrderIdCancelResponse
+func (c *ClientWithResponses) PostCustomerCustomerIdOrdersOrderIdCancelWithBodyWithResponse(ctx context.Context, customerId string, orderId string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersOrderIdCancelResponse, error) {
+	rsp, err := c.PostCustomerCustomerIdOrdersOrderIdCancelWithBody(ctx, customerId, orderId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostCustomerCustomerIdOrdersOrderIdCancelResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostCustomerCustomerIdOrdersOrderIdCancelWithResponse(ctx context.Context, customerId string, orderId string, body PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody, reqEditors ...RequestEditorFn) (*PostCustomerCustomerIdOrdersOrderIdCancelResponse, error) {
+	rsp, err := c.PostCustomerCustomerIdOrdersOrderIdCancel(ctx, customerId, orderId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostCustomerCustomerIdOrdersOrderIdCancelResponse(rsp)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdEventsWithResponse request returning *GetCustomerCustomerIdOrdersOrderIdEventsResponse
+func (c *ClientWithResponses) GetCustomerCustomerIdOrdersOrderIdEventsWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdEventsResponse, error) {
+	rsp, err := c.GetCustomerCustomerIdOrdersOrderIdEvents(ctx, customerId, orderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCustomerCustomerIdOrdersOrderIdEventsResponse(rsp)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdHistoryWithResponse request returning *GetCustomerCustomerIdOrdersOrderIdHistoryResponse
+func (c *ClientWithResponses) GetCustomerCustomerIdOrdersOrderIdHistoryWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdHistoryResponse, error) {
+	rsp, err := c.GetCustomerCustomerIdOrdersOrderIdHistory(ctx, customerId, orderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCustomerCustomerIdOrdersOrderIdHistoryResponse(rsp)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdSagaWithResponse request returning *GetCustomerCustomerIdOrdersOrderIdSagaResponse
+func (c *ClientWithResponses) GetCustomerCustomerIdOrdersOrderIdSagaWithResponse(ctx context.Context, customerId string, orderId string, reqEditors ...RequestEditorFn) (*GetCustomerCustomerIdOrdersOrderIdSagaResponse, error) {
+	rsp, err := c.GetCustomerCustomerIdOrdersOrderIdSaga(ctx, customerId, orderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCustomerCustomerIdOrdersOrderIdSagaResponse(rsp)
+}
+
+// ParseGetCustomerCustomerIdOrdersResponse parses an HTTP response from a GetCustomerCustomerIdOrdersWithResponse call
+func ParseGetCustomerCustomerIdOrdersResponse(rsp *http.Response) (*GetCustomerCustomerIdOrdersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCustomerCustomerIdOrdersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostCustomerCustomerIdOrdersResponse parses an HTTP response from a PostCustomerCustomerIdOrdersWithResponse call
+func ParsePostCustomerCustomerIdOrdersResponse(rsp *http.Response) (*PostCustomerCustomerIdOrdersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostCustomerCustomerIdOrdersResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
@@ -411,3 +1029,128 @@ func ParseGetCustomerCustomerIdOrdersOrderIdResponse(rsp *http.Response) (*GetCu
 
 	return response, nil
 }
+
+// ParsePostCustomerCustomerIdOrdersOrderIdCancelResponse parses an HTTP response from a PostCustomerCustomerIdOrdersOrderIdCancelWithResponse call
+func ParsePostCustomerCustomerIdOrdersOrderIdCancelResponse(rsp *http.Response) (*PostCustomerCustomerIdOrdersOrderIdCancelResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostCustomerCustomerIdOrdersOrderIdCancelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetCustomerCustomerIdOrdersOrderIdEventsResponse parses an HTTP response from a GetCustomerCustomerIdOrdersOrderIdEventsWithResponse call
+func ParseGetCustomerCustomerIdOrdersOrderIdEventsResponse(rsp *http.Response) (*GetCustomerCustomerIdOrdersOrderIdEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCustomerCustomerIdOrdersOrderIdEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetCustomerCustomerIdOrdersOrderIdHistoryResponse parses an HTTP response from a GetCustomerCustomerIdOrdersOrderIdHistoryWithResponse call
+func ParseGetCustomerCustomerIdOrdersOrderIdHistoryResponse(rsp *http.Response) (*GetCustomerCustomerIdOrdersOrderIdHistoryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCustomerCustomerIdOrdersOrderIdHistoryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetCustomerCustomerIdOrdersOrderIdSagaResponse parses an HTTP response from a GetCustomerCustomerIdOrdersOrderIdSagaWithResponse call
+func ParseGetCustomerCustomerIdOrdersOrderIdSagaResponse(rsp *http.Response) (*GetCustomerCustomerIdOrdersOrderIdSagaResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCustomerCustomerIdOrdersOrderIdSagaResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Response
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
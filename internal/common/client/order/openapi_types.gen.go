@@ -3,6 +3,15 @@
 // Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
 package order
 
+import (
+	"time"
+)
+
+// CancelOrderRequest defines model for CancelOrderRequest.
+type CancelOrderRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
 // CreateOrderRequest defines model for CreateOrderRequest.
 type CreateOrderRequest struct {
 	CustomerId string             `json:"customer_id"`
@@ -30,11 +39,43 @@ type ItemWithQuantity struct {
 
 // Order defines model for Order.
 type Order struct {
-	CustomerId  string `json:"customer_id"`
-	Id          string `json:"id"`
-	Items       []Item `json:"items"`
-	PaymentLink string `json:"payment_link"`
-	Status      string `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	CustomerId string    `json:"customer_id"`
+
+	// EstimatedReadyAt estimated by the kitchen from its queue once the order is paid, absent before
+	EstimatedReadyAt *time.Time `json:"estimated_ready_at,omitempty"`
+	Id               string     `json:"id"`
+	Items            []Item     `json:"items"`
+	PaidAt           *time.Time `json:"paid_at,omitempty"`
+	PaymentLink      string     `json:"payment_link"`
+	ReadyAt          *time.Time `json:"ready_at,omitempty"`
+	Status           string     `json:"status"`
+}
+
+// OrderHistoryEntry defines model for OrderHistoryEntry.
+type OrderHistoryEntry struct {
+	// Actor service that made the change
+	Actor string    `json:"actor"`
+	At    time.Time `json:"at"`
+
+	// Items only on created
+	Items   *[]Item `json:"items,omitempty"`
+	New     *string `json:"new,omitempty"`
+	Old     *string `json:"old,omitempty"`
+	TraceId *string `json:"trace_id,omitempty"`
+
+	// Type created, status_changed, payment_link_changed or estimated_ready_at_changed
+	Type string `json:"type"`
+
+	// Version 1 for created, one more per change
+	Version int64 `json:"version"`
+}
+
+// OrderList defines model for OrderList.
+type OrderList struct {
+	// NextCursor empty on the last page
+	NextCursor string  `json:"next_cursor"`
+	Orders     []Order `json:"orders"`
 }
 
 // Response defines model for Response.
@@ -45,5 +86,56 @@ type Response struct {
 	TraceId string                 `json:"trace_id"`
 }
 
+// Saga defines model for Saga.
+type Saga struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Deadline the current step is compensated after it, absent when the saga is not running
+	Deadline *time.Time   `json:"deadline,omitempty"`
+	History  []SagaRecord `json:"history"`
+	OrderId  string       `json:"order_id"`
+
+	// Status running, completed, compensating, compensated or failed
+	Status string `json:"status"`
+
+	// Step reserve_stock, create_payment_link, await_payment, cook or ready
+	Step      string    `json:"step"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SagaRecord defines model for SagaRecord.
+type SagaRecord struct {
+	// Action started, done, timed_out, compensated or failed
+	Action string    `json:"action"`
+	At     time.Time `json:"at"`
+	Reason *string   `json:"reason,omitempty"`
+	Step   string    `json:"step"`
+}
+
+// GetCustomerCustomerIdOrdersParams defines parameters for GetCustomerCustomerIdOrders.
+type GetCustomerCustomerIdOrdersParams struct {
+	// Status only orders in one of these statuses
+	Status *[]string `form:"status,omitempty" json:"status,omitempty"`
+
+	// CreatedAfter inclusive
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore exclusive
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Cursor next_cursor of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit  *int32  `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostCustomerCustomerIdOrdersParams defines parameters for PostCustomerCustomerIdOrders.
+type PostCustomerCustomerIdOrdersParams struct {
+	// IdempotencyKey retrying with the same key returns the first result instead of creating another order
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
 // PostCustomerCustomerIdOrdersJSONRequestBody defines body for PostCustomerCustomerIdOrders for application/json ContentType.
 type PostCustomerCustomerIdOrdersJSONRequestBody = CreateOrderRequest
+
+// PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody defines body for PostCustomerCustomerIdOrdersOrderIdCancel for application/json ContentType.
+type PostCustomerCustomerIdOrdersOrderIdCancelJSONRequestBody = CancelOrderRequest
@@ -0,0 +1,178 @@
+/*
+dlqctl 查看和处理 dlq 中的消息, 连接参数取自 global.yaml 的 rabbitmq 配置.
+
+	dlqctl list [-limit 100]
+	dlqctl export [-o dlq.jsonl]
+	dlqctl replay (-ids id1,id2 | -all)
+	dlqctl purge (-ids id1,id2 | -all)
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	q, closeConn, err := broker.DialDLQ(
+		viper.GetString("rabbitmq.user"),
+		viper.GetString("rabbitmq.password"),
+		viper.GetString("rabbitmq.host"),
+		viper.GetString("rabbitmq.port"),
+	)
+	if err != nil {
+		fail(err)
+	}
+	defer func() { _ = closeConn() }()
+
+	err = run(ctx, q, os.Args[1], os.Args[2:], os.Stdout, os.Stderr)
+	if errors.Is(err, errUsage) {
+		usage()
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+var errUsage = errors.New("usage")
+
+// *broker.DLQ, 测试时替换
+type deadLetterQueue interface {
+	List(ctx context.Context, limit int) ([]*broker.DeadLetter, error)
+	Export(ctx context.Context, w io.Writer) (int, error)
+	Replay(ctx context.Context, sel broker.Selection) (int, error)
+	Purge(ctx context.Context, sel broker.Selection) (int, error)
+}
+
+func run(ctx context.Context, q deadLetterQueue, cmd string, args []string, stdout, stderr io.Writer) error {
+	switch cmd {
+	case "list":
+		return list(ctx, q, args, stdout, stderr)
+	case "export":
+		return export(ctx, q, args, stdout, stderr)
+	case "replay":
+		return apply(ctx, cmd, q.Replay, args, stdout, stderr)
+	case "purge":
+		return apply(ctx, cmd, q.Purge, args, stdout, stderr)
+	}
+	return errUsage
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func list(ctx context.Context, q deadLetterQueue, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("list", stderr)
+	limit := fs.Int("limit", 100, "max messages to show, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *limit < 0 {
+		return fmt.Errorf("negative limit %d", *limit)
+	}
+
+	msgs, err := q.List(ctx, *limit)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MESSAGE_ID\tEXCHANGE\tROUTING_KEY\tRETRY\tTRACE_ID\tDEAD_AT")
+	for _, m := range msgs {
+		deadAt := ""
+		if !m.DeadAt.IsZero() {
+			deadAt = m.DeadAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", m.MessageID, m.Exchange, m.RoutingKey, m.RetryCount, m.TraceID, deadAt)
+	}
+	return w.Flush()
+}
+
+func export(ctx context.Context, q deadLetterQueue, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("export", stderr)
+	out := fs.String("o", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	w := stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	n, err := q.Export(ctx, w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "exported %d messages\n", n)
+	return nil
+}
+
+func apply(
+	ctx context.Context,
+	name string,
+	op func(context.Context, broker.Selection) (int, error),
+	args []string,
+	stdout, stderr io.Writer,
+) error {
+	fs := newFlagSet(name, stderr)
+	ids := fs.String("ids", "", "comma separated message ids")
+	all := fs.Bool("all", false, "every message in dlq")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sel := broker.Selection{All: *all}
+	for _, id := range strings.Split(*ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			sel.IDs = append(sel.IDs, id)
+		}
+	}
+	if err := sel.Validate(); err != nil {
+		return err
+	}
+	n, err := op(ctx, sel)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s %d messages\n", name, n)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: dlqctl list|export|replay|purge [flags]")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "dlqctl:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl deadLetterQueue
+type fakeDLQ struct {
+	msgs  []*broker.DeadLetter
+	limit int
+	sel   broker.Selection
+}
+
+func (f *fakeDLQ) List(_ context.Context, limit int) ([]*broker.DeadLetter, error) {
+	f.limit = limit
+	if limit == 0 || limit > len(f.msgs) {
+		return f.msgs, nil
+	}
+	return f.msgs[:limit], nil
+}
+
+func (f *fakeDLQ) Export(context.Context, io.Writer) (int, error) {
+	return len(f.msgs), nil
+}
+
+func (f *fakeDLQ) Replay(_ context.Context, sel broker.Selection) (int, error) {
+	return f.remove(sel), nil
+}
+
+func (f *fakeDLQ) Purge(_ context.Context, sel broker.Selection) (int, error) {
+	return f.remove(sel), nil
+}
+
+func (f *fakeDLQ) remove(sel broker.Selection) int {
+	f.sel = sel
+	n := len(f.msgs)
+	f.msgs = slices.DeleteFunc(f.msgs, func(m *broker.DeadLetter) bool {
+		return sel.All || slices.Contains(sel.IDs, m.MessageID)
+	})
+	return n - len(f.msgs)
+}
+
+func newFakeDLQ() *fakeDLQ {
+	return &fakeDLQ{msgs: []*broker.DeadLetter{
+		{MessageID: "m1", RoutingKey: "stock.order.cancelled", RetryCount: 5},
+		{MessageID: "m2", RoutingKey: "payment.order.expired", RetryCount: 5},
+		{MessageID: "m3", RoutingKey: "order.paid", RetryCount: 5},
+	}}
+}
+
+func runCmd(q deadLetterQueue, cmd string, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := run(context.Background(), q, cmd, args, &stdout, io.Discard)
+	return stdout.String(), err
+}
+
+func TestList(t *testing.T) {
+	q := newFakeDLQ()
+	out, err := runCmd(q, "list", "-limit", "2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, q.limit)
+	assert.Contains(t, out, "m1")
+	assert.Contains(t, out, "payment.order.expired")
+	assert.NotContains(t, out, "m3")
+
+	out, err = runCmd(q, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 100, q.limit)
+	assert.Contains(t, out, "m3")
+
+	q.limit = -100
+	_, err = runCmd(q, "list", "-limit", "-1")
+	assert.ErrorContains(t, err, "negative limit")
+	assert.Equal(t, -100, q.limit, "List must not be called")
+}
+
+func TestReplay(t *testing.T) {
+	q := newFakeDLQ()
+	out, err := runCmd(q, "replay", "-ids", "m1, ,m3")
+	require.NoError(t, err)
+	assert.Equal(t, broker.Selection{IDs: []string{"m1", "m3"}}, q.sel)
+	assert.Equal(t, "replay 2 messages\n", out)
+	assert.Len(t, q.msgs, 1)
+
+	_, err = runCmd(q, "replay")
+	assert.ErrorIs(t, err, broker.ErrEmptySelection)
+}
+
+func TestPurge(t *testing.T) {
+	q := newFakeDLQ()
+	out, err := runCmd(q, "purge", "-all")
+	require.NoError(t, err)
+	assert.True(t, q.sel.All)
+	assert.Equal(t, "purge 3 messages\n", out)
+	assert.Empty(t, q.msgs)
+}
+
+func TestRun_Usage(t *testing.T) {
+	_, err := runCmd(newFakeDLQ(), "drop")
+	assert.ErrorIs(t, err, errUsage)
+	_, err = runCmd(newFakeDLQ(), "list", "-nope")
+	assert.Error(t, err)
+}
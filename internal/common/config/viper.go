@@ -36,6 +36,7 @@ func newViperConfig() error {
 	viper.AddConfigPath(relPath)
 	_ = viper.BindEnv("stripe-key", "STRIPE_KEY")
 	_ = viper.BindEnv("endpoint-stripe-secret", "ENDPOINT_STRIPE_SECRET")
+	_ = viper.BindEnv("admin-token", "ADMIN_TOKEN")
 	viper.AutomaticEnv()
 	return viper.ReadInConfig()
 }
@@ -5,6 +5,8 @@ const (
 	ErrnoUnknown       = 404
 	ErrnoBindRequest   = 403
 	ErrnoInvalidParams = 401
+	ErrnoNotFound      = 405
+	ErrnoConflict      = 406
 )
 
 var (
@@ -13,5 +15,7 @@ var (
 		ErrnoUnknown:       "unknown error",
 		ErrnoBindRequest:   "bind request error",
 		ErrnoInvalidParams: "invalid parameters",
+		ErrnoNotFound:      "not found",
+		ErrnoConflict:      "conflict",
 	}
 )
@@ -4,5 +4,11 @@ const (
 	OrderStatusPending           = "pending"
 	OrderStatusWaitingForPayment = "waiting_for_payment"
 	OrderStatusPaid              = "paid"
+	OrderStatusCooking           = "cooking" // the kitchen started the ticket of the order
 	OrderStatusReady             = "ready"
+	OrderStatusCancelled         = "cancelled"
+	OrderStatusExpired           = "expired"
+	OrderStatusRejected          = "rejected" // the kitchen could not make the paid order
+	OrderStatusRefunded          = "refunded"
+	OrderStatusPartiallyRefunded = "partially_refunded" // part of the payment was given back
 )
@@ -1,6 +1,8 @@
 package convert
 
 import (
+	"time"
+
 	client "github.com/peiyouyao/gorder/common/client/order"
 	"github.com/peiyouyao/gorder/common/entity"
 	"github.com/peiyouyao/gorder/common/genproto/orderpb"
@@ -14,6 +16,11 @@ func OrderEntityToProto(o *entity.Order) *orderpb.Order {
 		Status:      o.Status,
 		Items:       ItemEntitiesToProtos(o.Items),
 		PaymentLink: o.PaymentLink,
+
+		CreatedAt:        unixMilli(o.CreatedAt),
+		PaidAt:           unixMilli(o.PaidAt),
+		EstimatedReadyAt: unixMilli(o.EstimatedReadyAt),
+		ReadyAt:          unixMilli(o.ReadyAt),
 	}
 }
 
@@ -25,6 +32,11 @@ func OrderProtoToEntity(o *orderpb.Order) *entity.Order {
 		Status:      o.Status,
 		PaymentLink: o.PaymentLink,
 		Items:       ItemProtosToEntities(o.Items),
+
+		CreatedAt:        fromUnixMilli(o.CreatedAt),
+		PaidAt:           fromUnixMilli(o.PaidAt),
+		EstimatedReadyAt: fromUnixMilli(o.EstimatedReadyAt),
+		ReadyAt:          fromUnixMilli(o.ReadyAt),
 	}
 }
 
@@ -36,6 +48,11 @@ func OrderClientToEntity(o *client.Order) *entity.Order {
 		Status:      o.Status,
 		PaymentLink: o.PaymentLink,
 		Items:       ItemClientsToEntities(o.Items),
+
+		CreatedAt:        o.CreatedAt,
+		PaidAt:           fromPtr(o.PaidAt),
+		EstimatedReadyAt: fromPtr(o.EstimatedReadyAt),
+		ReadyAt:          fromPtr(o.ReadyAt),
 	}
 }
 
@@ -47,7 +64,42 @@ func OrderEntityToClient(o *entity.Order) *client.Order {
 		Status:      o.Status,
 		PaymentLink: o.PaymentLink,
 		Items:       ItemEntitiesToClients(o.Items),
+
+		CreatedAt:        o.CreatedAt,
+		PaidAt:           toPtr(o.PaidAt),
+		EstimatedReadyAt: toPtr(o.EstimatedReadyAt),
+		ReadyAt:          toPtr(o.ReadyAt),
+	}
+}
+
+// proto 中 0 表示没有
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// openapi 中可选的时间是指针
+func toPtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func fromPtr(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
 	}
+	return *t
 }
 
 func check(o interface{}) {
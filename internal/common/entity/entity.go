@@ -3,6 +3,7 @@ package entity
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -80,6 +81,11 @@ type Order struct {
 	Status      string
 	PaymentLink string
 	Items       []*Item
+	// 零值表示还没有
+	CreatedAt        time.Time
+	PaidAt           time.Time
+	EstimatedReadyAt time.Time
+	ReadyAt          time.Time
 }
 
 func NewValidOrder(ID string, customerID string, status string, paymentLink string, items []*Item) (*Order, error) {
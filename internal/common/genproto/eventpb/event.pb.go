@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: eventpb/event.proto
+
+package eventpb
+
+import (
+	orderpb "github.com/peiyouyao/gorder/common/genproto/orderpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Envelope wraps every event published to the broker.
+// Consumers upcast older SchemaVersion values before reading the payload.
+type Envelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventID       string                 `protobuf:"bytes,1,opt,name=EventID,proto3" json:"EventID,omitempty"`     // same as the amqp message id
+	EventType     string                 `protobuf:"bytes,2,opt,name=EventType,proto3" json:"EventType,omitempty"` // order.created, order.paid ...
+	SchemaVersion int32                  `protobuf:"varint,3,opt,name=SchemaVersion,proto3" json:"SchemaVersion,omitempty"`
+	OccurredAt    int64                  `protobuf:"varint,4,opt,name=OccurredAt,proto3" json:"OccurredAt,omitempty"`      // unix milliseconds
+	Producer      string                 `protobuf:"bytes,5,opt,name=Producer,proto3" json:"Producer,omitempty"`           // service name of the publisher
+	CorrelationID string                 `protobuf:"bytes,6,opt,name=CorrelationID,proto3" json:"CorrelationID,omitempty"` // ties together the events of one order
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Envelope_Order
+	Payload isEnvelope_Payload `protobuf_oneof:"Payload"`
+	// schema version 1, the raw json body of messages published before the envelope existed
+	LegacyPayload []byte `protobuf:"bytes,15,opt,name=LegacyPayload,proto3" json:"LegacyPayload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	mi := &file_eventpb_event_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Envelope) GetEventID() string {
+	if x != nil {
+		return x.EventID
+	}
+	return ""
+}
+
+func (x *Envelope) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *Envelope) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Envelope) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+func (x *Envelope) GetProducer() string {
+	if x != nil {
+		return x.Producer
+	}
+	return ""
+}
+
+func (x *Envelope) GetCorrelationID() string {
+	if x != nil {
+		return x.CorrelationID
+	}
+	return ""
+}
+
+func (x *Envelope) GetPayload() isEnvelope_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Envelope) GetOrder() *orderpb.Order {
+	if x != nil {
+		if x, ok := x.Payload.(*Envelope_Order); ok {
+			return x.Order
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetLegacyPayload() []byte {
+	if x != nil {
+		return x.LegacyPayload
+	}
+	return nil
+}
+
+type isEnvelope_Payload interface {
+	isEnvelope_Payload()
+}
+
+type Envelope_Order struct {
+	Order *orderpb.Order `protobuf:"bytes,7,opt,name=Order,proto3,oneof"`
+}
+
+func (*Envelope_Order) isEnvelope_Payload() {}
+
+var File_eventpb_event_proto protoreflect.FileDescriptor
+
+const file_eventpb_event_proto_rawDesc = "" +
+	"\n" +
+	"\x13eventpb/event.proto\x12\aeventpb\x1a\x13orderpb/order.proto\"\xa3\x02\n" +
+	"\bEnvelope\x12\x18\n" +
+	"\aEventID\x18\x01 \x01(\tR\aEventID\x12\x1c\n" +
+	"\tEventType\x18\x02 \x01(\tR\tEventType\x12$\n" +
+	"\rSchemaVersion\x18\x03 \x01(\x05R\rSchemaVersion\x12\x1e\n" +
+	"\n" +
+	"OccurredAt\x18\x04 \x01(\x03R\n" +
+	"OccurredAt\x12\x1a\n" +
+	"\bProducer\x18\x05 \x01(\tR\bProducer\x12$\n" +
+	"\rCorrelationID\x18\x06 \x01(\tR\rCorrelationID\x12&\n" +
+	"\x05Order\x18\a \x01(\v2\x0e.orderpb.OrderH\x00R\x05Order\x12$\n" +
+	"\rLegacyPayload\x18\x0f \x01(\fR\rLegacyPayloadB\t\n" +
+	"\aPayloadB5Z3github.com/peiyouyao/gorder/common/genproto/eventpbb\x06proto3"
+
+var (
+	file_eventpb_event_proto_rawDescOnce sync.Once
+	file_eventpb_event_proto_rawDescData []byte
+)
+
+func file_eventpb_event_proto_rawDescGZIP() []byte {
+	file_eventpb_event_proto_rawDescOnce.Do(func() {
+		file_eventpb_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_eventpb_event_proto_rawDesc), len(file_eventpb_event_proto_rawDesc)))
+	})
+	return file_eventpb_event_proto_rawDescData
+}
+
+var file_eventpb_event_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_eventpb_event_proto_goTypes = []any{
+	(*Envelope)(nil),      // 0: eventpb.Envelope
+	(*orderpb.Order)(nil), // 1: orderpb.Order
+}
+var file_eventpb_event_proto_depIdxs = []int32{
+	1, // 0: eventpb.Envelope.Order:type_name -> orderpb.Order
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_eventpb_event_proto_init() }
+func file_eventpb_event_proto_init() {
+	if File_eventpb_event_proto != nil {
+		return
+	}
+	file_eventpb_event_proto_msgTypes[0].OneofWrappers = []any{
+		(*Envelope_Order)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eventpb_event_proto_rawDesc), len(file_eventpb_event_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_eventpb_event_proto_goTypes,
+		DependencyIndexes: file_eventpb_event_proto_depIdxs,
+		MessageInfos:      file_eventpb_event_proto_msgTypes,
+	}.Build()
+	File_eventpb_event_proto = out.File
+	file_eventpb_event_proto_goTypes = nil
+	file_eventpb_event_proto_depIdxs = nil
+}
@@ -0,0 +1,622 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: kitchenpb/kitchen.proto
+
+package kitchenpb
+
+import (
+	orderpb "github.com/peiyouyao/gorder/common/genproto/orderpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Ticket struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	OrderID    string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	CustomerID string                 `protobuf:"bytes,2,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Status     string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"` // queued, cooking, plated or rejected
+	Items      []*TicketItem          `protobuf:"bytes,4,rep,name=Items,proto3" json:"Items,omitempty"`
+	Station    string                 `protobuf:"bytes,5,opt,name=Station,proto3" json:"Station,omitempty"`
+	ClaimedBy  string                 `protobuf:"bytes,6,opt,name=ClaimedBy,proto3" json:"ClaimedBy,omitempty"`
+	PrepTime   int64                  `protobuf:"varint,7,opt,name=PrepTime,proto3" json:"PrepTime,omitempty"` // seconds
+	// unix milliseconds, 0 when not yet
+	PaidAt        int64  `protobuf:"varint,8,opt,name=PaidAt,proto3" json:"PaidAt,omitempty"`
+	StartedAt     int64  `protobuf:"varint,9,opt,name=StartedAt,proto3" json:"StartedAt,omitempty"`
+	ReadyAt       int64  `protobuf:"varint,10,opt,name=ReadyAt,proto3" json:"ReadyAt,omitempty"`
+	PlatedAt      int64  `protobuf:"varint,11,opt,name=PlatedAt,proto3" json:"PlatedAt,omitempty"`
+	RejectReason  string `protobuf:"bytes,12,opt,name=RejectReason,proto3" json:"RejectReason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ticket) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *Ticket) GetCustomerID() string {
+	if x != nil {
+		return x.CustomerID
+	}
+	return ""
+}
+
+func (x *Ticket) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Ticket) GetItems() []*TicketItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Ticket) GetStation() string {
+	if x != nil {
+		return x.Station
+	}
+	return ""
+}
+
+func (x *Ticket) GetClaimedBy() string {
+	if x != nil {
+		return x.ClaimedBy
+	}
+	return ""
+}
+
+func (x *Ticket) GetPrepTime() int64 {
+	if x != nil {
+		return x.PrepTime
+	}
+	return 0
+}
+
+func (x *Ticket) GetPaidAt() int64 {
+	if x != nil {
+		return x.PaidAt
+	}
+	return 0
+}
+
+func (x *Ticket) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Ticket) GetReadyAt() int64 {
+	if x != nil {
+		return x.ReadyAt
+	}
+	return 0
+}
+
+func (x *Ticket) GetPlatedAt() int64 {
+	if x != nil {
+		return x.PlatedAt
+	}
+	return 0
+}
+
+func (x *Ticket) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
+
+type TicketItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *orderpb.Item          `protobuf:"bytes,1,opt,name=Item,proto3" json:"Item,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=Done,proto3" json:"Done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TicketItem) Reset() {
+	*x = TicketItem{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketItem) ProtoMessage() {}
+
+func (x *TicketItem) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketItem.ProtoReflect.Descriptor instead.
+func (*TicketItem) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TicketItem) GetItem() *orderpb.Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *TicketItem) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type ListTicketsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTicketsRequest) Reset() {
+	*x = ListTicketsRequest{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTicketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTicketsRequest) ProtoMessage() {}
+
+func (x *ListTicketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTicketsRequest.ProtoReflect.Descriptor instead.
+func (*ListTicketsRequest) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTicketsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListTicketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickets       []*Ticket              `protobuf:"bytes,1,rep,name=Tickets,proto3" json:"Tickets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTicketsResponse) Reset() {
+	*x = ListTicketsResponse{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTicketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTicketsResponse) ProtoMessage() {}
+
+func (x *ListTicketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTicketsResponse.ProtoReflect.Descriptor instead.
+func (*ListTicketsResponse) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTicketsResponse) GetTickets() []*Ticket {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+type ClaimTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	Staff         string                 `protobuf:"bytes,2,opt,name=Staff,proto3" json:"Staff,omitempty"`
+	Station       string                 `protobuf:"bytes,3,opt,name=Station,proto3" json:"Station,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimTicketRequest) Reset() {
+	*x = ClaimTicketRequest{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimTicketRequest) ProtoMessage() {}
+
+func (x *ClaimTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimTicketRequest.ProtoReflect.Descriptor instead.
+func (*ClaimTicketRequest) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClaimTicketRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *ClaimTicketRequest) GetStaff() string {
+	if x != nil {
+		return x.Staff
+	}
+	return ""
+}
+
+func (x *ClaimTicketRequest) GetStation() string {
+	if x != nil {
+		return x.Station
+	}
+	return ""
+}
+
+type MarkItemsDoneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	ItemIDs       []string               `protobuf:"bytes,2,rep,name=ItemIDs,proto3" json:"ItemIDs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkItemsDoneRequest) Reset() {
+	*x = MarkItemsDoneRequest{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkItemsDoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkItemsDoneRequest) ProtoMessage() {}
+
+func (x *MarkItemsDoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkItemsDoneRequest.ProtoReflect.Descriptor instead.
+func (*MarkItemsDoneRequest) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MarkItemsDoneRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *MarkItemsDoneRequest) GetItemIDs() []string {
+	if x != nil {
+		return x.ItemIDs
+	}
+	return nil
+}
+
+type BumpTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BumpTicketRequest) Reset() {
+	*x = BumpTicketRequest{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BumpTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BumpTicketRequest) ProtoMessage() {}
+
+func (x *BumpTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BumpTicketRequest.ProtoReflect.Descriptor instead.
+func (*BumpTicketRequest) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BumpTicketRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+type RejectTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectTicketRequest) Reset() {
+	*x = RejectTicketRequest{}
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTicketRequest) ProtoMessage() {}
+
+func (x *RejectTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitchenpb_kitchen_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTicketRequest.ProtoReflect.Descriptor instead.
+func (*RejectTicketRequest) Descriptor() ([]byte, []int) {
+	return file_kitchenpb_kitchen_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RejectTicketRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *RejectTicketRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_kitchenpb_kitchen_proto protoreflect.FileDescriptor
+
+const file_kitchenpb_kitchen_proto_rawDesc = "" +
+	"\n" +
+	"\x17kitchenpb/kitchen.proto\x12\tkitchenpb\x1a\x13orderpb/order.proto\"\xeb\x02\n" +
+	"\x06Ticket\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x1e\n" +
+	"\n" +
+	"CustomerID\x18\x02 \x01(\tR\n" +
+	"CustomerID\x12\x16\n" +
+	"\x06Status\x18\x03 \x01(\tR\x06Status\x12+\n" +
+	"\x05Items\x18\x04 \x03(\v2\x15.kitchenpb.TicketItemR\x05Items\x12\x18\n" +
+	"\aStation\x18\x05 \x01(\tR\aStation\x12\x1c\n" +
+	"\tClaimedBy\x18\x06 \x01(\tR\tClaimedBy\x12\x1a\n" +
+	"\bPrepTime\x18\a \x01(\x03R\bPrepTime\x12\x16\n" +
+	"\x06PaidAt\x18\b \x01(\x03R\x06PaidAt\x12\x1c\n" +
+	"\tStartedAt\x18\t \x01(\x03R\tStartedAt\x12\x18\n" +
+	"\aReadyAt\x18\n" +
+	" \x01(\x03R\aReadyAt\x12\x1a\n" +
+	"\bPlatedAt\x18\v \x01(\x03R\bPlatedAt\x12\"\n" +
+	"\fRejectReason\x18\f \x01(\tR\fRejectReason\"C\n" +
+	"\n" +
+	"TicketItem\x12!\n" +
+	"\x04Item\x18\x01 \x01(\v2\r.orderpb.ItemR\x04Item\x12\x12\n" +
+	"\x04Done\x18\x02 \x01(\bR\x04Done\"*\n" +
+	"\x12ListTicketsRequest\x12\x14\n" +
+	"\x05Limit\x18\x01 \x01(\x05R\x05Limit\"B\n" +
+	"\x13ListTicketsResponse\x12+\n" +
+	"\aTickets\x18\x01 \x03(\v2\x11.kitchenpb.TicketR\aTickets\"^\n" +
+	"\x12ClaimTicketRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x14\n" +
+	"\x05Staff\x18\x02 \x01(\tR\x05Staff\x12\x18\n" +
+	"\aStation\x18\x03 \x01(\tR\aStation\"J\n" +
+	"\x14MarkItemsDoneRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x18\n" +
+	"\aItemIDs\x18\x02 \x03(\tR\aItemIDs\"-\n" +
+	"\x11BumpTicketRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\"G\n" +
+	"\x13RejectTicketRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x16\n" +
+	"\x06Reason\x18\x02 \x01(\tR\x06Reason2\xe6\x02\n" +
+	"\x0eKitchenService\x12L\n" +
+	"\vListTickets\x12\x1d.kitchenpb.ListTicketsRequest\x1a\x1e.kitchenpb.ListTicketsResponse\x12?\n" +
+	"\vClaimTicket\x12\x1d.kitchenpb.ClaimTicketRequest\x1a\x11.kitchenpb.Ticket\x12C\n" +
+	"\rMarkItemsDone\x12\x1f.kitchenpb.MarkItemsDoneRequest\x1a\x11.kitchenpb.Ticket\x12=\n" +
+	"\n" +
+	"BumpTicket\x12\x1c.kitchenpb.BumpTicketRequest\x1a\x11.kitchenpb.Ticket\x12A\n" +
+	"\fRejectTicket\x12\x1e.kitchenpb.RejectTicketRequest\x1a\x11.kitchenpb.TicketB7Z5github.com/peiyouyao/gorder/common/genproto/kitchenpbb\x06proto3"
+
+var (
+	file_kitchenpb_kitchen_proto_rawDescOnce sync.Once
+	file_kitchenpb_kitchen_proto_rawDescData []byte
+)
+
+func file_kitchenpb_kitchen_proto_rawDescGZIP() []byte {
+	file_kitchenpb_kitchen_proto_rawDescOnce.Do(func() {
+		file_kitchenpb_kitchen_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kitchenpb_kitchen_proto_rawDesc), len(file_kitchenpb_kitchen_proto_rawDesc)))
+	})
+	return file_kitchenpb_kitchen_proto_rawDescData
+}
+
+var file_kitchenpb_kitchen_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_kitchenpb_kitchen_proto_goTypes = []any{
+	(*Ticket)(nil),               // 0: kitchenpb.Ticket
+	(*TicketItem)(nil),           // 1: kitchenpb.TicketItem
+	(*ListTicketsRequest)(nil),   // 2: kitchenpb.ListTicketsRequest
+	(*ListTicketsResponse)(nil),  // 3: kitchenpb.ListTicketsResponse
+	(*ClaimTicketRequest)(nil),   // 4: kitchenpb.ClaimTicketRequest
+	(*MarkItemsDoneRequest)(nil), // 5: kitchenpb.MarkItemsDoneRequest
+	(*BumpTicketRequest)(nil),    // 6: kitchenpb.BumpTicketRequest
+	(*RejectTicketRequest)(nil),  // 7: kitchenpb.RejectTicketRequest
+	(*orderpb.Item)(nil),         // 8: orderpb.Item
+}
+var file_kitchenpb_kitchen_proto_depIdxs = []int32{
+	1, // 0: kitchenpb.Ticket.Items:type_name -> kitchenpb.TicketItem
+	8, // 1: kitchenpb.TicketItem.Item:type_name -> orderpb.Item
+	0, // 2: kitchenpb.ListTicketsResponse.Tickets:type_name -> kitchenpb.Ticket
+	2, // 3: kitchenpb.KitchenService.ListTickets:input_type -> kitchenpb.ListTicketsRequest
+	4, // 4: kitchenpb.KitchenService.ClaimTicket:input_type -> kitchenpb.ClaimTicketRequest
+	5, // 5: kitchenpb.KitchenService.MarkItemsDone:input_type -> kitchenpb.MarkItemsDoneRequest
+	6, // 6: kitchenpb.KitchenService.BumpTicket:input_type -> kitchenpb.BumpTicketRequest
+	7, // 7: kitchenpb.KitchenService.RejectTicket:input_type -> kitchenpb.RejectTicketRequest
+	3, // 8: kitchenpb.KitchenService.ListTickets:output_type -> kitchenpb.ListTicketsResponse
+	0, // 9: kitchenpb.KitchenService.ClaimTicket:output_type -> kitchenpb.Ticket
+	0, // 10: kitchenpb.KitchenService.MarkItemsDone:output_type -> kitchenpb.Ticket
+	0, // 11: kitchenpb.KitchenService.BumpTicket:output_type -> kitchenpb.Ticket
+	0, // 12: kitchenpb.KitchenService.RejectTicket:output_type -> kitchenpb.Ticket
+	8, // [8:13] is the sub-list for method output_type
+	3, // [3:8] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_kitchenpb_kitchen_proto_init() }
+func file_kitchenpb_kitchen_proto_init() {
+	if File_kitchenpb_kitchen_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kitchenpb_kitchen_proto_rawDesc), len(file_kitchenpb_kitchen_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_kitchenpb_kitchen_proto_goTypes,
+		DependencyIndexes: file_kitchenpb_kitchen_proto_depIdxs,
+		MessageInfos:      file_kitchenpb_kitchen_proto_msgTypes,
+	}.Build()
+	File_kitchenpb_kitchen_proto = out.File
+	file_kitchenpb_kitchen_proto_goTypes = nil
+	file_kitchenpb_kitchen_proto_depIdxs = nil
+}
@@ -0,0 +1,283 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: kitchenpb/kitchen.proto
+
+package kitchenpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	KitchenService_ListTickets_FullMethodName   = "/kitchenpb.KitchenService/ListTickets"
+	KitchenService_ClaimTicket_FullMethodName   = "/kitchenpb.KitchenService/ClaimTicket"
+	KitchenService_MarkItemsDone_FullMethodName = "/kitchenpb.KitchenService/MarkItemsDone"
+	KitchenService_BumpTicket_FullMethodName    = "/kitchenpb.KitchenService/BumpTicket"
+	KitchenService_RejectTicket_FullMethodName  = "/kitchenpb.KitchenService/RejectTicket"
+)
+
+// KitchenServiceClient is the client API for KitchenService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Kitchen staff working the ticket queue, each call returns the ticket after the change.
+type KitchenServiceClient interface {
+	// Queued and cooking tickets, oldest paid first.
+	ListTickets(ctx context.Context, in *ListTicketsRequest, opts ...grpc.CallOption) (*ListTicketsResponse, error)
+	// A claimed ticket is no longer plated by the stations.
+	ClaimTicket(ctx context.Context, in *ClaimTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+	// The ticket is plated once every item is done.
+	MarkItemsDone(ctx context.Context, in *MarkItemsDoneRequest, opts ...grpc.CallOption) (*Ticket, error)
+	BumpTicket(ctx context.Context, in *BumpTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+	// The order is rejected and refunded.
+	RejectTicket(ctx context.Context, in *RejectTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+}
+
+type kitchenServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKitchenServiceClient(cc grpc.ClientConnInterface) KitchenServiceClient {
+	return &kitchenServiceClient{cc}
+}
+
+func (c *kitchenServiceClient) ListTickets(ctx context.Context, in *ListTicketsRequest, opts ...grpc.CallOption) (*ListTicketsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTicketsResponse)
+	err := c.cc.Invoke(ctx, KitchenService_ListTickets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kitchenServiceClient) ClaimTicket(ctx context.Context, in *ClaimTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, KitchenService_ClaimTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kitchenServiceClient) MarkItemsDone(ctx context.Context, in *MarkItemsDoneRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, KitchenService_MarkItemsDone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kitchenServiceClient) BumpTicket(ctx context.Context, in *BumpTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, KitchenService_BumpTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kitchenServiceClient) RejectTicket(ctx context.Context, in *RejectTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, KitchenService_RejectTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KitchenServiceServer is the server API for KitchenService service.
+// All implementations should embed UnimplementedKitchenServiceServer
+// for forward compatibility.
+//
+// Kitchen staff working the ticket queue, each call returns the ticket after the change.
+type KitchenServiceServer interface {
+	// Queued and cooking tickets, oldest paid first.
+	ListTickets(context.Context, *ListTicketsRequest) (*ListTicketsResponse, error)
+	// A claimed ticket is no longer plated by the stations.
+	ClaimTicket(context.Context, *ClaimTicketRequest) (*Ticket, error)
+	// The ticket is plated once every item is done.
+	MarkItemsDone(context.Context, *MarkItemsDoneRequest) (*Ticket, error)
+	BumpTicket(context.Context, *BumpTicketRequest) (*Ticket, error)
+	// The order is rejected and refunded.
+	RejectTicket(context.Context, *RejectTicketRequest) (*Ticket, error)
+}
+
+// UnimplementedKitchenServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKitchenServiceServer struct{}
+
+func (UnimplementedKitchenServiceServer) ListTickets(context.Context, *ListTicketsRequest) (*ListTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTickets not implemented")
+}
+func (UnimplementedKitchenServiceServer) ClaimTicket(context.Context, *ClaimTicketRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimTicket not implemented")
+}
+func (UnimplementedKitchenServiceServer) MarkItemsDone(context.Context, *MarkItemsDoneRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkItemsDone not implemented")
+}
+func (UnimplementedKitchenServiceServer) BumpTicket(context.Context, *BumpTicketRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpTicket not implemented")
+}
+func (UnimplementedKitchenServiceServer) RejectTicket(context.Context, *RejectTicketRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectTicket not implemented")
+}
+func (UnimplementedKitchenServiceServer) testEmbeddedByValue() {}
+
+// UnsafeKitchenServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KitchenServiceServer will
+// result in compilation errors.
+type UnsafeKitchenServiceServer interface {
+	mustEmbedUnimplementedKitchenServiceServer()
+}
+
+func RegisterKitchenServiceServer(s grpc.ServiceRegistrar, srv KitchenServiceServer) {
+	// If the following call pancis, it indicates UnimplementedKitchenServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&KitchenService_ServiceDesc, srv)
+}
+
+func _KitchenService_ListTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KitchenServiceServer).ListTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KitchenService_ListTickets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KitchenServiceServer).ListTickets(ctx, req.(*ListTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KitchenService_ClaimTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KitchenServiceServer).ClaimTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KitchenService_ClaimTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KitchenServiceServer).ClaimTicket(ctx, req.(*ClaimTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KitchenService_MarkItemsDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkItemsDoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KitchenServiceServer).MarkItemsDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KitchenService_MarkItemsDone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KitchenServiceServer).MarkItemsDone(ctx, req.(*MarkItemsDoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KitchenService_BumpTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KitchenServiceServer).BumpTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KitchenService_BumpTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KitchenServiceServer).BumpTicket(ctx, req.(*BumpTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KitchenService_RejectTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KitchenServiceServer).RejectTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KitchenService_RejectTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KitchenServiceServer).RejectTicket(ctx, req.(*RejectTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KitchenService_ServiceDesc is the grpc.ServiceDesc for KitchenService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KitchenService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kitchenpb.KitchenService",
+	HandlerType: (*KitchenServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTickets",
+			Handler:    _KitchenService_ListTickets_Handler,
+		},
+		{
+			MethodName: "ClaimTicket",
+			Handler:    _KitchenService_ClaimTicket_Handler,
+		},
+		{
+			MethodName: "MarkItemsDone",
+			Handler:    _KitchenService_MarkItemsDone_Handler,
+		},
+		{
+			MethodName: "BumpTicket",
+			Handler:    _KitchenService_BumpTicket_Handler,
+		},
+		{
+			MethodName: "RejectTicket",
+			Handler:    _KitchenService_RejectTicket_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "kitchenpb/kitchen.proto",
+}
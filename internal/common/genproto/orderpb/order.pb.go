@@ -23,11 +23,12 @@ const (
 )
 
 type CreateOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerID    string                 `protobuf:"bytes,1,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
-	Items         []*ItemWithQuantity    `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CustomerID     string                 `protobuf:"bytes,1,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Items          []*ItemWithQuantity    `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=IdempotencyKey,proto3" json:"IdempotencyKey,omitempty"` // same semantics as the Idempotency-Key http header
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
@@ -74,6 +75,57 @@ func (x *CreateOrderRequest) GetItems() []*ItemWithQuantity {
 	return nil
 }
 
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_orderpb_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateOrderResponse) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
@@ -84,7 +136,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_orderpb_order_proto_msgTypes[1]
+	mi := &file_orderpb_order_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -96,7 +148,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orderpb_order_proto_msgTypes[1]
+	mi := &file_orderpb_order_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -109,7 +161,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_orderpb_order_proto_rawDescGZIP(), []int{1}
+	return file_orderpb_order_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetOrderRequest) GetOrderID() string {
@@ -126,6 +178,406 @@ func (x *GetOrderRequest) GetCustomerID() string {
 	return ""
 }
 
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	CustomerID    string                 `protobuf:"bytes,2,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_orderpb_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelOrderRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetCustomerID() string {
+	if x != nil {
+		return x.CustomerID
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	CustomerID    string                 `protobuf:"bytes,2,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=Reason,proto3" json:"Reason,omitempty"` // required
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectOrderRequest) Reset() {
+	*x = RejectOrderRequest{}
+	mi := &file_orderpb_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectOrderRequest) ProtoMessage() {}
+
+func (x *RejectOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectOrderRequest.ProtoReflect.Descriptor instead.
+func (*RejectOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RejectOrderRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *RejectOrderRequest) GetCustomerID() string {
+	if x != nil {
+		return x.CustomerID
+	}
+	return ""
+}
+
+func (x *RejectOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerID    string                 `protobuf:"bytes,1,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Statuses      []string               `protobuf:"bytes,2,rep,name=Statuses,proto3" json:"Statuses,omitempty"`
+	CreatedAfter  int64                  `protobuf:"varint,3,opt,name=CreatedAfter,proto3" json:"CreatedAfter,omitempty"`   // unix seconds, inclusive, 0 means unbounded
+	CreatedBefore int64                  `protobuf:"varint,4,opt,name=CreatedBefore,proto3" json:"CreatedBefore,omitempty"` // unix seconds, exclusive, 0 means unbounded
+	Cursor        string                 `protobuf:"bytes,5,opt,name=Cursor,proto3" json:"Cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_orderpb_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListOrdersRequest) GetCustomerID() string {
+	if x != nil {
+		return x.CustomerID
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=Orders,proto3" json:"Orders,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_orderpb_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type OrderHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*OrderHistoryEntry   `protobuf:"bytes,1,rep,name=Entries,proto3" json:"Entries,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderHistory) Reset() {
+	*x = OrderHistory{}
+	mi := &file_orderpb_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderHistory) ProtoMessage() {}
+
+func (x *OrderHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderHistory.ProtoReflect.Descriptor instead.
+func (*OrderHistory) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderHistory) GetEntries() []*OrderHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type OrderHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"` // 1 for created, one more per change
+	Type          string                 `protobuf:"bytes,2,opt,name=Type,proto3" json:"Type,omitempty"`        // created, status_changed, payment_link_changed or estimated_ready_at_changed
+	Old           string                 `protobuf:"bytes,3,opt,name=Old,proto3" json:"Old,omitempty"`
+	New           string                 `protobuf:"bytes,4,opt,name=New,proto3" json:"New,omitempty"`
+	Items         []*Item                `protobuf:"bytes,5,rep,name=Items,proto3" json:"Items,omitempty"` // only on created
+	At            int64                  `protobuf:"varint,6,opt,name=At,proto3" json:"At,omitempty"`      // unix milliseconds
+	Actor         string                 `protobuf:"bytes,7,opt,name=Actor,proto3" json:"Actor,omitempty"` // service that made the change
+	TraceID       string                 `protobuf:"bytes,8,opt,name=TraceID,proto3" json:"TraceID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderHistoryEntry) Reset() {
+	*x = OrderHistoryEntry{}
+	mi := &file_orderpb_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderHistoryEntry) ProtoMessage() {}
+
+func (x *OrderHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_orderpb_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderHistoryEntry.ProtoReflect.Descriptor instead.
+func (*OrderHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_orderpb_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderHistoryEntry) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *OrderHistoryEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *OrderHistoryEntry) GetOld() string {
+	if x != nil {
+		return x.Old
+	}
+	return ""
+}
+
+func (x *OrderHistoryEntry) GetNew() string {
+	if x != nil {
+		return x.New
+	}
+	return ""
+}
+
+func (x *OrderHistoryEntry) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderHistoryEntry) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *OrderHistoryEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *OrderHistoryEntry) GetTraceID() string {
+	if x != nil {
+		return x.TraceID
+	}
+	return ""
+}
+
 type ItemWithQuantity struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
@@ -136,7 +588,7 @@ type ItemWithQuantity struct {
 
 func (x *ItemWithQuantity) Reset() {
 	*x = ItemWithQuantity{}
-	mi := &file_orderpb_order_proto_msgTypes[2]
+	mi := &file_orderpb_order_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -148,7 +600,7 @@ func (x *ItemWithQuantity) String() string {
 func (*ItemWithQuantity) ProtoMessage() {}
 
 func (x *ItemWithQuantity) ProtoReflect() protoreflect.Message {
-	mi := &file_orderpb_order_proto_msgTypes[2]
+	mi := &file_orderpb_order_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,7 +613,7 @@ func (x *ItemWithQuantity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ItemWithQuantity.ProtoReflect.Descriptor instead.
 func (*ItemWithQuantity) Descriptor() ([]byte, []int) {
-	return file_orderpb_order_proto_rawDescGZIP(), []int{2}
+	return file_orderpb_order_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ItemWithQuantity) GetID() string {
@@ -190,7 +642,7 @@ type Item struct {
 
 func (x *Item) Reset() {
 	*x = Item{}
-	mi := &file_orderpb_order_proto_msgTypes[3]
+	mi := &file_orderpb_order_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -202,7 +654,7 @@ func (x *Item) String() string {
 func (*Item) ProtoMessage() {}
 
 func (x *Item) ProtoReflect() protoreflect.Message {
-	mi := &file_orderpb_order_proto_msgTypes[3]
+	mi := &file_orderpb_order_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -215,7 +667,7 @@ func (x *Item) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Item.ProtoReflect.Descriptor instead.
 func (*Item) Descriptor() ([]byte, []int) {
-	return file_orderpb_order_proto_rawDescGZIP(), []int{3}
+	return file_orderpb_order_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Item) GetID() string {
@@ -247,19 +699,24 @@ func (x *Item) GetPriceID() string {
 }
 
 type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	CustomerID    string                 `protobuf:"bytes,2,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
-	Items         []*Item                `protobuf:"bytes,4,rep,name=Items,proto3" json:"Items,omitempty"`
-	PaymentLink   string                 `protobuf:"bytes,5,opt,name=PaymentLink,proto3" json:"PaymentLink,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ID          string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	CustomerID  string                 `protobuf:"bytes,2,opt,name=CustomerID,proto3" json:"CustomerID,omitempty"`
+	Status      string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	Items       []*Item                `protobuf:"bytes,4,rep,name=Items,proto3" json:"Items,omitempty"`
+	PaymentLink string                 `protobuf:"bytes,5,opt,name=PaymentLink,proto3" json:"PaymentLink,omitempty"`
+	// unix milliseconds, 0 when not yet
+	CreatedAt        int64 `protobuf:"varint,6,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	PaidAt           int64 `protobuf:"varint,7,opt,name=PaidAt,proto3" json:"PaidAt,omitempty"`
+	EstimatedReadyAt int64 `protobuf:"varint,8,opt,name=EstimatedReadyAt,proto3" json:"EstimatedReadyAt,omitempty"` // estimated by the kitchen, the only time UpdateOrder changes
+	ReadyAt          int64 `protobuf:"varint,9,opt,name=ReadyAt,proto3" json:"ReadyAt,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_orderpb_order_proto_msgTypes[4]
+	mi := &file_orderpb_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -271,7 +728,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_orderpb_order_proto_msgTypes[4]
+	mi := &file_orderpb_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,7 +741,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_orderpb_order_proto_rawDescGZIP(), []int{4}
+	return file_orderpb_order_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Order) GetID() string {
@@ -322,21 +779,89 @@ func (x *Order) GetPaymentLink() string {
 	return ""
 }
 
+func (x *Order) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Order) GetPaidAt() int64 {
+	if x != nil {
+		return x.PaidAt
+	}
+	return 0
+}
+
+func (x *Order) GetEstimatedReadyAt() int64 {
+	if x != nil {
+		return x.EstimatedReadyAt
+	}
+	return 0
+}
+
+func (x *Order) GetReadyAt() int64 {
+	if x != nil {
+		return x.ReadyAt
+	}
+	return 0
+}
+
 var File_orderpb_order_proto protoreflect.FileDescriptor
 
 const file_orderpb_order_proto_rawDesc = "" +
 	"\n" +
-	"\x13orderpb/order.proto\x12\aorderpb\x1a\x1bgoogle/protobuf/empty.proto\"e\n" +
+	"\x13orderpb/order.proto\x12\aorderpb\x1a\x1bgoogle/protobuf/empty.proto\"\x8d\x01\n" +
 	"\x12CreateOrderRequest\x12\x1e\n" +
 	"\n" +
 	"CustomerID\x18\x01 \x01(\tR\n" +
 	"CustomerID\x12/\n" +
-	"\x05Items\x18\x02 \x03(\v2\x19.orderpb.ItemWithQuantityR\x05Items\"K\n" +
+	"\x05Items\x18\x02 \x03(\v2\x19.orderpb.ItemWithQuantityR\x05Items\x12&\n" +
+	"\x0eIdempotencyKey\x18\x03 \x01(\tR\x0eIdempotencyKey\"/\n" +
+	"\x13CreateOrderResponse\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\"K\n" +
 	"\x0fGetOrderRequest\x12\x18\n" +
 	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x1e\n" +
 	"\n" +
 	"CustomerID\x18\x02 \x01(\tR\n" +
-	"CustomerID\">\n" +
+	"CustomerID\"f\n" +
+	"\x12CancelOrderRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x1e\n" +
+	"\n" +
+	"CustomerID\x18\x02 \x01(\tR\n" +
+	"CustomerID\x12\x16\n" +
+	"\x06Reason\x18\x03 \x01(\tR\x06Reason\"f\n" +
+	"\x12RejectOrderRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x1e\n" +
+	"\n" +
+	"CustomerID\x18\x02 \x01(\tR\n" +
+	"CustomerID\x12\x16\n" +
+	"\x06Reason\x18\x03 \x01(\tR\x06Reason\"\xc7\x01\n" +
+	"\x11ListOrdersRequest\x12\x1e\n" +
+	"\n" +
+	"CustomerID\x18\x01 \x01(\tR\n" +
+	"CustomerID\x12\x1a\n" +
+	"\bStatuses\x18\x02 \x03(\tR\bStatuses\x12\"\n" +
+	"\fCreatedAfter\x18\x03 \x01(\x03R\fCreatedAfter\x12$\n" +
+	"\rCreatedBefore\x18\x04 \x01(\x03R\rCreatedBefore\x12\x16\n" +
+	"\x06Cursor\x18\x05 \x01(\tR\x06Cursor\x12\x14\n" +
+	"\x05Limit\x18\x06 \x01(\x05R\x05Limit\"\\\n" +
+	"\x12ListOrdersResponse\x12&\n" +
+	"\x06Orders\x18\x01 \x03(\v2\x0e.orderpb.OrderR\x06Orders\x12\x1e\n" +
+	"\n" +
+	"NextCursor\x18\x02 \x01(\tR\n" +
+	"NextCursor\"D\n" +
+	"\fOrderHistory\x124\n" +
+	"\aEntries\x18\x01 \x03(\v2\x1a.orderpb.OrderHistoryEntryR\aEntries\"\xca\x01\n" +
+	"\x11OrderHistoryEntry\x12\x18\n" +
+	"\aVersion\x18\x01 \x01(\x03R\aVersion\x12\x12\n" +
+	"\x04Type\x18\x02 \x01(\tR\x04Type\x12\x10\n" +
+	"\x03Old\x18\x03 \x01(\tR\x03Old\x12\x10\n" +
+	"\x03New\x18\x04 \x01(\tR\x03New\x12#\n" +
+	"\x05Items\x18\x05 \x03(\v2\r.orderpb.ItemR\x05Items\x12\x0e\n" +
+	"\x02At\x18\x06 \x01(\x03R\x02At\x12\x14\n" +
+	"\x05Actor\x18\a \x01(\tR\x05Actor\x12\x18\n" +
+	"\aTraceID\x18\b \x01(\tR\aTraceID\">\n" +
 	"\x10ItemWithQuantity\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1a\n" +
 	"\bQuantity\x18\x02 \x01(\x05R\bQuantity\"`\n" +
@@ -344,7 +869,7 @@ const file_orderpb_order_proto_rawDesc = "" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x12\n" +
 	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1a\n" +
 	"\bQuantity\x18\x03 \x01(\x05R\bQuantity\x12\x18\n" +
-	"\aPriceID\x18\x04 \x01(\tR\aPriceID\"\x96\x01\n" +
+	"\aPriceID\x18\x04 \x01(\tR\aPriceID\"\x92\x02\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1e\n" +
 	"\n" +
@@ -352,11 +877,22 @@ const file_orderpb_order_proto_rawDesc = "" +
 	"CustomerID\x12\x16\n" +
 	"\x06Status\x18\x03 \x01(\tR\x06Status\x12#\n" +
 	"\x05Items\x18\x04 \x03(\v2\r.orderpb.ItemR\x05Items\x12 \n" +
-	"\vPaymentLink\x18\x05 \x01(\tR\vPaymentLink2\xbf\x01\n" +
-	"\fOrderService\x12B\n" +
-	"\vCreateOrder\x12\x1b.orderpb.CreateOrderRequest\x1a\x16.google.protobuf.Empty\x124\n" +
+	"\vPaymentLink\x18\x05 \x01(\tR\vPaymentLink\x12\x1c\n" +
+	"\tCreatedAt\x18\x06 \x01(\x03R\tCreatedAt\x12\x16\n" +
+	"\x06PaidAt\x18\a \x01(\x03R\x06PaidAt\x12*\n" +
+	"\x10EstimatedReadyAt\x18\b \x01(\x03R\x10EstimatedReadyAt\x12\x18\n" +
+	"\aReadyAt\x18\t \x01(\x03R\aReadyAt2\x92\x04\n" +
+	"\fOrderService\x12H\n" +
+	"\vCreateOrder\x12\x1b.orderpb.CreateOrderRequest\x1a\x1c.orderpb.CreateOrderResponse\x124\n" +
 	"\bGetOrder\x12\x18.orderpb.GetOrderRequest\x1a\x0e.orderpb.Order\x125\n" +
-	"\vUpdateOrder\x12\x0e.orderpb.Order\x1a\x16.google.protobuf.EmptyB5Z3github.com/peiyouyao/gorder/common/genproto/orderpbb\x06proto3"
+	"\vUpdateOrder\x12\x0e.orderpb.Order\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\vCancelOrder\x12\x1b.orderpb.CancelOrderRequest\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\vRejectOrder\x12\x1b.orderpb.RejectOrderRequest\x1a\x16.google.protobuf.Empty\x12E\n" +
+	"\n" +
+	"ListOrders\x12\x1a.orderpb.ListOrdersRequest\x1a\x1b.orderpb.ListOrdersResponse\x12B\n" +
+	"\x0fGetOrderHistory\x12\x18.orderpb.GetOrderRequest\x1a\x15.orderpb.OrderHistory\x128\n" +
+	"\n" +
+	"WatchOrder\x12\x18.orderpb.GetOrderRequest\x1a\x0e.orderpb.Order0\x01B5Z3github.com/peiyouyao/gorder/common/genproto/orderpbb\x06proto3"
 
 var (
 	file_orderpb_order_proto_rawDescOnce sync.Once
@@ -370,29 +906,49 @@ func file_orderpb_order_proto_rawDescGZIP() []byte {
 	return file_orderpb_order_proto_rawDescData
 }
 
-var file_orderpb_order_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_orderpb_order_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_orderpb_order_proto_goTypes = []any{
-	(*CreateOrderRequest)(nil), // 0: orderpb.CreateOrderRequest
-	(*GetOrderRequest)(nil),    // 1: orderpb.GetOrderRequest
-	(*ItemWithQuantity)(nil),   // 2: orderpb.ItemWithQuantity
-	(*Item)(nil),               // 3: orderpb.Item
-	(*Order)(nil),              // 4: orderpb.Order
-	(*emptypb.Empty)(nil),      // 5: google.protobuf.Empty
+	(*CreateOrderRequest)(nil),  // 0: orderpb.CreateOrderRequest
+	(*CreateOrderResponse)(nil), // 1: orderpb.CreateOrderResponse
+	(*GetOrderRequest)(nil),     // 2: orderpb.GetOrderRequest
+	(*CancelOrderRequest)(nil),  // 3: orderpb.CancelOrderRequest
+	(*RejectOrderRequest)(nil),  // 4: orderpb.RejectOrderRequest
+	(*ListOrdersRequest)(nil),   // 5: orderpb.ListOrdersRequest
+	(*ListOrdersResponse)(nil),  // 6: orderpb.ListOrdersResponse
+	(*OrderHistory)(nil),        // 7: orderpb.OrderHistory
+	(*OrderHistoryEntry)(nil),   // 8: orderpb.OrderHistoryEntry
+	(*ItemWithQuantity)(nil),    // 9: orderpb.ItemWithQuantity
+	(*Item)(nil),                // 10: orderpb.Item
+	(*Order)(nil),               // 11: orderpb.Order
+	(*emptypb.Empty)(nil),       // 12: google.protobuf.Empty
 }
 var file_orderpb_order_proto_depIdxs = []int32{
-	2, // 0: orderpb.CreateOrderRequest.Items:type_name -> orderpb.ItemWithQuantity
-	3, // 1: orderpb.Order.Items:type_name -> orderpb.Item
-	0, // 2: orderpb.OrderService.CreateOrder:input_type -> orderpb.CreateOrderRequest
-	1, // 3: orderpb.OrderService.GetOrder:input_type -> orderpb.GetOrderRequest
-	4, // 4: orderpb.OrderService.UpdateOrder:input_type -> orderpb.Order
-	5, // 5: orderpb.OrderService.CreateOrder:output_type -> google.protobuf.Empty
-	4, // 6: orderpb.OrderService.GetOrder:output_type -> orderpb.Order
-	5, // 7: orderpb.OrderService.UpdateOrder:output_type -> google.protobuf.Empty
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	9,  // 0: orderpb.CreateOrderRequest.Items:type_name -> orderpb.ItemWithQuantity
+	11, // 1: orderpb.ListOrdersResponse.Orders:type_name -> orderpb.Order
+	8,  // 2: orderpb.OrderHistory.Entries:type_name -> orderpb.OrderHistoryEntry
+	10, // 3: orderpb.OrderHistoryEntry.Items:type_name -> orderpb.Item
+	10, // 4: orderpb.Order.Items:type_name -> orderpb.Item
+	0,  // 5: orderpb.OrderService.CreateOrder:input_type -> orderpb.CreateOrderRequest
+	2,  // 6: orderpb.OrderService.GetOrder:input_type -> orderpb.GetOrderRequest
+	11, // 7: orderpb.OrderService.UpdateOrder:input_type -> orderpb.Order
+	3,  // 8: orderpb.OrderService.CancelOrder:input_type -> orderpb.CancelOrderRequest
+	4,  // 9: orderpb.OrderService.RejectOrder:input_type -> orderpb.RejectOrderRequest
+	5,  // 10: orderpb.OrderService.ListOrders:input_type -> orderpb.ListOrdersRequest
+	2,  // 11: orderpb.OrderService.GetOrderHistory:input_type -> orderpb.GetOrderRequest
+	2,  // 12: orderpb.OrderService.WatchOrder:input_type -> orderpb.GetOrderRequest
+	1,  // 13: orderpb.OrderService.CreateOrder:output_type -> orderpb.CreateOrderResponse
+	11, // 14: orderpb.OrderService.GetOrder:output_type -> orderpb.Order
+	12, // 15: orderpb.OrderService.UpdateOrder:output_type -> google.protobuf.Empty
+	12, // 16: orderpb.OrderService.CancelOrder:output_type -> google.protobuf.Empty
+	12, // 17: orderpb.OrderService.RejectOrder:output_type -> google.protobuf.Empty
+	6,  // 18: orderpb.OrderService.ListOrders:output_type -> orderpb.ListOrdersResponse
+	7,  // 19: orderpb.OrderService.GetOrderHistory:output_type -> orderpb.OrderHistory
+	11, // 20: orderpb.OrderService.WatchOrder:output_type -> orderpb.Order
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_orderpb_order_proto_init() }
@@ -406,7 +962,7 @@ func file_orderpb_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orderpb_order_proto_rawDesc), len(file_orderpb_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
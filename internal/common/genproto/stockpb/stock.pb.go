@@ -10,6 +10,7 @@ import (
 	orderpb "github.com/peiyouyao/gorder/common/genproto/orderpb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -206,11 +207,203 @@ func (x *CheckIfItemsInStockResponse) GetItems() []*orderpb.Item {
 	return nil
 }
 
+type ReserveItemsRequest struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	OrderID       string                      `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	Items         []*orderpb.ItemWithQuantity `protobuf:"bytes,2,rep,name=Items,proto3" json:"Items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveItemsRequest) Reset() {
+	*x = ReserveItemsRequest{}
+	mi := &file_stockpb_stock_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveItemsRequest) ProtoMessage() {}
+
+func (x *ReserveItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockpb_stock_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveItemsRequest.ProtoReflect.Descriptor instead.
+func (*ReserveItemsRequest) Descriptor() ([]byte, []int) {
+	return file_stockpb_stock_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReserveItemsRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *ReserveItemsRequest) GetItems() []*orderpb.ItemWithQuantity {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ReserveItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"` // unix seconds, the reservation is released automatically after it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveItemsResponse) Reset() {
+	*x = ReserveItemsResponse{}
+	mi := &file_stockpb_stock_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveItemsResponse) ProtoMessage() {}
+
+func (x *ReserveItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stockpb_stock_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveItemsResponse.ProtoReflect.Descriptor instead.
+func (*ReserveItemsResponse) Descriptor() ([]byte, []int) {
+	return file_stockpb_stock_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReserveItemsResponse) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *ReserveItemsResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_stockpb_stock_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockpb_stock_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_stockpb_stock_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommitReservationRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_stockpb_stock_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockpb_stock_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_stockpb_stock_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReleaseReservationRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
 var File_stockpb_stock_proto protoreflect.FileDescriptor
 
 const file_stockpb_stock_proto_rawDesc = "" +
 	"\n" +
-	"\x13stockpb/stock.proto\x12\astockpb\x1a\x13orderpb/order.proto\"+\n" +
+	"\x13stockpb/stock.proto\x12\astockpb\x1a\x13orderpb/order.proto\x1a\x1bgoogle/protobuf/empty.proto\"+\n" +
 	"\x0fGetItemsRequest\x12\x18\n" +
 	"\aItemIDs\x18\x01 \x03(\tR\aItemIDs\"7\n" +
 	"\x10GetItemsResponse\x12#\n" +
@@ -219,10 +412,23 @@ const file_stockpb_stock_proto_rawDesc = "" +
 	"\x05Items\x18\x01 \x03(\v2\x19.orderpb.ItemWithQuantityR\x05Items\"\\\n" +
 	"\x1bCheckIfItemsInStockResponse\x12\x18\n" +
 	"\aInStock\x18\x01 \x01(\x05R\aInStock\x12#\n" +
-	"\x05Items\x18\x02 \x03(\v2\r.orderpb.ItemR\x05Items2\xb1\x01\n" +
+	"\x05Items\x18\x02 \x03(\v2\r.orderpb.ItemR\x05Items\"`\n" +
+	"\x13ReserveItemsRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12/\n" +
+	"\x05Items\x18\x02 \x03(\v2\x19.orderpb.ItemWithQuantityR\x05Items\"N\n" +
+	"\x14ReserveItemsResponse\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\x12\x1c\n" +
+	"\tExpiresAt\x18\x02 \x01(\x03R\tExpiresAt\"4\n" +
+	"\x18CommitReservationRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID\"5\n" +
+	"\x19ReleaseReservationRequest\x12\x18\n" +
+	"\aOrderID\x18\x01 \x01(\tR\aOrderID2\xa0\x03\n" +
 	"\fStockService\x12?\n" +
 	"\bGetItems\x12\x18.stockpb.GetItemsRequest\x1a\x19.stockpb.GetItemsResponse\x12`\n" +
-	"\x13CheckIfItemsInStock\x12#.stockpb.CheckIfItemsInStockRequest\x1a$.stockpb.CheckIfItemsInStockResponseB5Z3github.com/peiyouyao/gorder/common/genproto/stockpbb\x06proto3"
+	"\x13CheckIfItemsInStock\x12#.stockpb.CheckIfItemsInStockRequest\x1a$.stockpb.CheckIfItemsInStockResponse\x12K\n" +
+	"\fReserveItems\x12\x1c.stockpb.ReserveItemsRequest\x1a\x1d.stockpb.ReserveItemsResponse\x12N\n" +
+	"\x11CommitReservation\x12!.stockpb.CommitReservationRequest\x1a\x16.google.protobuf.Empty\x12P\n" +
+	"\x12ReleaseReservation\x12\".stockpb.ReleaseReservationRequest\x1a\x16.google.protobuf.EmptyB5Z3github.com/peiyouyao/gorder/common/genproto/stockpbb\x06proto3"
 
 var (
 	file_stockpb_stock_proto_rawDescOnce sync.Once
@@ -236,28 +442,40 @@ func file_stockpb_stock_proto_rawDescGZIP() []byte {
 	return file_stockpb_stock_proto_rawDescData
 }
 
-var file_stockpb_stock_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_stockpb_stock_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_stockpb_stock_proto_goTypes = []any{
 	(*GetItemsRequest)(nil),             // 0: stockpb.GetItemsRequest
 	(*GetItemsResponse)(nil),            // 1: stockpb.GetItemsResponse
 	(*CheckIfItemsInStockRequest)(nil),  // 2: stockpb.CheckIfItemsInStockRequest
 	(*CheckIfItemsInStockResponse)(nil), // 3: stockpb.CheckIfItemsInStockResponse
-	(*orderpb.Item)(nil),                // 4: orderpb.Item
-	(*orderpb.ItemWithQuantity)(nil),    // 5: orderpb.ItemWithQuantity
+	(*ReserveItemsRequest)(nil),         // 4: stockpb.ReserveItemsRequest
+	(*ReserveItemsResponse)(nil),        // 5: stockpb.ReserveItemsResponse
+	(*CommitReservationRequest)(nil),    // 6: stockpb.CommitReservationRequest
+	(*ReleaseReservationRequest)(nil),   // 7: stockpb.ReleaseReservationRequest
+	(*orderpb.Item)(nil),                // 8: orderpb.Item
+	(*orderpb.ItemWithQuantity)(nil),    // 9: orderpb.ItemWithQuantity
+	(*emptypb.Empty)(nil),               // 10: google.protobuf.Empty
 }
 var file_stockpb_stock_proto_depIdxs = []int32{
-	4, // 0: stockpb.GetItemsResponse.Items:type_name -> orderpb.Item
-	5, // 1: stockpb.CheckIfItemsInStockRequest.Items:type_name -> orderpb.ItemWithQuantity
-	4, // 2: stockpb.CheckIfItemsInStockResponse.Items:type_name -> orderpb.Item
-	0, // 3: stockpb.StockService.GetItems:input_type -> stockpb.GetItemsRequest
-	2, // 4: stockpb.StockService.CheckIfItemsInStock:input_type -> stockpb.CheckIfItemsInStockRequest
-	1, // 5: stockpb.StockService.GetItems:output_type -> stockpb.GetItemsResponse
-	3, // 6: stockpb.StockService.CheckIfItemsInStock:output_type -> stockpb.CheckIfItemsInStockResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	8,  // 0: stockpb.GetItemsResponse.Items:type_name -> orderpb.Item
+	9,  // 1: stockpb.CheckIfItemsInStockRequest.Items:type_name -> orderpb.ItemWithQuantity
+	8,  // 2: stockpb.CheckIfItemsInStockResponse.Items:type_name -> orderpb.Item
+	9,  // 3: stockpb.ReserveItemsRequest.Items:type_name -> orderpb.ItemWithQuantity
+	0,  // 4: stockpb.StockService.GetItems:input_type -> stockpb.GetItemsRequest
+	2,  // 5: stockpb.StockService.CheckIfItemsInStock:input_type -> stockpb.CheckIfItemsInStockRequest
+	4,  // 6: stockpb.StockService.ReserveItems:input_type -> stockpb.ReserveItemsRequest
+	6,  // 7: stockpb.StockService.CommitReservation:input_type -> stockpb.CommitReservationRequest
+	7,  // 8: stockpb.StockService.ReleaseReservation:input_type -> stockpb.ReleaseReservationRequest
+	1,  // 9: stockpb.StockService.GetItems:output_type -> stockpb.GetItemsResponse
+	3,  // 10: stockpb.StockService.CheckIfItemsInStock:output_type -> stockpb.CheckIfItemsInStockResponse
+	5,  // 11: stockpb.StockService.ReserveItems:output_type -> stockpb.ReserveItemsResponse
+	10, // 12: stockpb.StockService.CommitReservation:output_type -> google.protobuf.Empty
+	10, // 13: stockpb.StockService.ReleaseReservation:output_type -> google.protobuf.Empty
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_stockpb_stock_proto_init() }
@@ -271,7 +489,7 @@ func file_stockpb_stock_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stockpb_stock_proto_rawDesc), len(file_stockpb_stock_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
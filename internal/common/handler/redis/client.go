@@ -9,6 +9,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var ErrNil = redis.Nil
+
 func SetNX(ctx context.Context, client *redis.Client, key, value string, ttl time.Duration) (err error) {
 	now := time.Now()
 	defer func() {
@@ -33,6 +35,78 @@ func SetNX(ctx context.Context, client *redis.Client, key, value string, ttl tim
 	return err
 }
 
+// TrySetNX is SetNX that also reports whether the key was set, false means it already existed.
+func TrySetNX(ctx context.Context, client *redis.Client, key, value string, ttl time.Duration) (ok bool, err error) {
+	now := time.Now()
+	defer func() {
+		l := logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"start": now,
+			"key":   key,
+			"value": value,
+			"set":   ok,
+			"err":   err,
+			"cost":  time.Since(now).Milliseconds(),
+		})
+		if err == nil {
+			l.Info("Redis setnx ok")
+		} else {
+			l.Warn("Redis setnx fail")
+		}
+	}()
+
+	if client == nil {
+		return false, errors.New("redis client is nil")
+	}
+	return client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func Set(ctx context.Context, client *redis.Client, key, value string, ttl time.Duration) (err error) {
+	now := time.Now()
+	defer func() {
+		l := logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"start": now,
+			"key":   key,
+			"value": value,
+			"err":   err,
+			"cost":  time.Since(now).Milliseconds(),
+		})
+		if err == nil {
+			l.Info("Redis set ok")
+		} else {
+			l.Warn("Redis set fail")
+		}
+	}()
+
+	if client == nil {
+		return errors.New("redis client is nil")
+	}
+	return client.Set(ctx, key, value, ttl).Err()
+}
+
+// Get returns ErrNil when the key does not exist.
+func Get(ctx context.Context, client *redis.Client, key string) (value string, err error) {
+	now := time.Now()
+	defer func() {
+		l := logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"start": now,
+			"key":   key,
+			"value": value,
+			"err":   err,
+			"cost":  time.Since(now).Milliseconds(),
+		})
+		if err == nil || errors.Is(err, ErrNil) {
+			l.Info("Redis get ok")
+		} else {
+			l.Warn("Redis get fail")
+		}
+	}()
+
+	if client == nil {
+		return "", errors.New("redis client is nil")
+	}
+	return client.Get(ctx, key).Result()
+}
+
 func Del(ctx context.Context, client *redis.Client, key string) (err error) {
 	now := time.Now()
 	defer func() {
@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MongoDB 在调用 mongo 前调用, 返回的 dlog 在调用结束后记录结果, 错误和耗时
+func MongoDB(ctx context.Context, method string, fields logrus.Fields) (dlog func(resp any, err error)) {
+	start := time.Now()
+
+	// 拷贝字段，避免外部修改污染
+	fs := make(logrus.Fields, len(fields))
+	maps.Copy(fs, fields)
+
+	dlog = func(res any, err error) {
+		fs["mongo_res"] = res
+		fs["mongo_time_cost"] = time.Since(start)
+		if err == nil {
+			logrus.WithContext(ctx).WithFields(fs).Infof("%s ok", method)
+		} else {
+			fs["mongo_err"] = err.Error()
+			logrus.WithContext(ctx).WithFields(fs).Errorf("%s fail", method)
+		}
+	}
+	return
+}
@@ -5,12 +5,19 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/handler/errors"
 	"github.com/peiyouyao/gorder/common/tracing"
 )
 
 type BaseResponse struct{}
 
+// errors are reported with 200 unless listed here
+var errnoHTTPStatus = map[int]int{
+	constants.ErrnoNotFound: http.StatusNotFound,
+	constants.ErrnoConflict: http.StatusConflict,
+}
+
 type response struct {
 	Errno   int    `json:"errno"`
 	Message string `json:"message"`
@@ -47,7 +54,11 @@ func (r *BaseResponse) error(c *gin.Context, err error) {
 		Data:    nil,
 		TraceID: tracing.TraceID(c.Request.Context()),
 	}
-	c.JSON(http.StatusOK, resp)
+	httpStatus, ok := errnoHTTPStatus[errno]
+	if !ok {
+		httpStatus = http.StatusOK
+	}
+	c.JSON(httpStatus, resp)
 	rJson, _ := json.Marshal(resp)
 	c.Set("response", rJson)
 }
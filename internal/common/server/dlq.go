@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/handler/errors"
+	"github.com/peiyouyao/gorder/common/response"
+	"github.com/spf13/viper"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+/*
+RegisterDLQAdmin 挂载 dlq 管理接口, 请求头 X-Admin-Token 必须等于 admin-token, 没配置 admin-token 时全部拒绝.
+
+	GET  /dlq?limit=100  列出 dlq 中的消息
+	GET  /dlq/export     以 JSON lines 导出
+	POST /dlq/replay     {"ids": [...]} 或 {"all": true}, 投递回原来的 exchange / routing key
+	POST /dlq/purge      同上, 从 dlq 中删除
+*/
+func RegisterDLQAdmin(router gin.IRouter, q *broker.DLQ) {
+	h := &dlqAdmin{q: q}
+	g := router.Group("/dlq", adminAuth)
+	g.GET("", h.list)
+	g.GET("/export", h.export)
+	g.POST("/replay", h.replay)
+	g.POST("/purge", h.purge)
+}
+
+type dlqAdmin struct {
+	response.BaseResponse
+	q *broker.DLQ
+}
+
+func (h *dlqAdmin) list(c *gin.Context) {
+	var (
+		err  error
+		resp []*broker.DeadLetter
+	)
+	defer func() {
+		h.Response(c, err, resp)
+	}()
+
+	limit := 100
+	if s := c.Query("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			err = errors.NewWithError(constants.ErrnoInvalidParams, err)
+			return
+		}
+	}
+	if limit < 0 {
+		err = errors.NewWithError(constants.ErrnoInvalidParams, broker.ErrNegativeLimit)
+		return
+	}
+	resp, err = h.q.List(c.Request.Context(), limit)
+}
+
+func (h *dlqAdmin) export(c *gin.Context) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="dlq.jsonl"`)
+	c.Status(http.StatusOK)
+	if _, err := h.q.Export(c.Request.Context(), c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func (h *dlqAdmin) replay(c *gin.Context) {
+	h.apply(c, h.q.Replay)
+}
+
+func (h *dlqAdmin) purge(c *gin.Context) {
+	h.apply(c, h.q.Purge)
+}
+
+func (h *dlqAdmin) apply(c *gin.Context, op func(context.Context, broker.Selection) (int, error)) {
+	var (
+		err  error
+		resp struct {
+			Count int `json:"count"`
+		}
+	)
+	defer func() {
+		h.Response(c, err, resp)
+	}()
+
+	var sel broker.Selection
+	if err = c.ShouldBindJSON(&sel); err != nil {
+		err = errors.NewWithError(constants.ErrnoBindRequest, err)
+		return
+	}
+	if err = sel.Validate(); err != nil {
+		err = errors.NewWithError(constants.ErrnoInvalidParams, err)
+		return
+	}
+	resp.Count, err = op(c.Request.Context(), sel)
+}
+
+func adminAuth(c *gin.Context) {
+	token := viper.GetString("admin-token")
+	got := c.GetHeader(adminTokenHeader)
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(got)) != 1 {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+		return
+	}
+	c.Next()
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"net"
 
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/middleware"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -35,6 +36,7 @@ func RunGRPCServerOnAddr(addr string, registerServer func(server *grpc.Server))
 			grpc_tags.UnaryServerInterceptor(grpc_tags.WithFieldExtractor(grpc_tags.CodeGenRequestFieldExtractor)),
 			grpc_logrus.UnaryServerInterceptor(logrusEntry),
 			middleware.GRPCUnaryInterceptor,
+			actor.UnaryServerInterceptor,
 		),
 		grpc.ChainStreamInterceptor(
 			grpc_tags.StreamServerInterceptor(grpc_tags.WithFieldExtractor(grpc_tags.CodeGenRequestFieldExtractor)),
@@ -6,7 +6,7 @@ import (
 	"github.com/peiyouyao/gorder/common/genproto/orderpb"
 )
 
-// impl consumer.OrderService
+// impl command.OrderService
 type OrderGRPC struct {
 	client orderpb.OrderServiceClient
 }
@@ -19,3 +19,8 @@ func (g *OrderGRPC) UpdateOrder(ctx context.Context, request *orderpb.Order) err
 	_, err := g.client.UpdateOrder(ctx, request)
 	return err
 }
+
+func (g *OrderGRPC) RejectOrder(ctx context.Context, request *orderpb.RejectOrderRequest) error {
+	_, err := g.client.RejectOrder(ctx, request)
+	return err
+}
@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+)
+
+// TicketRepositoryInmem keeps the tickets in process, for tests.
+type TicketRepositoryInmem struct {
+	lock    sync.RWMutex
+	tickets map[string]*ticket.Ticket // order id -> ticket
+}
+
+func NewTicketRepositoryInmem() *TicketRepositoryInmem {
+	return &TicketRepositoryInmem{tickets: make(map[string]*ticket.Ticket)}
+}
+
+// impl ticket.Repository
+func (m *TicketRepositoryInmem) Create(_ context.Context, t *ticket.Ticket) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.tickets[t.OrderID]; ok {
+		return ticket.AlreadyExistsError{OrderID: t.OrderID}
+	}
+	m.tickets[t.OrderID] = copyTicket(t)
+	return nil
+}
+
+func (m *TicketRepositoryInmem) Get(_ context.Context, orderID string) (*ticket.Ticket, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	t, ok := m.tickets[orderID]
+	if !ok {
+		return nil, ticket.NotFoundError{OrderID: orderID}
+	}
+	return copyTicket(t), nil
+}
+
+// updateFn 在写锁内执行, 不会有并发修改
+func (m *TicketRepositoryInmem) Update(ctx context.Context, orderID string, updateFn func(context.Context, *ticket.Ticket) error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.tickets[orderID]
+	if !ok {
+		return ticket.NotFoundError{OrderID: orderID}
+	}
+	updated := copyTicket(t)
+	if err := updateFn(ctx, updated); err != nil {
+		return err
+	}
+	m.tickets[orderID] = updated
+	return nil
+}
+
+func (m *TicketRepositoryInmem) Queued(ctx context.Context, limit int) ([]*ticket.Ticket, error) {
+	return m.List(ctx, []ticket.Status{ticket.StatusQueued}, limit)
+}
+
+func (m *TicketRepositoryInmem) Ahead(_ context.Context, t *ticket.Ticket) ([]*ticket.Ticket, error) {
+	return m.find(func(o *ticket.Ticket) bool {
+		return o.Status == ticket.StatusCooking || (o.Status == ticket.StatusQueued && o.PaidAt.Before(t.PaidAt))
+	}, byPaidAt, -1), nil
+}
+
+func (m *TicketRepositoryInmem) List(_ context.Context, statuses []ticket.Status, limit int) ([]*ticket.Ticket, error) {
+	return m.find(func(t *ticket.Ticket) bool {
+		return slices.Contains(statuses, t.Status)
+	}, byPaidAt, limit), nil
+}
+
+func (m *TicketRepositoryInmem) FindDone(_ context.Context, now time.Time, limit int) ([]*ticket.Ticket, error) {
+	return m.find(func(t *ticket.Ticket) bool {
+		return t.Status == ticket.StatusCooking && t.ClaimedBy == "" && t.ReadyAt().Before(now)
+	}, func(a, b *ticket.Ticket) int {
+		return a.ReadyAt().Compare(b.ReadyAt())
+	}, limit), nil
+}
+
+func (m *TicketRepositoryInmem) FindUnreported(_ context.Context, limit int) ([]*ticket.Ticket, error) {
+	return m.find((*ticket.Ticket).Unreported, byPaidAt, limit), nil
+}
+
+// find returns copies of the tickets that match, sorted by sortBy, at most limit unless it is negative
+func (m *TicketRepositoryInmem) find(match func(*ticket.Ticket) bool, sortBy func(a, b *ticket.Ticket) int, limit int) []*ticket.Ticket {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var found []*ticket.Ticket
+	for _, t := range m.tickets {
+		if match(t) {
+			found = append(found, copyTicket(t))
+		}
+	}
+	slices.SortFunc(found, sortBy)
+	if limit >= 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+func byPaidAt(a, b *ticket.Ticket) int {
+	if c := a.PaidAt.Compare(b.PaidAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.OrderID, b.OrderID)
+}
+
+// callers must not be able to change the store through a returned ticket, like with a real database
+func copyTicket(t *ticket.Ticket) *ticket.Ticket {
+	c := *t
+	c.Items = slices.Clone(t.Items)
+	c.DoneItems = slices.Clone(t.DoneItems)
+	return &c
+}
@@ -0,0 +1,272 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/logging"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	dbName          = viper.GetString("kitchen.mongo.db-name")
+	ticketsCollName = viper.GetString("kitchen.mongo.tickets-coll-name")
+)
+
+// impl ticket.Repository
+type TicketRepositoryMongo struct {
+	db *mongo.Client
+}
+
+type ticketModel struct {
+	OrderID          string         `bson:"_id"`
+	CustomerID       string         `bson:"customer_id"`
+	PaymentLink      string         `bson:"payment_link"`
+	Items            []*entity.Item `bson:"items"`
+	Status           string         `bson:"status"`
+	Station          string         `bson:"station,omitempty"`
+	PrepTime         time.Duration  `bson:"prep_time"`
+	PaidAt           time.Time      `bson:"paid_at"`
+	QueuedAt         time.Time      `bson:"queued_at"`
+	StartedAt        *time.Time     `bson:"started_at,omitempty"`
+	PlatedAt         *time.Time     `bson:"plated_at,omitempty"`
+	EstimatedReadyAt *time.Time     `bson:"estimated_ready_at,omitempty"`
+	ClaimedBy        string         `bson:"claimed_by"`
+	DoneItems        []string       `bson:"done_items,omitempty"`
+	Reject           *rejectModel   `bson:"reject,omitempty"`
+	Reported         string         `bson:"reported"`
+	// 以下两个字段由其他字段推出, 只用于查询
+	ReadyAt    *time.Time `bson:"ready_at,omitempty"`
+	Unreported bool       `bson:"unreported"`
+	Version    int64      `bson:"version"` // 乐观锁, 每次 Update 加一
+}
+
+type rejectModel struct {
+	Reason string    `bson:"reason"`
+	At     time.Time `bson:"at"`
+}
+
+func NewTicketRepositoryMongo(db *mongo.Client) *TicketRepositoryMongo {
+	return &TicketRepositoryMongo{db: db}
+}
+
+func (r *TicketRepositoryMongo) Create(ctx context.Context, t *ticket.Ticket) (err error) {
+	var res *mongo.InsertOneResult
+	dlog := logging.MongoDB(ctx, "TicketRepositoryMongo.Create", logrus.Fields{"ticket": t})
+	defer func() { dlog(res, err) }()
+
+	res, err = r.collection().InsertOne(ctx, r.marshalToModel(t, 0))
+	if mongo.IsDuplicateKeyError(err) {
+		err = ticket.AlreadyExistsError{OrderID: t.OrderID}
+	}
+	return
+}
+
+func (r *TicketRepositoryMongo) Get(ctx context.Context, orderID string) (got *ticket.Ticket, err error) {
+	dlog := logging.MongoDB(ctx, "TicketRepositoryMongo.Get", logrus.Fields{"order_id": orderID})
+	defer func() { dlog(got, err) }()
+
+	read, err := r.get(ctx, orderID)
+	if err != nil {
+		return
+	}
+	got = r.unmarshal(read)
+	return
+}
+
+func (r *TicketRepositoryMongo) Update(ctx context.Context, orderID string, updateFn func(context.Context, *ticket.Ticket) error) (err error) {
+	var res *mongo.UpdateResult
+	dlog := logging.MongoDB(ctx, "TicketRepositoryMongo.Update", logrus.Fields{"order_id": orderID})
+	defer func() { dlog(res, err) }()
+
+	read, err := r.get(ctx, orderID)
+	if err != nil {
+		return
+	}
+	t := r.unmarshal(read)
+	if err = updateFn(ctx, t); err != nil {
+		return
+	}
+	res, err = r.collection().ReplaceOne(
+		ctx,
+		bson.M{"_id": orderID, "version": read.Version},
+		r.marshalToModel(t, read.Version+1),
+	)
+	if err == nil && res.MatchedCount == 0 {
+		err = ticket.ConflictError{OrderID: orderID}
+	}
+	return
+}
+
+func (r *TicketRepositoryMongo) Queued(ctx context.Context, limit int) ([]*ticket.Ticket, error) {
+	return r.find(ctx, "TicketRepositoryMongo.Queued",
+		bson.M{"status": string(ticket.StatusQueued)},
+		options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (r *TicketRepositoryMongo) Ahead(ctx context.Context, t *ticket.Ticket) ([]*ticket.Ticket, error) {
+	return r.find(ctx, "TicketRepositoryMongo.Ahead",
+		bson.M{"$or": bson.A{
+			bson.M{"status": string(ticket.StatusCooking)},
+			bson.M{"status": string(ticket.StatusQueued), "paid_at": bson.M{"$lt": t.PaidAt}},
+		}},
+		options.Find(),
+	)
+}
+
+func (r *TicketRepositoryMongo) List(ctx context.Context, statuses []ticket.Status, limit int) ([]*ticket.Ticket, error) {
+	in := make(bson.A, 0, len(statuses))
+	for _, s := range statuses {
+		in = append(in, string(s))
+	}
+	return r.find(ctx, "TicketRepositoryMongo.List",
+		bson.M{"status": bson.M{"$in": in}},
+		options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (r *TicketRepositoryMongo) FindDone(ctx context.Context, now time.Time, limit int) ([]*ticket.Ticket, error) {
+	return r.find(ctx, "TicketRepositoryMongo.FindDone",
+		bson.M{"status": string(ticket.StatusCooking), "claimed_by": "", "ready_at": bson.M{"$lt": now}},
+		options.Find().SetSort(bson.D{{Key: "ready_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (r *TicketRepositoryMongo) FindUnreported(ctx context.Context, limit int) ([]*ticket.Ticket, error) {
+	return r.find(ctx, "TicketRepositoryMongo.FindUnreported",
+		bson.M{"unreported": true},
+		options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+// EnsureIndexes creates the indexes the queue relies on, it is safe to call on every start.
+func (r *TicketRepositoryMongo) EnsureIndexes(ctx context.Context) (err error) {
+	var names []string
+	dlog := logging.MongoDB(ctx, "TicketRepositoryMongo.EnsureIndexes", nil)
+	defer func() { dlog(names, err) }()
+
+	names, err = r.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "paid_at", Value: 1}},
+			Options: options.Index().SetName("status_1_paid_at_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "ready_at", Value: 1}},
+			Options: options.Index().SetName("status_1_ready_at_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "unreported", Value: 1}},
+			Options: options.Index().SetName("unreported_1").SetPartialFilterExpression(bson.M{"unreported": true}),
+		},
+	})
+	return
+}
+
+func (r *TicketRepositoryMongo) find(ctx context.Context, method string, cond bson.M, opts *options.FindOptions) (found []*ticket.Ticket, err error) {
+	dlog := logging.MongoDB(ctx, method, logrus.Fields{"cond": cond})
+	defer func() { dlog(len(found), err) }()
+
+	cur, err := r.collection().Find(ctx, cond, opts)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+
+	var reads []*ticketModel
+	if err = cur.All(ctx, &reads); err != nil {
+		return
+	}
+	for _, read := range reads {
+		found = append(found, r.unmarshal(read))
+	}
+	return
+}
+
+func (r *TicketRepositoryMongo) get(ctx context.Context, orderID string) (*ticketModel, error) {
+	read := &ticketModel{}
+	if err := r.collection().FindOne(ctx, bson.M{"_id": orderID}).Decode(read); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ticket.NotFoundError{OrderID: orderID}
+		}
+		return nil, err
+	}
+	return read, nil
+}
+
+func (r *TicketRepositoryMongo) collection() *mongo.Collection {
+	return r.db.Database(dbName).Collection(ticketsCollName)
+}
+
+func (r *TicketRepositoryMongo) marshalToModel(t *ticket.Ticket, version int64) ticketModel {
+	m := ticketModel{
+		OrderID:     t.OrderID,
+		CustomerID:  t.CustomerID,
+		PaymentLink: t.PaymentLink,
+		Items:       t.Items,
+		Status:      string(t.Status),
+		Station:     t.Station,
+		PrepTime:    t.PrepTime,
+		PaidAt:      t.PaidAt,
+		QueuedAt:    t.QueuedAt,
+		ClaimedBy:   t.ClaimedBy,
+		DoneItems:   t.DoneItems,
+		Reported:    t.Reported,
+		Unreported:  t.Unreported(),
+		Version:     version,
+	}
+	if !t.StartedAt.IsZero() {
+		startedAt, readyAt := t.StartedAt, t.ReadyAt()
+		m.StartedAt, m.ReadyAt = &startedAt, &readyAt
+	}
+	if !t.PlatedAt.IsZero() {
+		platedAt := t.PlatedAt
+		m.PlatedAt = &platedAt
+	}
+	if !t.EstimatedReadyAt.IsZero() {
+		estimated := t.EstimatedReadyAt
+		m.EstimatedReadyAt = &estimated
+	}
+	if t.Status == ticket.StatusRejected {
+		m.Reject = &rejectModel{Reason: t.RejectReason, At: t.RejectedAt}
+	}
+	return m
+}
+
+func (r *TicketRepositoryMongo) unmarshal(read *ticketModel) *ticket.Ticket {
+	t := &ticket.Ticket{
+		OrderID:     read.OrderID,
+		CustomerID:  read.CustomerID,
+		PaymentLink: read.PaymentLink,
+		Items:       read.Items,
+		Status:      ticket.Status(read.Status),
+		Station:     read.Station,
+		PrepTime:    read.PrepTime,
+		PaidAt:      read.PaidAt,
+		QueuedAt:    read.QueuedAt,
+		ClaimedBy:   read.ClaimedBy,
+		DoneItems:   read.DoneItems,
+		Reported:    read.Reported,
+	}
+	if read.StartedAt != nil {
+		t.StartedAt = *read.StartedAt
+	}
+	if read.PlatedAt != nil {
+		t.PlatedAt = *read.PlatedAt
+	}
+	if read.EstimatedReadyAt != nil {
+		t.EstimatedReadyAt = *read.EstimatedReadyAt
+	}
+	if read.Reject != nil {
+		t.RejectReason, t.RejectedAt = read.Reject.Reason, read.Reject.At
+	}
+	return t
+}
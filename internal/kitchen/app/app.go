@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	grpcClient "github.com/peiyouyao/gorder/common/client"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/app/query"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type Application struct {
+	Commands Commands
+	Queries  Queries
+}
+
+type Commands struct {
+	EnqueueTicket   command.EnqueueTicketHandler
+	StartNextTicket command.StartNextTicketHandler
+	PlateTicket     command.PlateTicketHandler
+	SweepTickets    command.SweepTicketsHandler
+	// 厨房员工
+	ClaimTicket   command.ClaimTicketHandler
+	MarkItemsDone command.MarkItemsDoneHandler
+	BumpTicket    command.BumpTicketHandler
+	RejectTicket  command.RejectTicketHandler
+}
+
+type Queries struct {
+	ListOpenTickets query.ListOpenTicketsHandler
+}
+
+func NewApplication(ctx context.Context) (Application, func()) {
+	orderClient, closeOrderClient, err := grpcClient.NewOrderGRPCClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+	orderGRPC := adapters.NewOrderGRPC(orderClient)
+
+	mongoCli := newMongoClient()
+	ticketRepo := adapters.NewTicketRepositoryMongo(mongoCli)
+	if err := ticketRepo.EnsureIndexes(ctx); err != nil {
+		logrus.Warnf("Ensure ticket indexes fail err=%v", err)
+	}
+
+	return newApplication(ticketRepo, orderGRPC), func() {
+		_ = closeOrderClient()
+		_ = mongoCli.Disconnect(context.Background())
+	}
+}
+
+func newApplication(ticketRepo ticket.Repository, orderGRPC command.OrderService) Application {
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	metrics := metrics.NewPrometheusMetricsClient(&metrics.PrometheusMetricsClientConfig{
+		Host:        viper.GetString("kitchen.metrics-addr"),
+		ServiceName: viper.GetString("kitchen.service-name"),
+	})
+	return Application{
+		Commands: Commands{
+			EnqueueTicket:   command.NewEnqueueTicketHandler(ticketRepo, orderGRPC, newPrepTimes(), estimateStations(), logger, metrics),
+			StartNextTicket: command.NewStartNextTicketHandler(ticketRepo, orderGRPC, logger, metrics),
+			PlateTicket:     command.NewPlateTicketHandler(ticketRepo, orderGRPC, logger, metrics),
+			SweepTickets:    command.NewSweepTicketsHandler(ticketRepo, orderGRPC, logger, metrics),
+			ClaimTicket:     command.NewClaimTicketHandler(ticketRepo, orderGRPC, logger, metrics),
+			MarkItemsDone:   command.NewMarkItemsDoneHandler(ticketRepo, orderGRPC, logger, metrics),
+			BumpTicket:      command.NewBumpTicketHandler(ticketRepo, orderGRPC, logger, metrics),
+			RejectTicket:    command.NewRejectTicketHandler(ticketRepo, orderGRPC, logger, metrics),
+		},
+		Queries: Queries{
+			ListOpenTickets: query.NewListOpenTicketsHandler(ticketRepo, logger, metrics),
+		},
+	}
+}
+
+// kitchen.prep-time.items 的 key 是商品 id, viper 会把它转成小写
+func newPrepTimes() ticket.PrepTimes {
+	p := ticket.PrepTimes{
+		ByItem:  make(map[string]time.Duration),
+		Default: viper.GetDuration("kitchen.prep-time.default") * time.Second,
+	}
+	for id := range viper.GetStringMap("kitchen.prep-time.items") {
+		p.ByItem[id] = viper.GetDuration("kitchen.prep-time.items."+id) * time.Second
+	}
+	return p
+}
+
+// 多个 kitchen 实例共享队列时, 估计要按所有实例的 station 数算
+func estimateStations() int {
+	if n := viper.GetInt("kitchen.estimate.stations"); n > 0 {
+		return n
+	}
+	return max(viper.GetInt("kitchen.stations"), 1)
+}
+
+func newMongoClient() *mongo.Client {
+	uri := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s",
+		viper.GetString("mongo.user"),
+		viper.GetString("mongo.password"),
+		viper.GetString("mongo.host"),
+		viper.GetString("mongo.port"),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		panic(err)
+	}
+	if err = c.Ping(ctx, readpref.Primary()); err != nil {
+		panic(err)
+	}
+	return c
+}
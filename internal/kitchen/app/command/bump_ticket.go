@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// BumpTicket plates the ticket now
+type BumpTicket struct {
+	OrderID string
+}
+
+type BumpTicketHandler decorator.CommandHandler[BumpTicket, *ticket.Ticket]
+
+type bumpTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewBumpTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) BumpTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[BumpTicket, *ticket.Ticket](
+		bumpTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h bumpTicketHandler) Handle(ctx context.Context, cmd BumpTicket) (*ticket.Ticket, error) {
+	return updateAndReport(ctx, h.ticketRepo, h.orderGRPC, cmd.OrderID, func(t *ticket.Ticket) error {
+		return t.Bump(time.Now())
+	})
+}
@@ -0,0 +1,41 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpTicket(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewTicketRepositoryInmem()
+	orders := &fakeOrderService{}
+	handler := command.NewBumpTicketHandler(repo, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	newQueuedTicket(t, repo, "order-1")
+
+	// 排队的 ticket 要先在某个 station 认领
+	_, err := handler.Handle(ctx, command.BumpTicket{OrderID: "order-1"})
+	assert.ErrorIs(t, err, ticket.ErrNotCooking)
+	assert.Empty(t, orders.updates)
+
+	claim := command.NewClaimTicketHandler(repo, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	_, err = claim.Handle(ctx, command.ClaimTicket{OrderID: "order-1", Staff: "alice", Station: "kitchen-a-1"})
+	require.NoError(t, err)
+	tk, err := handler.Handle(ctx, command.BumpTicket{OrderID: "order-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusPlated, tk.Status)
+	assert.Equal(t, "kitchen-a-1", tk.Station)
+	assert.Equal(t, []string{constants.OrderStatusCooking, constants.OrderStatusReady}, orders.updates)
+
+	_, err = handler.Handle(ctx, command.BumpTicket{OrderID: "order-1"})
+	assert.ErrorIs(t, err, ticket.ErrFinished)
+	assert.Len(t, orders.updates, 2)
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// ClaimTicket gives a ticket to a staff member, the stations leave it to them
+type ClaimTicket struct {
+	OrderID string
+	Staff   string
+	Station string // station a queued ticket is started on
+}
+
+type ClaimTicketHandler decorator.CommandHandler[ClaimTicket, *ticket.Ticket]
+
+type claimTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewClaimTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) ClaimTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[ClaimTicket, *ticket.Ticket](
+		claimTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h claimTicketHandler) Handle(ctx context.Context, cmd ClaimTicket) (*ticket.Ticket, error) {
+	return updateAndReport(ctx, h.ticketRepo, h.orderGRPC, cmd.OrderID, func(t *ticket.Ticket) error {
+		return t.Claim(cmd.Staff, cmd.Station, time.Now())
+	})
+}
@@ -0,0 +1,98 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl command.OrderService, fails every call while err is set
+type fakeOrderService struct {
+	err     error
+	updates []string // status of each accepted UpdateOrder
+	rejects []*orderpb.RejectOrderRequest
+}
+
+func (f *fakeOrderService) UpdateOrder(_ context.Context, order *orderpb.Order) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.updates = append(f.updates, order.Status)
+	return nil
+}
+
+func (f *fakeOrderService) RejectOrder(_ context.Context, request *orderpb.RejectOrderRequest) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.rejects = append(f.rejects, request)
+	return nil
+}
+
+// newQueuedTicket saves a queued ticket of a paid order with two items
+func newQueuedTicket(t *testing.T, repo *adapters.TicketRepositoryInmem, orderID string) {
+	o := entity.NewOrder(orderID, "customer-1", constants.OrderStatusPaid, "https://pay.example/1", []*entity.Item{
+		{ID: "item-1", Name: "burger", Quantity: 1},
+		{ID: "item-2", Name: "fries", Quantity: 2},
+	})
+	tk, err := ticket.New(o, time.Now(), time.Now(), ticket.PrepTimes{Default: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), tk))
+}
+
+func TestClaimTicket(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewTicketRepositoryInmem()
+	orders := &fakeOrderService{}
+	handler := command.NewClaimTicketHandler(repo, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	newQueuedTicket(t, repo, "order-1")
+
+	// 排队的 ticket 在指定的 station 开始制作
+	tk, err := handler.Handle(ctx, command.ClaimTicket{OrderID: "order-1", Staff: "alice", Station: "kitchen-a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusCooking, tk.Status)
+	assert.Equal(t, "kitchen-a-1", tk.Station)
+	assert.Equal(t, "alice", tk.ClaimedBy)
+	assert.Equal(t, []string{constants.OrderStatusCooking}, orders.updates)
+
+	_, err = handler.Handle(ctx, command.ClaimTicket{OrderID: "order-1", Staff: "bob"})
+	assert.ErrorIs(t, err, ticket.ErrAlreadyClaimed)
+	stored, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", stored.ClaimedBy)
+
+	_, err = handler.Handle(ctx, command.ClaimTicket{OrderID: "missing", Staff: "alice"})
+	assert.ErrorAs(t, err, &ticket.NotFoundError{})
+}
+
+func TestClaimTicket_ReportFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewTicketRepositoryInmem()
+	orders := &fakeOrderService{err: errors.New("order down")}
+	handler := command.NewClaimTicketHandler(repo, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	newQueuedTicket(t, repo, "order-1")
+
+	// 认领照样生效, 状态留给 sweeper 重新上报
+	tk, err := handler.Handle(ctx, command.ClaimTicket{OrderID: "order-1", Staff: "alice", Station: "kitchen-a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusCooking, tk.Status)
+	stored, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", stored.ClaimedBy)
+	assert.True(t, stored.Unreported())
+	unreported, err := repo.FindUnreported(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, unreported, 1)
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+type EnqueueTicket struct {
+	Order  *entity.Order
+	PaidAt time.Time
+}
+
+type EnqueueTicketHandler decorator.CommandHandler[EnqueueTicket, interface{}]
+
+type enqueueTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+	prepTimes  ticket.PrepTimes
+	stations   int // stations the estimate assumes
+}
+
+func NewEnqueueTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	prepTimes ticket.PrepTimes,
+	stations int,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) EnqueueTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	if stations <= 0 {
+		panic("non-positive stations")
+	}
+	return decorator.ApplyCommandDecorators[EnqueueTicket, interface{}](
+		enqueueTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC, prepTimes: prepTimes, stations: stations},
+		logger,
+		metricsClient,
+	)
+}
+
+// 订单已有 ticket 时什么也不做, 重复投递的 order.paid 不会再排一次队
+func (e enqueueTicketHandler) Handle(ctx context.Context, cmd EnqueueTicket) (interface{}, error) {
+	now := time.Now()
+	t, err := ticket.New(cmd.Order, cmd.PaidAt, now, e.prepTimes)
+	if err != nil {
+		return nil, err
+	}
+	ahead, err := e.ticketRepo.Ahead(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	t.Estimate(ahead, e.stations, now)
+	if err = e.ticketRepo.Create(ctx, t); err != nil {
+		if errors.As(err, &ticket.AlreadyExistsError{}) {
+			logrus.WithContext(ctx).WithField("order_id", t.OrderID).Info("Ticket already queued")
+			return nil, nil
+		}
+		return nil, err
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id":           t.OrderID,
+		"prep_time":          t.PrepTime,
+		"estimated_ready_at": t.EstimatedReadyAt,
+	}).Info("Ticket queued")
+
+	// 状态还是 paid, 只是告诉 order 估计时间; 失败不重试, 开始制作时会再发一次
+	if err = e.orderGRPC.UpdateOrder(ctx, convert.OrderEntityToProto(t.Order())); err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id": t.OrderID,
+			"err":      err.Error(),
+		}).Warn("Report estimated ready time fail")
+	}
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// MarkItemsDone plates the ticket once every item is done
+type MarkItemsDone struct {
+	OrderID string
+	ItemIDs []string
+}
+
+type MarkItemsDoneHandler decorator.CommandHandler[MarkItemsDone, *ticket.Ticket]
+
+type markItemsDoneHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewMarkItemsDoneHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) MarkItemsDoneHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[MarkItemsDone, *ticket.Ticket](
+		markItemsDoneHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h markItemsDoneHandler) Handle(ctx context.Context, cmd MarkItemsDone) (*ticket.Ticket, error) {
+	return updateAndReport(ctx, h.ticketRepo, h.orderGRPC, cmd.OrderID, func(t *ticket.Ticket) error {
+		return t.MarkItemsDone(cmd.ItemIDs, time.Now())
+	})
+}
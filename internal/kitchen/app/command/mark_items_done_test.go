@@ -0,0 +1,45 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkItemsDone(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewTicketRepositoryInmem()
+	orders := &fakeOrderService{}
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	handler := command.NewMarkItemsDoneHandler(repo, orders, logger, metrics.NoMetrics{})
+	newQueuedTicket(t, repo, "order-1")
+
+	_, err := handler.Handle(ctx, command.MarkItemsDone{OrderID: "order-1", ItemIDs: []string{"item-1"}})
+	assert.ErrorIs(t, err, ticket.ErrNotCooking)
+
+	claim := command.NewClaimTicketHandler(repo, orders, logger, metrics.NoMetrics{})
+	_, err = claim.Handle(ctx, command.ClaimTicket{OrderID: "order-1", Staff: "alice", Station: "kitchen-a-1"})
+	require.NoError(t, err)
+
+	_, err = handler.Handle(ctx, command.MarkItemsDone{OrderID: "order-1", ItemIDs: []string{"item-3"}})
+	assert.ErrorIs(t, err, ticket.ErrUnknownItem)
+
+	tk, err := handler.Handle(ctx, command.MarkItemsDone{OrderID: "order-1", ItemIDs: []string{"item-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusCooking, tk.Status)
+	assert.Equal(t, []string{"item-1"}, tk.DoneItems)
+
+	// 最后一个做完后出餐
+	tk, err = handler.Handle(ctx, command.MarkItemsDone{OrderID: "order-1", ItemIDs: []string{"item-2", "item-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusPlated, tk.Status)
+	assert.Equal(t, []string{constants.OrderStatusCooking, constants.OrderStatusReady}, orders.updates)
+}
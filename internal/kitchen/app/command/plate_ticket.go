@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// PlateTicket is what a station does once the prep time passed
+type PlateTicket struct {
+	OrderID string
+}
+
+type PlateTicketHandler decorator.CommandHandler[PlateTicket, interface{}]
+
+type plateTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewPlateTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) PlateTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[PlateTicket, interface{}](
+		plateTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+// 做好后把订单改为 ready, 上报失败由 ReportTickets 重试
+func (p plateTicketHandler) Handle(ctx context.Context, cmd PlateTicket) (interface{}, error) {
+	var plated *ticket.Ticket
+	err := p.ticketRepo.Update(ctx, cmd.OrderID, func(_ context.Context, t *ticket.Ticket) error {
+		// 员工认领或已经拒绝的 ticket 不由 station 出餐
+		if t.ClaimedBy != "" || t.Status == ticket.StatusRejected {
+			return nil
+		}
+		if err := t.Plate(time.Now()); err != nil {
+			return err
+		}
+		plated = t
+		return nil
+	})
+	if err != nil || plated == nil {
+		return nil, err
+	}
+	if err = report(ctx, p.ticketRepo, p.orderGRPC, plated); err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id": plated.OrderID,
+			"err":      err.Error(),
+		}).Warn("Report ready fail, retry later")
+	}
+	return nil, nil
+}
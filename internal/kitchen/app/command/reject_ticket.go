@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// RejectTicket gives up on the order. The refund is not done here: order publishes
+// order.rejected and payment's order-closed consumer refunds the payment
+// (payment/infrastructure/consumer/order_closed.go).
+type RejectTicket struct {
+	OrderID string
+	Reason  string
+}
+
+type RejectTicketHandler decorator.CommandHandler[RejectTicket, *ticket.Ticket]
+
+type rejectTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewRejectTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) RejectTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[RejectTicket, *ticket.Ticket](
+		rejectTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h rejectTicketHandler) Handle(ctx context.Context, cmd RejectTicket) (*ticket.Ticket, error) {
+	return updateAndReport(ctx, h.ticketRepo, h.orderGRPC, cmd.OrderID, func(t *ticket.Ticket) error {
+		return t.Reject(cmd.Reason, time.Now())
+	})
+}
@@ -0,0 +1,49 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectTicket(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewTicketRepositoryInmem()
+	orders := &fakeOrderService{}
+	handler := command.NewRejectTicketHandler(repo, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	newQueuedTicket(t, repo, "order-1")
+	newQueuedTicket(t, repo, "order-2")
+
+	_, err := handler.Handle(ctx, command.RejectTicket{OrderID: "order-1"})
+	assert.Error(t, err, "empty reason")
+
+	// order 收到 RejectOrder 后广播 order.rejected, payment 退款
+	tk, err := handler.Handle(ctx, command.RejectTicket{OrderID: "order-1", Reason: "out of buns"})
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusRejected, tk.Status)
+	require.Len(t, orders.rejects, 1)
+	assert.Equal(t, "order-1", orders.rejects[0].OrderID)
+	assert.Equal(t, "customer-1", orders.rejects[0].CustomerID)
+	assert.Equal(t, "out of buns", orders.rejects[0].Reason)
+	assert.Empty(t, orders.updates)
+
+	_, err = handler.Handle(ctx, command.RejectTicket{OrderID: "order-1", Reason: "out of buns"})
+	assert.ErrorIs(t, err, ticket.ErrFinished)
+
+	// order 没有接受时留给 sweeper 重新上报
+	orders.err = errors.New("order down")
+	_, err = handler.Handle(ctx, command.RejectTicket{OrderID: "order-2", Reason: "out of buns"})
+	require.NoError(t, err)
+	stored, err := repo.Get(ctx, "order-2")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusRejected, stored.Status)
+	assert.True(t, stored.Unreported())
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// report sends the order status of t to the order service and remembers it was accepted,
+// a failed report stays unreported and ReportTickets sends it again.
+func report(ctx context.Context, ticketRepo ticket.Repository, orderGRPC OrderService, t *ticket.Ticket) error {
+	if !t.Unreported() {
+		return nil
+	}
+	status := t.OrderStatus()
+	var err error
+	if t.Status == ticket.StatusRejected {
+		// order 收到后开始退款
+		err = orderGRPC.RejectOrder(ctx, &orderpb.RejectOrderRequest{
+			OrderID:    t.OrderID,
+			CustomerID: t.CustomerID,
+			Reason:     t.RejectReason,
+		})
+	} else {
+		err = orderGRPC.UpdateOrder(ctx, convert.OrderEntityToProto(t.Order()))
+	}
+	if err != nil {
+		return err
+	}
+	return ticketRepo.Update(ctx, t.OrderID, func(_ context.Context, t *ticket.Ticket) error {
+		t.Reported = status
+		return nil
+	})
+}
+
+// updateAndReport changes the ticket for a staff member and reports the new order status,
+// the change stands even when the report fails, the sweeper sends it again.
+func updateAndReport(
+	ctx context.Context,
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	orderID string,
+	updateFn func(t *ticket.Ticket) error,
+) (*ticket.Ticket, error) {
+	var updated *ticket.Ticket
+	err := ticketRepo.Update(ctx, orderID, func(_ context.Context, t *ticket.Ticket) error {
+		if err := updateFn(t); err != nil {
+			return err
+		}
+		updated = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = report(ctx, ticketRepo, orderGRPC, updated); err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id": orderID,
+			"status":   updated.OrderStatus(),
+			"err":      err.Error(),
+		}).Warn("Report ticket fail, retry later")
+	}
+	return updated, nil
+}
@@ -0,0 +1,12 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+)
+
+type OrderService interface {
+	UpdateOrder(ctx context.Context, order *orderpb.Order) error
+	RejectOrder(ctx context.Context, request *orderpb.RejectOrderRequest) error
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+// 每次最多看这么多排队的 ticket, 都被其他 station 抢走时下次再来
+const startCandidates = 10
+
+type StartNextTicket struct {
+	Station string
+}
+
+// StartNextTicketHandler returns the ticket the station started, nil when nothing is queued
+type StartNextTicketHandler decorator.CommandHandler[StartNextTicket, *ticket.Ticket]
+
+type startNextTicketHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewStartNextTicketHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) StartNextTicketHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[StartNextTicket, *ticket.Ticket](
+		startNextTicketHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (s startNextTicketHandler) Handle(ctx context.Context, cmd StartNextTicket) (*ticket.Ticket, error) {
+	queued, err := s.ticketRepo.Queued(ctx, startCandidates)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range queued {
+		var started *ticket.Ticket
+		err = s.ticketRepo.Update(ctx, t.OrderID, func(_ context.Context, t *ticket.Ticket) error {
+			if err := t.Start(cmd.Station, time.Now()); err != nil {
+				return err
+			}
+			started = t
+			return nil
+		})
+		// 被别的 station 抢先了, 试下一个
+		if errors.Is(err, ticket.ErrNotQueued) || errors.As(err, &ticket.ConflictError{}) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err = report(ctx, s.ticketRepo, s.orderGRPC, started); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": started.OrderID,
+				"err":      err.Error(),
+			}).Warn("Report cooking fail, retry later")
+		}
+		return started, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+type SweepTickets struct {
+	Now   time.Time
+	Limit int
+}
+
+type SweepTicketsResult struct {
+	Plated   []string // order IDs of tickets whose station stopped before they were done
+	Reported []string // order IDs whose status was sent again
+}
+
+/*
+兜底:
+ 1. 制作中但已经超过 ReadyAt 的 ticket 直接出餐, 通常是 kitchen 重启时 station 还没做完
+ 2. 重新上报 order 没有收到的状态
+*/
+type SweepTicketsHandler decorator.CommandHandler[SweepTickets, *SweepTicketsResult]
+
+type sweepTicketsHandler struct {
+	ticketRepo ticket.Repository
+	orderGRPC  OrderService
+}
+
+func NewSweepTicketsHandler(
+	ticketRepo ticket.Repository,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) SweepTicketsHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	if orderGRPC == nil {
+		panic("nil orderGRPC")
+	}
+	return decorator.ApplyCommandDecorators[SweepTickets, *SweepTicketsResult](
+		sweepTicketsHandler{ticketRepo: ticketRepo, orderGRPC: orderGRPC},
+		logger,
+		metricsClient,
+	)
+}
+
+func (s sweepTicketsHandler) Handle(ctx context.Context, cmd SweepTickets) (*SweepTicketsResult, error) {
+	res := &SweepTicketsResult{}
+	done, err := s.ticketRepo.FindDone(ctx, cmd.Now, cmd.Limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range done {
+		err = s.ticketRepo.Update(ctx, t.OrderID, func(_ context.Context, t *ticket.Ticket) error {
+			return t.Plate(cmd.Now)
+		})
+		if err != nil {
+			// the station plated it in the meantime
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": t.OrderID,
+				"err":      err.Error(),
+			}).Warn("Plate ticket fail")
+			continue
+		}
+		res.Plated = append(res.Plated, t.OrderID)
+	}
+
+	unreported, err := s.ticketRepo.FindUnreported(ctx, cmd.Limit)
+	if err != nil {
+		return res, err
+	}
+	for _, t := range unreported {
+		if err = report(ctx, s.ticketRepo, s.orderGRPC, t); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": t.OrderID,
+				"status":   t.OrderStatus(),
+				"err":      err.Error(),
+			}).Warn("Report ticket fail")
+			continue
+		}
+		res.Reported = append(res.Reported, t.OrderID)
+	}
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// ListOpenTickets lists queued and cooking tickets, oldest paid first
+type ListOpenTickets struct {
+	Limit int
+}
+
+type ListOpenTicketsHandler decorator.QueryHandler[ListOpenTickets, []*ticket.Ticket]
+
+type listOpenTicketsHandler struct {
+	ticketRepo ticket.Repository
+}
+
+func NewListOpenTicketsHandler(
+	ticketRepo ticket.Repository,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) ListOpenTicketsHandler {
+	if ticketRepo == nil {
+		panic("nil ticketRepo")
+	}
+	return decorator.ApplyQueryDecorators[ListOpenTickets, []*ticket.Ticket](
+		listOpenTicketsHandler{ticketRepo: ticketRepo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (l listOpenTicketsHandler) Handle(ctx context.Context, query ListOpenTickets) ([]*ticket.Ticket, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+	return l.ticketRepo.List(ctx, []ticket.Status{ticket.StatusQueued, ticket.StatusCooking}, limit)
+}
@@ -0,0 +1,50 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Repository interface {
+	// Create fails with an AlreadyExistsError when the order already has a ticket.
+	Create(ctx context.Context, t *Ticket) error
+	Get(ctx context.Context, orderID string) (*Ticket, error)
+	// Update loads the ticket, lets updateFn change it and saves it,
+	// it fails with a ConflictError when the ticket changed in between.
+	Update(ctx context.Context, orderID string, updateFn func(context.Context, *Ticket) error) error
+	// Queued returns at most limit queued tickets, oldest PaidAt first.
+	Queued(ctx context.Context, limit int) ([]*Ticket, error)
+	// Ahead returns the cooking tickets and the queued tickets paid before t, what the kitchen does before t.
+	Ahead(ctx context.Context, t *Ticket) ([]*Ticket, error)
+	// List returns at most limit tickets in one of statuses, oldest PaidAt first.
+	List(ctx context.Context, statuses []Status, limit int) ([]*Ticket, error)
+	// FindDone returns at most limit cooking tickets nobody claimed that were ready before now.
+	FindDone(ctx context.Context, now time.Time, limit int) ([]*Ticket, error)
+	// FindUnreported returns at most limit tickets whose status the order service has not accepted yet.
+	FindUnreported(ctx context.Context, limit int) ([]*Ticket, error)
+}
+
+type NotFoundError struct {
+	OrderID string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("ticket of order %s not found", e.OrderID)
+}
+
+type AlreadyExistsError struct {
+	OrderID string
+}
+
+func (e AlreadyExistsError) Error() string {
+	return fmt.Sprintf("ticket of order %s already exists", e.OrderID)
+}
+
+type ConflictError struct {
+	OrderID string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("ticket of order %s changed concurrently", e.OrderID)
+}
@@ -0,0 +1,247 @@
+package ticket
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+)
+
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusCooking Status = "cooking"
+	StatusPlated  Status = "plated"
+	// StatusRejected the kitchen can not make the order, it is refunded
+	StatusRejected Status = "rejected"
+)
+
+// Ticket is one paid order in the kitchen, stations take the oldest queued ticket first.
+type Ticket struct {
+	OrderID     string
+	CustomerID  string
+	PaymentLink string
+	Items       []*entity.Item
+	Status      Status
+	Station     string        // 开始制作后才有, <kitchen 实例>-<编号>
+	PrepTime    time.Duration // sum of the prep time of every item
+	PaidAt      time.Time     // priority, older first
+	QueuedAt    time.Time
+	StartedAt   time.Time
+	PlatedAt    time.Time
+	// EstimatedReadyAt is sent to the order service, see Estimate
+	EstimatedReadyAt time.Time
+	// ClaimedBy is the staff member working on the ticket, stations do not plate a claimed ticket
+	ClaimedBy    string
+	DoneItems    []string // ids of the items marked done
+	RejectReason string
+	RejectedAt   time.Time
+	// Reported is the last order status the order service accepted for this ticket
+	Reported string
+}
+
+// PrepTimes is how long one of an item takes, Default for items not in ByItem.
+type PrepTimes struct {
+	ByItem  map[string]time.Duration // keyed by lower case item id, viper lowercases map keys
+	Default time.Duration
+}
+
+func (p PrepTimes) Of(items []*entity.Item) time.Duration {
+	var total time.Duration
+	for _, it := range items {
+		d, ok := p.ByItem[strings.ToLower(it.ID)]
+		if !ok {
+			d = p.Default
+		}
+		total += d * time.Duration(it.Quantity)
+	}
+	return total
+}
+
+var (
+	ErrNotQueued      = errors.New("ticket is not queued")
+	ErrNotCooking     = errors.New("ticket is not cooking")
+	ErrFinished       = errors.New("ticket is already plated or rejected")
+	ErrAlreadyClaimed = errors.New("ticket is claimed by someone else")
+	ErrUnknownItem    = errors.New("item is not on the ticket")
+)
+
+func New(o *entity.Order, paidAt, now time.Time, prepTimes PrepTimes) (*Ticket, error) {
+	if o.ID == "" {
+		return nil, errors.New("empty order id")
+	}
+	if o.Status != constants.OrderStatusPaid {
+		return nil, fmt.Errorf("order %s is %s, only paid orders are cooked", o.ID, o.Status)
+	}
+	if len(o.Items) == 0 {
+		return nil, fmt.Errorf("order %s has no items", o.ID)
+	}
+	return &Ticket{
+		OrderID:     o.ID,
+		CustomerID:  o.CustomerID,
+		PaymentLink: o.PaymentLink,
+		Items:       slices.Clone(o.Items),
+		Status:      StatusQueued,
+		PrepTime:    prepTimes.Of(o.Items),
+		PaidAt:      paidAt,
+		QueuedAt:    now,
+		Reported:    constants.OrderStatusPaid,
+	}, nil
+}
+
+// Start puts the ticket on station.
+func (t *Ticket) Start(station string, now time.Time) error {
+	if t.Status != StatusQueued {
+		return ErrNotQueued
+	}
+	t.Status = StatusCooking
+	t.Station = station
+	t.StartedAt = now
+	t.EstimatedReadyAt = t.ReadyAt()
+	return nil
+}
+
+/*
+Estimate 估计排队中的 t 什么时候做好: stations 个 station 平分排在 t 前面的工作
+(制作中 ticket 的剩余时间和更早支付的排队 ticket 的制作时间), 之后再做 t 自己.
+制作中的 ticket 就是 ReadyAt.
+*/
+func (t *Ticket) Estimate(ahead []*Ticket, stations int, now time.Time) {
+	if t.Status != StatusQueued {
+		t.EstimatedReadyAt = t.ReadyAt()
+		return
+	}
+	var work time.Duration
+	for _, a := range ahead {
+		switch {
+		case a.OrderID == t.OrderID:
+		case a.Status == StatusCooking:
+			work += max(a.ReadyAt().Sub(now), 0)
+		case a.Status == StatusQueued:
+			work += a.PrepTime
+		}
+	}
+	t.EstimatedReadyAt = now.Add(work/time.Duration(max(stations, 1)) + t.PrepTime)
+}
+
+// Claim gives the ticket to staff, a queued ticket is started on station,
+// a ticket a station is already cooking keeps its station.
+func (t *Ticket) Claim(staff, station string, now time.Time) error {
+	if staff == "" {
+		return errors.New("empty staff")
+	}
+	switch t.Status {
+	case StatusQueued:
+		if err := t.Start(station, now); err != nil {
+			return err
+		}
+	case StatusCooking:
+		if t.ClaimedBy != "" && t.ClaimedBy != staff {
+			return ErrAlreadyClaimed
+		}
+	default:
+		return ErrFinished
+	}
+	t.ClaimedBy = staff
+	return nil
+}
+
+// MarkItemsDone plates the ticket once every item is done.
+func (t *Ticket) MarkItemsDone(itemIDs []string, now time.Time) error {
+	if t.Status != StatusCooking {
+		return ErrNotCooking
+	}
+	for _, id := range itemIDs {
+		if !slices.ContainsFunc(t.Items, func(it *entity.Item) bool { return it.ID == id }) {
+			return fmt.Errorf("%w: %s", ErrUnknownItem, id)
+		}
+		if !slices.Contains(t.DoneItems, id) {
+			t.DoneItems = append(t.DoneItems, id)
+		}
+	}
+	if len(t.DoneItems) == len(t.Items) {
+		return t.Plate(now)
+	}
+	return nil
+}
+
+// Bump plates a cooking ticket now whatever is left on it, a queued ticket has to be claimed on a station first.
+func (t *Ticket) Bump(now time.Time) error {
+	switch t.Status {
+	case StatusQueued:
+		return ErrNotCooking
+	case StatusCooking:
+	default:
+		return ErrFinished
+	}
+	return t.Plate(now)
+}
+
+// Reject gives up on an order that is not plated yet, the order service refunds it.
+func (t *Ticket) Reject(reason string, now time.Time) error {
+	if reason == "" {
+		return errors.New("empty reject reason")
+	}
+	if !t.Open() {
+		return ErrFinished
+	}
+	t.Status = StatusRejected
+	t.RejectReason = reason
+	t.RejectedAt = now
+	return nil
+}
+
+// Open tells whether the ticket is still in the kitchen.
+func (t *Ticket) Open() bool {
+	return t.Status == StatusQueued || t.Status == StatusCooking
+}
+
+// Plate finishes cooking, it is a no-op on a plated ticket so that a late station and the sweeper can both call it.
+func (t *Ticket) Plate(now time.Time) error {
+	switch t.Status {
+	case StatusPlated:
+		return nil
+	case StatusCooking:
+		t.Status = StatusPlated
+		t.PlatedAt = now
+		return nil
+	}
+	return ErrNotCooking
+}
+
+// ReadyAt is when a cooking ticket is done.
+func (t *Ticket) ReadyAt() time.Time {
+	return t.StartedAt.Add(t.PrepTime)
+}
+
+// OrderStatus is the status the order has while the ticket is in its status.
+func (t *Ticket) OrderStatus() string {
+	switch t.Status {
+	case StatusCooking:
+		return constants.OrderStatusCooking
+	case StatusPlated:
+		return constants.OrderStatusReady
+	case StatusRejected:
+		return constants.OrderStatusRejected
+	}
+	return constants.OrderStatusPaid
+}
+
+// Unreported tells whether the order service has not seen the current status yet.
+func (t *Ticket) Unreported() bool {
+	return t.Reported != t.OrderStatus()
+}
+
+func (t *Ticket) Order() *entity.Order {
+	o := entity.NewOrder(t.OrderID, t.CustomerID, t.OrderStatus(), t.PaymentLink, t.Items)
+	o.PaidAt = t.PaidAt
+	if t.Open() {
+		o.EstimatedReadyAt = t.EstimatedReadyAt
+	}
+	return o
+}
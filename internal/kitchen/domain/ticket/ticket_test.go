@@ -0,0 +1,123 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPrepTimes = PrepTimes{
+	ByItem:  map[string]time.Duration{"prod_burger": 3 * time.Minute},
+	Default: time.Minute,
+}
+
+func testOrder(status string) *entity.Order {
+	return entity.NewOrder("order-1", "customer-1", status, "https://pay.example/1", []*entity.Item{
+		{ID: "prod_Burger", Quantity: 2},
+		{ID: "prod_fries", Quantity: 1},
+	})
+}
+
+func TestTicket_Lifecycle(t *testing.T) {
+	now := time.Now()
+	tk, err := New(testOrder(constants.OrderStatusPaid), now.Add(-time.Minute), now, testPrepTimes)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, tk.Status)
+	// 2 burgers and one item without its own prep time
+	assert.Equal(t, 7*time.Minute, tk.PrepTime)
+	assert.False(t, tk.Unreported())
+
+	require.NoError(t, tk.Start("kitchen-a-2", now))
+	assert.Equal(t, "kitchen-a-2", tk.Station)
+	assert.Equal(t, now.Add(7*time.Minute), tk.ReadyAt())
+	assert.Equal(t, constants.OrderStatusCooking, tk.OrderStatus())
+	assert.True(t, tk.Unreported())
+	assert.ErrorIs(t, tk.Start("kitchen-a-1", now), ErrNotQueued)
+
+	require.NoError(t, tk.Plate(now))
+	require.NoError(t, tk.Plate(now), "plating twice is a no-op")
+	assert.Equal(t, constants.OrderStatusReady, tk.Order().Status)
+}
+
+func TestNew_NotPaid(t *testing.T) {
+	_, err := New(testOrder(constants.OrderStatusWaitingForPayment), time.Now(), time.Now(), testPrepTimes)
+	assert.Error(t, err)
+}
+
+func TestPlate_NotStarted(t *testing.T) {
+	tk, err := New(testOrder(constants.OrderStatusPaid), time.Now(), time.Now(), testPrepTimes)
+	require.NoError(t, err)
+	assert.ErrorIs(t, tk.Plate(time.Now()), ErrNotCooking)
+}
+
+func TestTicket_StaffFlow(t *testing.T) {
+	now := time.Now()
+	tk, err := New(testOrder(constants.OrderStatusPaid), now, now, testPrepTimes)
+	require.NoError(t, err)
+
+	require.NoError(t, tk.Claim("alice", "kitchen-a-1", now))
+	assert.Equal(t, StatusCooking, tk.Status)
+	assert.Equal(t, "kitchen-a-1", tk.Station)
+	require.NoError(t, tk.Claim("alice", "kitchen-a-2", now), "claiming again is a no-op")
+	assert.Equal(t, "kitchen-a-1", tk.Station)
+	assert.ErrorIs(t, tk.Claim("bob", "kitchen-a-2", now), ErrAlreadyClaimed)
+
+	assert.ErrorIs(t, tk.MarkItemsDone([]string{"prod_soup"}, now), ErrUnknownItem)
+	require.NoError(t, tk.MarkItemsDone([]string{"prod_Burger"}, now))
+	assert.Equal(t, StatusCooking, tk.Status)
+	require.NoError(t, tk.MarkItemsDone([]string{"prod_Burger", "prod_fries"}, now))
+	assert.Equal(t, StatusPlated, tk.Status)
+
+	assert.ErrorIs(t, tk.Reject("too late", now), ErrFinished)
+	assert.ErrorIs(t, tk.Bump(now), ErrFinished)
+}
+
+func TestTicket_Reject(t *testing.T) {
+	now := time.Now()
+	tk, err := New(testOrder(constants.OrderStatusPaid), now, now, testPrepTimes)
+	require.NoError(t, err)
+
+	assert.Error(t, tk.Reject("", now))
+	require.NoError(t, tk.Reject("out of buns", now))
+	assert.False(t, tk.Open())
+	assert.Equal(t, constants.OrderStatusRejected, tk.OrderStatus())
+	assert.True(t, tk.Unreported())
+	assert.ErrorIs(t, tk.Claim("alice", "kitchen-a-1", now), ErrFinished)
+}
+
+func TestTicket_BumpQueued(t *testing.T) {
+	now := time.Now()
+	tk, err := New(testOrder(constants.OrderStatusPaid), now, now, testPrepTimes)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, tk.Bump(now), ErrNotCooking)
+	assert.Equal(t, StatusQueued, tk.Status)
+
+	require.NoError(t, tk.Claim("alice", "kitchen-a-1", now))
+	require.NoError(t, tk.Bump(now))
+	assert.Equal(t, StatusPlated, tk.Status)
+	assert.Equal(t, "kitchen-a-1", tk.Station)
+	assert.Equal(t, constants.OrderStatusReady, tk.OrderStatus())
+}
+
+func TestTicket_Estimate(t *testing.T) {
+	now := time.Now()
+	cooking := &Ticket{OrderID: "order-0", Status: StatusCooking, StartedAt: now.Add(-time.Minute), PrepTime: 3 * time.Minute}
+	queued := &Ticket{OrderID: "order-2", Status: StatusQueued, PrepTime: 4 * time.Minute}
+	tk, err := New(testOrder(constants.OrderStatusPaid), now, now, testPrepTimes)
+	require.NoError(t, err)
+
+	// 2 minutes left on the cooking ticket and 4 queued, shared by 2 stations, then its own 7
+	tk.Estimate([]*Ticket{cooking, queued, tk}, 2, now)
+	assert.Equal(t, now.Add(10*time.Minute), tk.EstimatedReadyAt)
+	assert.Equal(t, tk.EstimatedReadyAt, tk.Order().EstimatedReadyAt)
+
+	require.NoError(t, tk.Start("kitchen-a-1", now))
+	assert.Equal(t, tk.ReadyAt(), tk.EstimatedReadyAt)
+	require.NoError(t, tk.Plate(now))
+	assert.True(t, tk.Order().EstimatedReadyAt.IsZero())
+}
@@ -2,128 +2,86 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/peiyouyao/gorder/common/broker"
 	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/convert"
-	"github.com/peiyouyao/gorder/common/entity"
-	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/kitchen/app"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
 	"github.com/pkg/errors"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 )
 
-type OrderService interface {
-	UpdateOrder(ctx context.Context, request *orderpb.Order) error
-}
-
 /*
-消费 mq 中 order.paid 消息
+消费 mq 中 order.paid 消息, 为订单排一个 ticket
 */
 type Consumer struct {
-	orderGRPC OrderService
-}
-
-func NewConsumer(orderGRPC OrderService) *Consumer {
-	return &Consumer{orderGRPC: orderGRPC}
+	app   app.Application
+	dedup *broker.Dedup
 }
 
-func (c *Consumer) Listen(ch *amqp.Channel) {
-	q, err := ch.QueueDeclare("", true, false, true, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	if err = ch.QueueBind(q.Name, "", broker.EventOrderPaid, false, nil); err != nil {
-		logrus.Fatal(err)
-	}
-
-	msgs, err := ch.Consume(q.Name, "", false, false, false, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
+func NewConsumer(app app.Application) *Consumer {
+	return &Consumer{
+		app:   app,
+		dedup: broker.NewDedup("kitchen"),
 	}
+}
 
-	for msg := range msgs {
-		c.handleMessage(ch, msg, q)
-	}
+func (c *Consumer) Listen(ctx context.Context, sub broker.Subscriber) error {
+	// 具名持久队列, kitchen 实例共享, 全部停机期间的消息不会丢
+	return sub.Subscribe(ctx, broker.Subscription{
+		Queue:    "kitchen." + broker.EventOrderPaid,
+		Exchange: broker.EventOrderPaid,
+	}, c.handleMessage)
 }
 
-func (c *Consumer) handleMessage(ch *amqp.Channel, msg amqp.Delivery, q amqp.Queue) {
+func (c *Consumer) handleMessage(d broker.Delivery) {
+	msg := d.Message()
 	logrus.WithFields(logrus.Fields{
-		"from_q": q.Name,
-		"msg_id": msg.MessageId,
-	}).Info("Receive order.create msg")
+		"from_q": d.Queue(),
+		"msg_id": msg.ID,
+	}).Info("Receive order.paid msg")
 
 	tr := otel.Tracer("rabbitmq")
 	ctx, span := tr.Start(
 		broker.ExtractRabbitMQHeaders(context.Background(), msg.Headers),
-		fmt.Sprintf("rabbitmq.%s.consume", q.Name),
+		fmt.Sprintf("rabbitmq.%s.consume", d.Queue()),
 	)
 	defer span.End()
 
-	var err error
-	defer func() {
+	c.dedup.Handle(ctx, d, func(ctx context.Context) error {
+		env, err := broker.DecodeEvent(msg, broker.EventOrderPaid)
 		if err != nil {
-			fs := logrus.Fields{
-				"q_name": q.Name,
-				"q_msg":  msg,
-				"err":    err.Error(),
-			}
-			logrus.WithContext(ctx).WithFields(fs).Warn("MQ consume fail")
-			_ = msg.Nack(false, false)
-		} else {
-			logrus.WithContext(ctx).Info("MQ consume ok")
-			_ = msg.Ack(false)
+			logrus.WithField("err", err.Error()).Warn("Decode event fail")
+			return broker.Unprocessable(err)
 		}
-	}()
-
-	o := &entity.Order{}
-	if err = json.Unmarshal(msg.Body, o); err != nil {
-		logrus.WithField("err", err.Error()).Warn("Unmarshal fail")
-		return
-	}
-
-	if o.Status != constants.OrderStatusPaid {
-		err = errors.New("order not paid can not cook")
-		return
-	}
-	cook(ctx, o)
+		o := convert.OrderProtoToEntity(env.GetOrder())
 
-	span.AddEvent(fmt.Sprintf("order.cooked.%v", o))
-	if err := c.orderGRPC.UpdateOrder(ctx, &orderpb.Order{
-		ID:          o.ID,
-		CustomerID:  o.CustomerID,
-		Status:      constants.OrderStatusReady,
-		Items:       convert.ItemEntitiesToProtos(o.Items),
-		PaymentLink: o.PaymentLink,
-	}); err != nil {
-		fs := logrus.Fields{
-			"order_id": o.ID,
-			"q_name":   q.Name,
-			"q_msg":    msg,
-			"err":      err.Error(),
+		if o.Status != constants.OrderStatusPaid {
+			return broker.Unprocessable(errors.New("order not paid can not cook"))
 		}
-		logrus.WithContext(ctx).WithFields(fs).Error("Update order fail")
-		// retry
-		if err = broker.HandleRetry(ctx, ch, &msg); err != nil {
-			logrus.WithFields(logrus.Fields{
-				"msg_id": msg.MessageId,
-				"err":    err.Error(),
-			}).Warn("Retry fail")
+		paidAt := time.UnixMilli(env.GetOccurredAt())
+		if env.GetOccurredAt() == 0 {
+			// schema version 1 没有 OccurredAt
+			paidAt = time.Now()
 		}
-		return
-	}
 
-	span.AddEvent("kitchen.order.finished.updated")
-	logrus.Info("Consume create.order ok")
-}
+		if _, err = c.app.Commands.EnqueueTicket.Handle(ctx, command.EnqueueTicket{Order: o, PaidAt: paidAt}); err != nil {
+			fs := logrus.Fields{
+				"order_id": o.ID,
+				"q_name":   d.Queue(),
+				"q_msg":    msg,
+				"err":      err.Error(),
+			}
+			logrus.WithContext(ctx).WithFields(fs).Error("Enqueue ticket fail")
+			return err
+		}
 
-func cook(ctx context.Context, o *entity.Order) {
-	logrus.WithContext(ctx).Infof("Cooking order_id=%s", o.ID)
-	time.Sleep(5 * time.Second)
-	logrus.WithContext(ctx).Infof("Cooking done order_id=%s", o.ID)
+		span.AddEvent(fmt.Sprintf("kitchen.ticket.queued.%s", o.ID))
+		logrus.Info("Consume order.paid ok")
+		return nil
+	})
 }
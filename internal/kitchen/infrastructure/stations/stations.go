@@ -0,0 +1,126 @@
+package stations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/peiyouyao/gorder/kitchen/app"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+Kitchen 运行 kitchen.stations 个 station, 每个 station 同一时间只做一个 ticket:
+取最早支付的排队 ticket, 等它的 PrepTime 过去后出餐. 多个 kitchen 实例共享同一个队列.
+*/
+type Kitchen struct {
+	app           app.Application
+	instance      string // station 名字的前缀, 区分不同的 kitchen 实例
+	stations      int
+	pollInterval  time.Duration
+	sweepInterval time.Duration
+	batchSize     int
+}
+
+func NewKitchen(app app.Application) *Kitchen {
+	return &Kitchen{
+		app:           app,
+		instance:      instanceID(),
+		stations:      viper.GetInt("kitchen.stations"),
+		pollInterval:  viper.GetDuration("kitchen.poll-interval") * time.Second,
+		sweepInterval: viper.GetDuration("kitchen.sweeper.interval") * time.Second,
+		batchSize:     viper.GetInt("kitchen.sweeper.batch-size"),
+	}
+}
+
+// Run returns after ctx is done and every station stopped, a ticket left cooking is plated by the sweeper later.
+func (k *Kitchen) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{
+		"instance": k.instance,
+		"stations": k.stations,
+	}).Info("Kitchen started")
+	var wg sync.WaitGroup
+	for n := 1; n <= k.stations; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			k.station(ctx, fmt.Sprintf("%s-%d", k.instance, n))
+		}(n)
+	}
+
+	ticker := time.NewTicker(k.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logrus.Info("Kitchen stopped")
+			return
+		case <-ticker.C:
+			k.sweep(ctx)
+		}
+	}
+}
+
+func (k *Kitchen) station(ctx context.Context, n string) {
+	for {
+		t, err := k.app.Commands.StartNextTicket.Handle(ctx, command.StartNextTicket{Station: n})
+		if err != nil || t == nil {
+			if err != nil {
+				logrus.WithContext(ctx).WithField("station", n).Warnf("Start next ticket fail err=%v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(k.pollInterval):
+			}
+			continue
+		}
+
+		timer := time.NewTimer(time.Until(t.ReadyAt()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if _, err = k.app.Commands.PlateTicket.Handle(ctx, command.PlateTicket{OrderID: t.OrderID}); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"station":  n,
+				"order_id": t.OrderID,
+			}).Warnf("Plate ticket fail err=%v", err)
+		}
+	}
+}
+
+// kitchen.instance-id, 没有配置时用主机名
+func instanceID() string {
+	if id := viper.GetString("kitchen.instance-id"); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return viper.GetString("kitchen.service-name")
+	}
+	return host
+}
+
+func (k *Kitchen) sweep(ctx context.Context) {
+	res, err := k.app.Commands.SweepTickets.Handle(ctx, command.SweepTickets{
+		Now:   time.Now(),
+		Limit: k.batchSize,
+	})
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("Sweep tickets fail err=%v", err)
+	}
+	if res != nil && (len(res.Plated) > 0 || len(res.Reported) > 0) {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"plated":   res.Plated,
+			"reported": res.Reported,
+		}).Info("Swept tickets")
+	}
+}
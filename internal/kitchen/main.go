@@ -2,19 +2,26 @@ package main
 
 import (
 	"context"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
+	"github.com/gin-gonic/gin"
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/broker"
-	grpcClient "github.com/peiyouyao/gorder/common/client"
 	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/discovery"
+	"github.com/peiyouyao/gorder/common/genproto/kitchenpb"
 	"github.com/peiyouyao/gorder/common/logging"
+	"github.com/peiyouyao/gorder/common/server"
 	"github.com/peiyouyao/gorder/common/tracing"
-	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app"
 	"github.com/peiyouyao/gorder/kitchen/infrastructure/consumer"
+	"github.com/peiyouyao/gorder/kitchen/infrastructure/stations"
+	"github.com/peiyouyao/gorder/kitchen/ports"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
 )
 
 func init() {
@@ -23,45 +30,65 @@ func init() {
 
 func main() {
 	serviceName := viper.GetString("kitchen.service-name")
+	actor.SetService(serviceName)
 
-	ctx, cancal := context.WithCancel(context.Background())
+	// SIGINT / SIGTERM 后停止接收消息, 等处理中的消息 ack 完再退出
+	ctx, cancal := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancal()
 
 	shutdown, err := tracing.InitJaegerProvider(viper.GetString("jaeger.url"), serviceName)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer shutdown(ctx)
+	defer shutdown(context.Background())
 
-	orderGRPCCli, closeFn, err := grpcClient.NewOrderGRPCClient(ctx)
+	application, cleanup := app.NewApplication(ctx)
+	defer cleanup()
+
+	deregisterFn, err := discovery.RegisterToConsul(ctx, serviceName)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer closeFn()
-
-	ch, closeCh := broker.Connect(
-		viper.GetString("rabbitmq.user"),
-		viper.GetString("rabbitmq.password"),
-		viper.GetString("rabbitmq.host"),
-		viper.GetString("rabbitmq.port"),
-	)
 	defer func() {
-		_ = ch.Close()
-		_ = closeCh()
+		_ = deregisterFn()
 	}()
 
-	orderGRPC := adapters.NewOrderGRPC(orderGRPCCli)
-	go consumer.NewConsumer(orderGRPC).Listen(ch)
+	b, closeBroker := broker.Open(serviceName)
+	// 消费者 drain 完后才关闭
+	defer func() {
+		_ = closeBroker()
+	}()
 
-	// ^C 退出 kitchen
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	var consumers sync.WaitGroup
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		if err := consumer.NewConsumer(application).Listen(ctx, b); err != nil {
+			logrus.WithField("consumer", "kitchen").Warnf("Consumer stopped err=%v", err)
+		}
+	}()
 
+	consumers.Add(1)
 	go func() {
-		<-sigs
-		logrus.Info("Receive signal, exiting ...")
-		os.Exit(0)
+		defer consumers.Done()
+		stations.NewKitchen(application).Run(ctx)
 	}()
+
+	go server.RunGRPCServer(serviceName, func(server *grpc.Server) {
+		svc := ports.NewGRPCServer(application)
+		kitchenpb.RegisterKitchenServiceServer(server, svc)
+	})
+
+	go server.RunHTTPServer(serviceName, func(router *gin.Engine) {
+		ports.RegisterHandlersWithOptions(router, &ports.HTTPServer{App: application}, ports.GinServerOptions{
+			BaseURL:      "/api",
+			Middlewares:  nil,
+			ErrorHandler: nil,
+		})
+	})
+
 	logrus.Println("To exit, press Ctrl+C")
-	select {}
+	<-ctx.Done()
+	logrus.Info("Receive signal, draining consumers and stations ...")
+	consumers.Wait()
 }
@@ -0,0 +1,135 @@
+package ports
+
+import (
+	context "context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/genproto/kitchenpb"
+	"github.com/peiyouyao/gorder/kitchen/app"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/app/query"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// impl kitchenpb.KitchenServiceServer
+type GRPCServer struct {
+	app app.Application
+}
+
+func NewGRPCServer(app app.Application) *GRPCServer {
+	return &GRPCServer{app: app}
+}
+
+func (s *GRPCServer) ListTickets(ctx context.Context, request *kitchenpb.ListTicketsRequest) (*kitchenpb.ListTicketsResponse, error) {
+	tickets, err := s.app.Queries.ListOpenTickets.Handle(ctx, query.ListOpenTickets{Limit: int(request.Limit)})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &kitchenpb.ListTicketsResponse{Tickets: make([]*kitchenpb.Ticket, 0, len(tickets))}
+	for _, t := range tickets {
+		resp.Tickets = append(resp.Tickets, ticketToProto(t))
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) ClaimTicket(ctx context.Context, request *kitchenpb.ClaimTicketRequest) (*kitchenpb.Ticket, error) {
+	return toProtoResponse(s.app.Commands.ClaimTicket.Handle(ctx, command.ClaimTicket{
+		OrderID: request.OrderID,
+		Staff:   request.Staff,
+		Station: request.Station,
+	}))
+}
+
+func (s *GRPCServer) MarkItemsDone(ctx context.Context, request *kitchenpb.MarkItemsDoneRequest) (*kitchenpb.Ticket, error) {
+	return toProtoResponse(s.app.Commands.MarkItemsDone.Handle(ctx, command.MarkItemsDone{
+		OrderID: request.OrderID,
+		ItemIDs: request.ItemIDs,
+	}))
+}
+
+func (s *GRPCServer) BumpTicket(ctx context.Context, request *kitchenpb.BumpTicketRequest) (*kitchenpb.Ticket, error) {
+	return toProtoResponse(s.app.Commands.BumpTicket.Handle(ctx, command.BumpTicket{OrderID: request.OrderID}))
+}
+
+func (s *GRPCServer) RejectTicket(ctx context.Context, request *kitchenpb.RejectTicketRequest) (*kitchenpb.Ticket, error) {
+	return toProtoResponse(s.app.Commands.RejectTicket.Handle(ctx, command.RejectTicket{
+		OrderID: request.OrderID,
+		Reason:  request.Reason,
+	}))
+}
+
+func toProtoResponse(t *ticket.Ticket, err error) (*kitchenpb.Ticket, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return ticketToProto(t), nil
+}
+
+func toStatus(err error) error {
+	var notFound ticket.NotFoundError
+	var conflict ticket.ConflictError
+	switch {
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &conflict):
+		return status.Error(codes.Aborted, err.Error())
+	case isStateError(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// isStateError tells whether the ticket is not in a state that allows the change
+func isStateError(err error) bool {
+	for _, target := range []error{
+		ticket.ErrNotQueued,
+		ticket.ErrNotCooking,
+		ticket.ErrFinished,
+		ticket.ErrAlreadyClaimed,
+		ticket.ErrUnknownItem,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func ticketToProto(t *ticket.Ticket) *kitchenpb.Ticket {
+	res := &kitchenpb.Ticket{
+		OrderID:      t.OrderID,
+		CustomerID:   t.CustomerID,
+		Status:       string(t.Status),
+		Items:        make([]*kitchenpb.TicketItem, 0, len(t.Items)),
+		Station:      t.Station,
+		ClaimedBy:    t.ClaimedBy,
+		PrepTime:     int64(t.PrepTime / time.Second),
+		PaidAt:       unixMilli(t.PaidAt),
+		StartedAt:    unixMilli(t.StartedAt),
+		PlatedAt:     unixMilli(t.PlatedAt),
+		RejectReason: t.RejectReason,
+	}
+	if !t.StartedAt.IsZero() {
+		res.ReadyAt = t.ReadyAt().UnixMilli()
+	}
+	for _, it := range t.Items {
+		res.Items = append(res.Items, &kitchenpb.TicketItem{
+			Item: convert.ItemEntityToProto(it),
+			Done: slices.Contains(t.DoneItems, it.ID),
+		})
+	}
+	return res
+}
+
+// 0 when t is zero
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,46 @@
+package ports_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/genproto/kitchenpb"
+	"github.com/peiyouyao/gorder/kitchen/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer(t *testing.T) {
+	ctx := context.Background()
+	server := ports.NewGRPCServer(newTestApp(t))
+
+	_, err := server.MarkItemsDone(ctx, &kitchenpb.MarkItemsDoneRequest{OrderID: "order-1", ItemIDs: []string{"item-1"}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	tk, err := server.ClaimTicket(ctx, &kitchenpb.ClaimTicketRequest{OrderID: "order-1", Staff: "alice", Station: "kitchen-a-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "cooking", tk.Status)
+	assert.Equal(t, "alice", tk.ClaimedBy)
+	assert.NotZero(t, tk.ReadyAt)
+
+	_, err = server.ClaimTicket(ctx, &kitchenpb.ClaimTicketRequest{OrderID: "order-1", Staff: "bob"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	tk, err = server.MarkItemsDone(ctx, &kitchenpb.MarkItemsDoneRequest{OrderID: "order-1", ItemIDs: []string{"item-1", "item-2"}})
+	require.NoError(t, err)
+	assert.Equal(t, "plated", tk.Status)
+	for _, it := range tk.Items {
+		assert.True(t, it.Done)
+	}
+
+	list, err := server.ListTickets(ctx, &kitchenpb.ListTicketsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Tickets)
+
+	_, err = server.RejectTicket(ctx, &kitchenpb.RejectTicketRequest{OrderID: "missing", Reason: "out of buns"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = server.BumpTicket(ctx, &kitchenpb.BumpTicketRequest{OrderID: "order-1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
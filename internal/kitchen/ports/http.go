@@ -0,0 +1,183 @@
+package ports
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	client "github.com/peiyouyao/gorder/common/client/kitchen"
+	"github.com/peiyouyao/gorder/common/constants"
+	myerrors "github.com/peiyouyao/gorder/common/handler/errors"
+	common "github.com/peiyouyao/gorder/common/response"
+	"github.com/peiyouyao/gorder/kitchen/app"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/app/query"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+)
+
+type HTTPServer struct {
+	common.BaseResponse // 继承
+	App                 app.Application
+}
+
+func (s *HTTPServer) GetTickets(c *gin.Context, params GetTicketsParams) {
+	var (
+		err  error
+		resp struct {
+			Tickets []client.Ticket `json:"tickets"`
+		}
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	q := query.ListOpenTickets{}
+	if params.Limit != nil {
+		q.Limit = int(*params.Limit)
+	}
+	tickets, err := s.App.Queries.ListOpenTickets.Handle(c.Request.Context(), q)
+	if err != nil {
+		return
+	}
+	resp.Tickets = make([]client.Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		resp.Tickets = append(resp.Tickets, *ticketToClient(t))
+	}
+}
+
+func (s *HTTPServer) PostTicketsOrderIdClaim(c *gin.Context, orderID string) {
+	var (
+		req  client.ClaimTicketRequest
+		err  error
+		resp ticketResponse
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	if err = c.ShouldBind(&req); err != nil {
+		err = myerrors.NewWithError(constants.ErrnoBindRequest, err)
+		return
+	}
+	cmd := command.ClaimTicket{OrderID: orderID, Staff: req.Staff}
+	if req.Station != nil {
+		cmd.Station = *req.Station
+	}
+	t, err := s.App.Commands.ClaimTicket.Handle(c.Request.Context(), cmd)
+	err = resp.set(t, err)
+}
+
+func (s *HTTPServer) PostTicketsOrderIdItemsDone(c *gin.Context, orderID string) {
+	var (
+		req  client.MarkItemsDoneRequest
+		err  error
+		resp ticketResponse
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	if err = c.ShouldBind(&req); err != nil {
+		err = myerrors.NewWithError(constants.ErrnoBindRequest, err)
+		return
+	}
+	t, err := s.App.Commands.MarkItemsDone.Handle(c.Request.Context(), command.MarkItemsDone{
+		OrderID: orderID,
+		ItemIDs: req.ItemIds,
+	})
+	err = resp.set(t, err)
+}
+
+func (s *HTTPServer) PostTicketsOrderIdBump(c *gin.Context, orderID string) {
+	var (
+		err  error
+		resp ticketResponse
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	t, err := s.App.Commands.BumpTicket.Handle(c.Request.Context(), command.BumpTicket{OrderID: orderID})
+	err = resp.set(t, err)
+}
+
+func (s *HTTPServer) PostTicketsOrderIdReject(c *gin.Context, orderID string) {
+	var (
+		req  client.RejectTicketRequest
+		err  error
+		resp ticketResponse
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	if err = c.ShouldBind(&req); err != nil {
+		err = myerrors.NewWithError(constants.ErrnoBindRequest, err)
+		return
+	}
+	t, err := s.App.Commands.RejectTicket.Handle(c.Request.Context(), command.RejectTicket{
+		OrderID: orderID,
+		Reason:  req.Reason,
+	})
+	err = resp.set(t, err)
+}
+
+// the ticket after a staff command
+type ticketResponse struct {
+	Ticket *client.Ticket `json:"ticket"`
+}
+
+func (r *ticketResponse) set(t *ticket.Ticket, err error) error {
+	if err != nil {
+		return toErrno(err)
+	}
+	r.Ticket = ticketToClient(t)
+	return nil
+}
+
+func toErrno(err error) error {
+	var notFound ticket.NotFoundError
+	var conflict ticket.ConflictError
+	switch {
+	case errors.As(err, &notFound):
+		return myerrors.NewWithError(constants.ErrnoNotFound, err)
+	case errors.As(err, &conflict), isStateError(err):
+		return myerrors.NewWithError(constants.ErrnoConflict, err)
+	}
+	return err
+}
+
+func ticketToClient(t *ticket.Ticket) *client.Ticket {
+	res := &client.Ticket{
+		OrderId:    t.OrderID,
+		CustomerId: t.CustomerID,
+		Status:     string(t.Status),
+		Items:      make([]client.TicketItem, 0, len(t.Items)),
+		Station:    t.Station,
+		ClaimedBy:  t.ClaimedBy,
+		PrepTime:   int64(t.PrepTime / time.Second),
+		PaidAt:     t.PaidAt,
+	}
+	if !t.StartedAt.IsZero() {
+		startedAt, readyAt := t.StartedAt, t.ReadyAt()
+		res.StartedAt, res.ReadyAt = &startedAt, &readyAt
+	}
+	if !t.PlatedAt.IsZero() {
+		platedAt := t.PlatedAt
+		res.PlatedAt = &platedAt
+	}
+	if t.RejectReason != "" {
+		reason := t.RejectReason
+		res.RejectReason = &reason
+	}
+	for _, it := range t.Items {
+		res.Items = append(res.Items, client.TicketItem{
+			Id:       it.ID,
+			Name:     it.Name,
+			Quantity: it.Quantity,
+			Done:     slices.Contains(t.DoneItems, it.ID),
+		})
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+package ports_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	client "github.com/peiyouyao/gorder/common/client/kitchen"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/kitchen/adapters"
+	"github.com/peiyouyao/gorder/kitchen/app"
+	"github.com/peiyouyao/gorder/kitchen/app/command"
+	"github.com/peiyouyao/gorder/kitchen/app/query"
+	"github.com/peiyouyao/gorder/kitchen/domain/ticket"
+	"github.com/peiyouyao/gorder/kitchen/ports"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl command.OrderService
+type nopOrderService struct{}
+
+func (nopOrderService) UpdateOrder(context.Context, *orderpb.Order) error { return nil }
+
+func (nopOrderService) RejectOrder(context.Context, *orderpb.RejectOrderRequest) error { return nil }
+
+// newTestApp 用内存 repo 组装 staff 命令, 带一个排队中的 order-1
+func newTestApp(t *testing.T) app.Application {
+	repo := adapters.NewTicketRepositoryInmem()
+	o := entity.NewOrder("order-1", "customer-1", constants.OrderStatusPaid, "https://pay.example/1", []*entity.Item{
+		{ID: "item-1", Name: "burger", Quantity: 1},
+		{ID: "item-2", Name: "fries", Quantity: 2},
+	})
+	tk, err := ticket.New(o, time.Now(), time.Now(), ticket.PrepTimes{Default: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), tk))
+
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	orders := nopOrderService{}
+	return app.Application{
+		Commands: app.Commands{
+			ClaimTicket:   command.NewClaimTicketHandler(repo, orders, logger, metrics.NoMetrics{}),
+			MarkItemsDone: command.NewMarkItemsDoneHandler(repo, orders, logger, metrics.NoMetrics{}),
+			BumpTicket:    command.NewBumpTicketHandler(repo, orders, logger, metrics.NoMetrics{}),
+			RejectTicket:  command.NewRejectTicketHandler(repo, orders, logger, metrics.NoMetrics{}),
+		},
+		Queries: app.Queries{
+			ListOpenTickets: query.NewListOpenTicketsHandler(repo, logger, metrics.NoMetrics{}),
+		},
+	}
+}
+
+type ticketResponse struct {
+	Errno int `json:"errno"`
+	Data  struct {
+		Ticket  *client.Ticket  `json:"ticket"`
+		Tickets []client.Ticket `json:"tickets"`
+	} `json:"data"`
+}
+
+func TestHTTPServer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	ports.RegisterHandlers(router, &ports.HTTPServer{App: newTestApp(t)})
+
+	do := func(method, path string, body any) (int, ticketResponse) {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp ticketResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	station := "kitchen-a-1"
+	code, resp := do(http.MethodPost, "/tickets/order-1/claim", client.ClaimTicketRequest{Staff: "alice", Station: &station})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, constants.ErrnoSuccess, resp.Errno)
+	assert.Equal(t, "cooking", resp.Data.Ticket.Status)
+	assert.Equal(t, station, resp.Data.Ticket.Station)
+	assert.NotNil(t, resp.Data.Ticket.ReadyAt)
+
+	// 已经被认领
+	code, resp = do(http.MethodPost, "/tickets/order-1/claim", client.ClaimTicketRequest{Staff: "bob"})
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, constants.ErrnoConflict, resp.Errno)
+
+	code, resp = do(http.MethodPost, "/tickets/order-1/items-done", client.MarkItemsDoneRequest{ItemIds: []string{"item-1"}})
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Data.Ticket.Items[0].Done)
+	assert.False(t, resp.Data.Ticket.Items[1].Done)
+
+	code, resp = do(http.MethodGet, "/tickets", nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Data.Tickets, 1)
+	assert.Equal(t, "order-1", resp.Data.Tickets[0].OrderId)
+
+	code, resp = do(http.MethodPost, "/tickets/order-1/bump", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "plated", resp.Data.Ticket.Status)
+	assert.NotNil(t, resp.Data.Ticket.PlatedAt)
+
+	code, _ = do(http.MethodPost, "/tickets/order-1/reject", client.RejectTicketRequest{Reason: "out of buns"})
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, resp = do(http.MethodPost, "/tickets/missing/bump", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, constants.ErrnoNotFound, resp.Errno)
+}
@@ -0,0 +1,196 @@
+// Package ports provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package ports
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oapi-codegen/runtime"
+)
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /tickets)
+	GetTickets(c *gin.Context, params GetTicketsParams)
+
+	// (POST /tickets/{order_id}/bump)
+	PostTicketsOrderIdBump(c *gin.Context, orderId string)
+
+	// (POST /tickets/{order_id}/claim)
+	PostTicketsOrderIdClaim(c *gin.Context, orderId string)
+
+	// (POST /tickets/{order_id}/items-done)
+	PostTicketsOrderIdItemsDone(c *gin.Context, orderId string)
+
+	// (POST /tickets/{order_id}/reject)
+	PostTicketsOrderIdReject(c *gin.Context, orderId string)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandler       func(*gin.Context, error, int)
+}
+
+type MiddlewareFunc func(c *gin.Context)
+
+// GetTickets operation middleware
+func (siw *ServerInterfaceWrapper) GetTickets(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTicketsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTickets(c, params)
+}
+
+// PostTicketsOrderIdBump operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketsOrderIdBump(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketsOrderIdBump(c, orderId)
+}
+
+// PostTicketsOrderIdClaim operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketsOrderIdClaim(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketsOrderIdClaim(c, orderId)
+}
+
+// PostTicketsOrderIdItemsDone operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketsOrderIdItemsDone(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketsOrderIdItemsDone(c, orderId)
+}
+
+// PostTicketsOrderIdReject operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketsOrderIdReject(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketsOrderIdReject(c, orderId)
+}
+
+// GinServerOptions provides options for the Gin server.
+type GinServerOptions struct {
+	BaseURL      string
+	Middlewares  []MiddlewareFunc
+	ErrorHandler func(*gin.Context, error, int)
+}
+
+// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
+func RegisterHandlers(router gin.IRouter, si ServerInterface) {
+	RegisterHandlersWithOptions(router, si, GinServerOptions{})
+}
+
+// RegisterHandlersWithOptions creates http.Handler with additional options
+func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
+	errorHandler := options.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(c *gin.Context, err error, statusCode int) {
+			c.JSON(statusCode, gin.H{"msg": err.Error()})
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandler:       errorHandler,
+	}
+
+	router.GET(options.BaseURL+"/tickets", wrapper.GetTickets)
+	router.POST(options.BaseURL+"/tickets/:order_id/bump", wrapper.PostTicketsOrderIdBump)
+	router.POST(options.BaseURL+"/tickets/:order_id/claim", wrapper.PostTicketsOrderIdClaim)
+	router.POST(options.BaseURL+"/tickets/:order_id/items-done", wrapper.PostTicketsOrderIdItemsDone)
+	router.POST(options.BaseURL+"/tickets/:order_id/reject", wrapper.PostTicketsOrderIdReject)
+}
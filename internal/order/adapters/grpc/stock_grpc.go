@@ -27,3 +27,18 @@ func (s StockGRPC) GetItems(ctx context.Context, itemsIDs []string) ([]*orderpb.
 	}
 	return resp.Items, nil
 }
+
+func (s StockGRPC) ReserveItems(ctx context.Context, orderID string, items []*orderpb.ItemWithQuantity) error {
+	_, err := s.client.ReserveItems(ctx, &stockpb.ReserveItemsRequest{OrderID: orderID, Items: items})
+	return err
+}
+
+func (s StockGRPC) CommitReservation(ctx context.Context, orderID string) error {
+	_, err := s.client.CommitReservation(ctx, &stockpb.CommitReservationRequest{OrderID: orderID})
+	return err
+}
+
+func (s StockGRPC) ReleaseReservation(ctx context.Context, orderID string) error {
+	_, err := s.client.ReleaseReservation(ctx, &stockpb.ReleaseReservationRequest{OrderID: orderID})
+	return err
+}
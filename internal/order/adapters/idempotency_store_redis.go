@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/handler/redis"
+	"github.com/peiyouyao/gorder/order/app/command"
+	"github.com/spf13/viper"
+)
+
+const idempotencyKeyPrefix = "gorder:order:idempotency:"
+
+// impl command.IdempotencyStore
+type IdempotencyStoreRedis struct {
+	ttl           time.Duration
+	inProgressTTL time.Duration // 请求中途崩溃时 key 只被占用这么久
+}
+
+func NewIdempotencyStoreRedis() *IdempotencyStoreRedis {
+	return &IdempotencyStoreRedis{
+		ttl:           viper.GetDuration("order.idempotency-ttl") * time.Second,
+		inProgressTTL: viper.GetDuration("order.idempotency-in-progress-ttl") * time.Second,
+	}
+}
+
+func (s *IdempotencyStoreRedis) Claim(ctx context.Context, key string, record command.IdempotencyRecord) (bool, *command.IdempotencyRecord, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return false, nil, err
+	}
+	ok, err := redis.TrySetNX(ctx, redis.LocaClient(), idempotencyKeyPrefix+key, string(value), s.inProgressTTL)
+	if err != nil || ok {
+		return ok, nil, err
+	}
+
+	stored, err := redis.Get(ctx, redis.LocaClient(), idempotencyKeyPrefix+key)
+	if err != nil {
+		return false, nil, err
+	}
+	existing := &command.IdempotencyRecord{}
+	if err = json.Unmarshal([]byte(stored), existing); err != nil {
+		return false, nil, err
+	}
+	return false, existing, nil
+}
+
+// Complete keeps the key for the full ttl.
+func (s *IdempotencyStoreRedis) Complete(ctx context.Context, key string, record command.IdempotencyRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return redis.Set(ctx, redis.LocaClient(), idempotencyKeyPrefix+key, string(value), s.ttl)
+}
+
+func (s *IdempotencyStoreRedis) Release(ctx context.Context, key string) error {
+	return redis.Del(ctx, redis.LocaClient(), idempotencyKeyPrefix+key)
+}
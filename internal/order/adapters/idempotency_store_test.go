@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/handler/redis"
+	"github.com/peiyouyao/gorder/order/app/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStoreRedis(t *testing.T) {
+	ctx := context.Background()
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := redis.LocaClient().Ping(pingCtx).Err(); err != nil {
+		t.Skipf("redis not available: %v", err)
+	}
+	s := NewIdempotencyStoreRedis()
+	s.inProgressTTL = 500 * time.Millisecond
+	key := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	t.Cleanup(func() { _ = s.Release(context.Background(), key) })
+
+	ok, _, err := s.Claim(ctx, key, command.IdempotencyRecord{Fingerprint: "a"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, existing, err := s.Claim(ctx, key, command.IdempotencyRecord{Fingerprint: "b"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, &command.IdempotencyRecord{Fingerprint: "a"}, existing)
+
+	// 没有 Complete 的 claim 很快过期
+	time.Sleep(time.Second)
+	ok, _, err = s.Claim(ctx, key, command.IdempotencyRecord{Fingerprint: "a"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Complete 后按 ttl 保存
+	done := command.IdempotencyRecord{Fingerprint: "a", OrderID: "order-1"}
+	require.NoError(t, s.Complete(ctx, key, done))
+	time.Sleep(time.Second)
+	ok, existing, err = s.Claim(ctx, key, command.IdempotencyRecord{Fingerprint: "a"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, &done, existing)
+
+	require.NoError(t, s.Release(ctx, key))
+	ok, _, err = s.Claim(ctx, key, command.IdempotencyRecord{Fingerprint: "a"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
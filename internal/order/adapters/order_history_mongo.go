@@ -0,0 +1,129 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/actor"
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/logging"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var historyCollName = viper.GetString("mongo.history-coll-name")
+
+type historyModel struct {
+	OrderID    string         `bson:"order_id"`
+	CustomerID string         `bson:"customer_id"`
+	Version    int64          `bson:"version"`
+	Type       string         `bson:"type"`
+	Old        string         `bson:"old,omitempty"`
+	New        string         `bson:"new,omitempty"`
+	Items      []*entity.Item `bson:"items,omitempty"`
+	At         time.Time      `bson:"at"`
+	Actor      string         `bson:"actor"`
+	TraceID    string         `bson:"trace_id,omitempty"`
+}
+
+func (r *OrderRepositoryMongo) History(ctx context.Context, id, customerID string) (entries []*domain.HistoryEntry, err error) {
+	fs := logrus.Fields{
+		"order_id":    id,
+		"customer_id": customerID,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.History", fs)
+	defer func() { dlog(len(entries), err) }()
+
+	// 先确认订单属于这个用户
+	if _, err = r.Get(ctx, id, customerID); err != nil {
+		return
+	}
+	cur, err := r.historyCollection().Find(
+		ctx,
+		bson.M{"order_id": id},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}),
+	)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+
+	var reads []*historyModel
+	if err = cur.All(ctx, &reads); err != nil {
+		return
+	}
+	for _, read := range reads {
+		entries = append(entries, &domain.HistoryEntry{
+			OrderID:    read.OrderID,
+			CustomerID: read.CustomerID,
+			Version:    read.Version,
+			Type:       read.Type,
+			Old:        read.Old,
+			New:        read.New,
+			Items:      read.Items,
+			At:         read.At,
+			Actor:      read.Actor,
+			TraceID:    read.TraceID,
+		})
+	}
+	return
+}
+
+// appendHistory numbers the changes after the last stored entry,
+// the unique (order_id, version) index makes a concurrent append fail instead of interleaving.
+func (r *OrderRepositoryMongo) appendHistory(ctx context.Context, o *domain.Order, changes []domain.Change) (err error) {
+	if len(changes) == 0 {
+		return nil
+	}
+	var res *mongo.InsertManyResult
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.appendHistory", logrus.Fields{"order_id": o.ID, "changes": changes})
+	defer func() { dlog(res, err) }()
+
+	last := &historyModel{}
+	err = r.historyCollection().FindOne(
+		ctx,
+		bson.M{"order_id": o.ID},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(last)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return
+	}
+
+	docs := make([]any, 0, len(changes))
+	for _, e := range domain.NewHistory(o, last.Version, changes, actor.From(ctx), traceID(ctx)) {
+		docs = append(docs, historyModel{
+			OrderID:    e.OrderID,
+			CustomerID: e.CustomerID,
+			Version:    e.Version,
+			Type:       e.Type,
+			Old:        e.Old,
+			New:        e.New,
+			Items:      e.Items,
+			At:         e.At,
+			Actor:      e.Actor,
+			TraceID:    e.TraceID,
+		})
+	}
+	res, err = r.historyCollection().InsertMany(ctx, docs)
+	return
+}
+
+func (r *OrderRepositoryMongo) historyCollection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(historyCollName)
+}
+
+// empty outside a trace
+func traceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
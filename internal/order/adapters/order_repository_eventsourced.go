@@ -0,0 +1,383 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/actor"
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/logging"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	eventsCollName    = viper.GetString("mongo.events-coll-name")
+	snapshotsCollName = viper.GetString("mongo.snapshots-coll-name")
+	viewCollName      = viper.GetString("mongo.view-coll-name")
+	countersCollName  = viper.GetString("mongo.counters-coll-name")
+)
+
+// counters 集合中事件序号的文档
+const eventSeqID = "order_events"
+
+/*
+OrderRepositoryEventSourced 只保存订单事件, Get 取最近的快照再重放之后的事件.
+每 SnapshotEvery 个事件保存一次快照; 同时维护一个和 order 集合结构相同的读模型, List 和 FindUnpaid 查它.
+事件, 快照和读模型在一个事务里写入, (order_id, version) 唯一索引让并发的 Update 只有一个成功.
+事件按同一事务中递增的计数器编号, 并发的事务在计数器上冲突后重试, 所以编号顺序就是提交顺序, ReadEvents 按它翻页.
+*/
+type OrderRepositoryEventSourced struct {
+	SnapshotEvery int64
+
+	db       *mongo.Client
+	database string
+	view     *OrderRepositoryMongo
+}
+
+type eventModel struct {
+	MongoID    primitive.ObjectID `bson:"_id"`
+	Seq        int64              `bson:"seq"` // position in the stream
+	OrderID    string             `bson:"order_id"`
+	CustomerID string             `bson:"customer_id"`
+	Version    int64              `bson:"version"`
+	Name       string             `bson:"name"`
+	Type       string             `bson:"type"`
+	Old        string             `bson:"old,omitempty"`
+	New        string             `bson:"new,omitempty"`
+	Items      []*entity.Item     `bson:"items,omitempty"`
+	At         time.Time          `bson:"at"`
+	Actor      string             `bson:"actor"`
+	TraceID    string             `bson:"trace_id,omitempty"`
+}
+
+type snapshotModel struct {
+	OrderID string     `bson:"_id"`
+	Version int64      `bson:"version"`
+	Order   orderModel `bson:"order"`
+}
+
+func NewOrderRepositoryEventSourced(db *mongo.Client) *OrderRepositoryEventSourced {
+	return &OrderRepositoryEventSourced{
+		SnapshotEvery: viper.GetInt64("order.event-store.snapshot-every"),
+		db:            db,
+		database:      dbName,
+		view:          &OrderRepositoryMongo{db: db, database: dbName, coll: viewCollName},
+	}
+}
+
+// impl domain.Repository
+func (r *OrderRepositoryEventSourced) Create(ctx context.Context, order *domain.Order) (created *domain.Order, err error) {
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.Create", logrus.Fields{"order": order})
+	defer func() { dlog(created, err) }()
+
+	o := *order
+	o.ID = primitive.NewObjectID().Hex()
+	if err = r.append(ctx, &domain.Snapshot{Order: &o}, []domain.Change{domain.CreatedChange(&o)}); err != nil {
+		return
+	}
+	created = order
+	created.ID = o.ID
+	return
+}
+
+func (r *OrderRepositoryEventSourced) Get(ctx context.Context, id, customerID string) (got *domain.Order, err error) {
+	fs := logrus.Fields{
+		"order_id":    id,
+		"customer_id": customerID,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.Get", fs)
+	defer func() { dlog(got, err) }()
+
+	s, err := r.load(ctx, id, customerID)
+	if err != nil {
+		return
+	}
+	got = s.Order
+	return
+}
+
+func (r *OrderRepositoryEventSourced) Update(
+	ctx context.Context, order *domain.Order,
+	updateFn func(context.Context, *domain.Order) (*domain.Order, error),
+) (err error) {
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.Update", logrus.Fields{"order": order})
+	defer func() { dlog(nil, err) }()
+
+	if order == nil {
+		panic("nil order")
+	}
+	err = r.inTransaction(ctx, func(ctx context.Context) error {
+		s, err := r.load(ctx, order.ID, order.CustomerID)
+		if err != nil {
+			return err
+		}
+		updated, err := updateFn(ctx, order)
+		if err != nil {
+			return err
+		}
+		if err = s.Order.Apply(updated); err != nil {
+			return err
+		}
+		return r.append(ctx, s, s.Order.Changes())
+	})
+	// 并发的更新写了同一个版本
+	return conflictOr(err, order.ID)
+}
+
+func (r *OrderRepositoryEventSourced) FindUnpaid(ctx context.Context, createdBefore time.Time, limit int) ([]*domain.Order, error) {
+	return r.view.FindUnpaid(ctx, createdBefore, limit)
+}
+
+func (r *OrderRepositoryEventSourced) List(ctx context.Context, filter domain.ListFilter) (*domain.OrderPage, error) {
+	return r.view.List(ctx, filter)
+}
+
+func (r *OrderRepositoryEventSourced) History(ctx context.Context, id, customerID string) (entries []*domain.HistoryEntry, err error) {
+	fs := logrus.Fields{
+		"order_id":    id,
+		"customer_id": customerID,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.History", fs)
+	defer func() { dlog(len(entries), err) }()
+
+	events, err := r.events(ctx, id, 0)
+	if err != nil {
+		return
+	}
+	if len(events) == 0 || events[0].CustomerID != customerID {
+		return nil, domain.NotFoundError{OrderID: id}
+	}
+	for _, e := range events {
+		entries = append(entries, &e.HistoryEntry)
+	}
+	return
+}
+
+// impl domain.EventStream
+func (r *OrderRepositoryEventSourced) ReadEvents(ctx context.Context, after string, limit int) (events []*domain.Event, err error) {
+	fs := logrus.Fields{
+		"after": after,
+		"limit": limit,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.ReadEvents", fs)
+	defer func() { dlog(len(events), err) }()
+
+	var afterSeq int64
+	if after != "" {
+		var perr error
+		if afterSeq, perr = strconv.ParseInt(after, 10, 64); perr != nil || afterSeq < 0 {
+			return nil, domain.InvalidCursorError{Cursor: after}
+		}
+	}
+	return r.find(
+		ctx,
+		bson.M{"seq": bson.M{"$gt": afterSeq}},
+		options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+// EnsureIndexes creates the indexes the event store and the read model rely on, it is safe to call on every start.
+func (r *OrderRepositoryEventSourced) EnsureIndexes(ctx context.Context) (err error) {
+	var name string
+	dlog := logging.MongoDB(ctx, "OrderRepositoryEventSourced.EnsureIndexes", nil)
+	defer func() { dlog(name, err) }()
+
+	name, err = r.eventsCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetName("order_id_1_version_1").SetUnique(true),
+	})
+	if err != nil {
+		return
+	}
+	name, err = r.eventsCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "seq", Value: 1}},
+		Options: options.Index().SetName("seq_1"),
+	})
+	if err != nil {
+		return
+	}
+	name, err = r.view.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "customer_id", Value: 1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("customer_id_1__id_-1"),
+	})
+	return
+}
+
+// load 取快照和之后的事件重放, 订单不存在或不属于 customerID 时返回 NotFoundError
+func (r *OrderRepositoryEventSourced) load(ctx context.Context, id, customerID string) (*domain.Snapshot, error) {
+	var from *domain.Snapshot
+	read := &snapshotModel{}
+	err := r.snapshotsCollection().FindOne(ctx, bson.M{"_id": id}).Decode(read)
+	switch {
+	case err == nil:
+		from = &domain.Snapshot{Order: r.view.unmarshal(&read.Order), Version: read.Version}
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, err
+	}
+
+	var after int64
+	if from != nil {
+		after = from.Version
+	}
+	events, err := r.events(ctx, id, after)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil && len(events) == 0 {
+		return nil, domain.NotFoundError{OrderID: id}
+	}
+	entries := make([]*domain.HistoryEntry, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, &e.HistoryEntry)
+	}
+	s, err := domain.Replay(from, entries)
+	if err != nil {
+		return nil, err
+	}
+	if s.Order.CustomerID != customerID {
+		return nil, domain.NotFoundError{OrderID: id}
+	}
+	return s, nil
+}
+
+// append 保存 s 之后的 changes, 更新读模型, 跨过 SnapshotEvery 的整数倍时保存快照
+func (r *OrderRepositoryEventSourced) append(ctx context.Context, s *domain.Snapshot, changes []domain.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	entries := domain.NewHistory(s.Order, s.Version, changes, actor.From(ctx), traceID(ctx))
+	models := make([]*eventModel, 0, len(entries))
+	for _, e := range entries {
+		models = append(models, &eventModel{
+			MongoID:    primitive.NewObjectID(),
+			OrderID:    e.OrderID,
+			CustomerID: e.CustomerID,
+			Version:    e.Version,
+			Name:       domain.EventName(e),
+			Type:       e.Type,
+			Old:        e.Old,
+			New:        e.New,
+			Items:      e.Items,
+			At:         e.At,
+			Actor:      e.Actor,
+			TraceID:    e.TraceID,
+		})
+	}
+	version := entries[len(entries)-1].Version
+	view := r.view.marshalToModel(s.Order)
+	view.MongoID, _ = primitive.ObjectIDFromHex(s.Order.ID)
+	view.ID = s.Order.ID
+
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		last, err := r.nextSeq(ctx, int64(len(models)))
+		if err != nil {
+			return err
+		}
+		docs := make([]any, 0, len(models))
+		for i, m := range models {
+			m.Seq = last - int64(len(models)-1-i)
+			docs = append(docs, m)
+		}
+		if _, err := r.eventsCollection().InsertMany(ctx, docs); err != nil {
+			return err
+		}
+		if _, err := r.view.collection().ReplaceOne(ctx, bson.M{"_id": view.MongoID}, view, options.Replace().SetUpsert(true)); err != nil {
+			return err
+		}
+		if r.SnapshotEvery <= 0 || version/r.SnapshotEvery == s.Version/r.SnapshotEvery {
+			return nil
+		}
+		_, err = r.snapshotsCollection().ReplaceOne(
+			ctx,
+			bson.M{"_id": s.Order.ID},
+			snapshotModel{OrderID: s.Order.ID, Version: version, Order: view},
+			options.Replace().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+// nextSeq 在 ctx 的事务中把计数器加 n, 返回加后的值, 即 n 个事件中最后一个的序号
+func (r *OrderRepositoryEventSourced) nextSeq(ctx context.Context, n int64) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.countersCollection().FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": eventSeqID},
+		bson.M{"$inc": bson.M{"seq": n}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	return counter.Seq, err
+}
+
+// events returns the events of the order after version, oldest first
+func (r *OrderRepositoryEventSourced) events(ctx context.Context, id string, after int64) ([]*domain.Event, error) {
+	return r.find(
+		ctx,
+		bson.M{"order_id": id, "version": bson.M{"$gt": after}},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}),
+	)
+}
+
+func (r *OrderRepositoryEventSourced) find(ctx context.Context, cond bson.M, opts *options.FindOptions) ([]*domain.Event, error) {
+	cur, err := r.eventsCollection().Find(ctx, cond, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var reads []*eventModel
+	if err = cur.All(ctx, &reads); err != nil {
+		return nil, err
+	}
+	events := make([]*domain.Event, 0, len(reads))
+	for _, read := range reads {
+		events = append(events, &domain.Event{
+			HistoryEntry: domain.HistoryEntry{
+				OrderID:    read.OrderID,
+				CustomerID: read.CustomerID,
+				Version:    read.Version,
+				Type:       read.Type,
+				Old:        read.Old,
+				New:        read.New,
+				Items:      read.Items,
+				At:         read.At,
+				Actor:      read.Actor,
+				TraceID:    read.TraceID,
+			},
+			Name:     read.Name,
+			Position: strconv.FormatInt(read.Seq, 10),
+		})
+	}
+	return events, nil
+}
+
+// 已经在 TransactorMongo.InTransaction 中时直接加入外层事务
+func (r *OrderRepositoryEventSourced) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return NewTransactorMongo(r.db).InTransaction(ctx, fn)
+}
+
+func (r *OrderRepositoryEventSourced) countersCollection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(countersCollName)
+}
+
+func (r *OrderRepositoryEventSourced) eventsCollection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(eventsCollName)
+}
+
+func (r *OrderRepositoryEventSourced) snapshotsCollection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(snapshotsCollName)
+}
@@ -2,44 +2,56 @@ package adapters
 
 import (
 	"context"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/peiyouyao/gorder/common/actor"
+	"github.com/peiyouyao/gorder/common/constants"
 	domain "github.com/peiyouyao/gorder/order/domain/order"
 	"github.com/sirupsen/logrus"
 )
 
 type OrderRepositoryInmem struct {
-	lock  *sync.RWMutex
-	store []*domain.Order
+	lock    *sync.RWMutex
+	store   []*domain.Order
+	history map[string][]*domain.HistoryEntry
+	seq     int64
 }
 
 func NewOrderRepositoryInmem() *OrderRepositoryInmem {
 	s := make([]*domain.Order, 0)
 	return &OrderRepositoryInmem{
-		lock:  &sync.RWMutex{},
-		store: s,
+		lock:    &sync.RWMutex{},
+		store:   s,
+		history: make(map[string][]*domain.HistoryEntry),
 	}
 }
 
 // impl domain.Repository
-func (m *OrderRepositoryInmem) Create(_ context.Context, order *domain.Order) (*domain.Order, error) {
+func (m *OrderRepositoryInmem) Create(ctx context.Context, order *domain.Order) (*domain.Order, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	m.seq++
 	res := &domain.Order{
-		ID:          strconv.FormatInt(time.Now().Unix(), 10),
+		ID:          strconv.FormatInt(time.Now().Unix(), 10) + "-" + strconv.FormatInt(m.seq, 10),
 		CustomerID:  order.CustomerID,
 		Status:      order.Status,
 		PaymentLink: order.PaymentLink,
 		Items:       order.Items,
+		CreatedAt:   order.CreatedAt,
+	}
+	if res.CreatedAt.IsZero() {
+		res.CreatedAt = time.Now()
 	}
 	m.store = append(m.store, res)
+	m.appendHistory(ctx, res, []domain.Change{domain.CreatedChange(res)})
 	logrus.WithFields(logrus.Fields{
 		"input_order":        order,
 		"store_after_create": m.store,
 	}).Debug("OrderRepositoryInmem.Create")
-	return res, nil
+	return copyOrder(res), nil
 }
 
 func (m *OrderRepositoryInmem) Get(ctx context.Context, id, customerID string) (*domain.Order, error) {
@@ -48,7 +60,7 @@ func (m *OrderRepositoryInmem) Get(ctx context.Context, id, customerID string) (
 	for _, o := range m.store {
 		if o.ID == id && o.CustomerID == customerID {
 			logrus.Debugf("OrderRepositoryInmem.Get id=%s customerID=%s res=%+v", id, customerID, *o)
-			return o, nil
+			return copyOrder(o), nil
 		}
 	}
 	return nil, domain.NotFoundError{OrderID: id}
@@ -68,9 +80,102 @@ func (m *OrderRepositoryInmem) Update(
 			if err != nil {
 				return err
 			}
-			m.store[i] = updatedOrder
+			stored := copyOrder(o)
+			if err = stored.Apply(updatedOrder); err != nil {
+				return err
+			}
+			m.appendHistory(ctx, stored, stored.Changes())
+			m.store[i] = copyOrder(stored)
 			return nil
 		}
 	}
 	return domain.NotFoundError{OrderID: order.ID}
 }
+
+func (m *OrderRepositoryInmem) FindUnpaid(_ context.Context, createdBefore time.Time, limit int) ([]*domain.Order, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var res []*domain.Order
+	for _, o := range m.store {
+		if len(res) >= limit {
+			break
+		}
+		if o.Status != constants.OrderStatusPending && o.Status != constants.OrderStatusWaitingForPayment {
+			continue
+		}
+		if o.CreatedAt.Before(createdBefore) {
+			res = append(res, copyOrder(o))
+		}
+	}
+	return res, nil
+}
+
+func (m *OrderRepositoryInmem) List(_ context.Context, filter domain.ListFilter) (*domain.OrderPage, error) {
+	if filter.Limit <= 0 {
+		return nil, domain.InvalidLimitError{Limit: filter.Limit}
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	page := &domain.OrderPage{}
+	passedCursor := filter.Cursor == ""
+	// store is in creation order, walk it backwards for newest first
+	for i := len(m.store) - 1; i >= 0; i-- {
+		o := m.store[i]
+		if !passedCursor {
+			passedCursor = o.ID == filter.Cursor
+			continue
+		}
+		if o.CustomerID != filter.CustomerID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, o.Status) {
+			continue
+		}
+		if !filter.CreatedAfter.IsZero() && o.CreatedAt.Before(filter.CreatedAfter) {
+			continue
+		}
+		if !filter.CreatedBefore.IsZero() && !o.CreatedAt.Before(filter.CreatedBefore) {
+			continue
+		}
+		if len(page.Orders) == filter.Limit {
+			page.NextCursor = page.Orders[len(page.Orders)-1].ID
+			break
+		}
+		page.Orders = append(page.Orders, copyOrder(o))
+	}
+	if !passedCursor {
+		return nil, domain.InvalidCursorError{Cursor: filter.Cursor}
+	}
+	return page, nil
+}
+
+func (m *OrderRepositoryInmem) History(ctx context.Context, id, customerID string) ([]*domain.HistoryEntry, error) {
+	if _, err := m.Get(ctx, id, customerID); err != nil {
+		return nil, err
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return slices.Clone(m.history[id]), nil
+}
+
+// 调用方持有写锁
+func (m *OrderRepositoryInmem) appendHistory(ctx context.Context, o *domain.Order, changes []domain.Change) {
+	last := int64(len(m.history[o.ID]))
+	m.history[o.ID] = append(m.history[o.ID], domain.NewHistory(o, last, changes, actor.From(ctx), traceID(ctx))...)
+}
+
+// callers must not be able to change the store through a returned order, like with a real database
+// and the copy starts without changes
+func copyOrder(o *domain.Order) *domain.Order {
+	return &domain.Order{
+		ID:               o.ID,
+		CustomerID:       o.CustomerID,
+		Status:           o.Status,
+		PaymentLink:      o.PaymentLink,
+		Items:            slices.Clone(o.Items),
+		CreatedAt:        o.CreatedAt,
+		PaidAt:           o.PaidAt,
+		EstimatedReadyAt: o.EstimatedReadyAt,
+		ReadyAt:          o.ReadyAt,
+	}
+}
@@ -1,23 +1,28 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"time"
 
-	"maps"
-
 	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/logging"
 	domain "github.com/peiyouyao/gorder/order/domain/order"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type OrderRepositoryMongo struct {
-	db *mongo.Client
+	db       *mongo.Client
+	database string
+	coll     string
 }
 
 type orderModel struct {
@@ -27,6 +32,11 @@ type orderModel struct {
 	Status      string             `bson:"status"`
 	PaymentLink string             `bson:"payment_link"`
 	Items       []*entity.Item     `bson:"items"`
+	// 旧文档没有 created_at, 用 _id 中的时间
+	CreatedAt        time.Time  `bson:"created_at"`
+	PaidAt           *time.Time `bson:"paid_at,omitempty"`
+	EstimatedReadyAt *time.Time `bson:"estimated_ready_at,omitempty"`
+	ReadyAt          *time.Time `bson:"ready_at,omitempty"`
 }
 
 var (
@@ -35,15 +45,15 @@ var (
 )
 
 func NewOrderRepositoryMongo(db *mongo.Client) *OrderRepositoryMongo {
-	return &OrderRepositoryMongo{db: db}
+	return &OrderRepositoryMongo{db: db, database: dbName, coll: collName}
 }
 
 func (r *OrderRepositoryMongo) Create(ctx context.Context, order *domain.Order) (created *domain.Order, err error) {
 	fs := logrus.Fields{
 		"order": order,
 	}
-	dlog := logMongoDB(ctx, "OrderRepositoryMongo.Create", fs)
-	defer dlog(created, err)
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.Create", fs)
+	defer func() { dlog(created, err) }()
 
 	write := r.marshalToModel(order)
 	res, err := r.collection().InsertOne(ctx, write)
@@ -52,21 +62,30 @@ func (r *OrderRepositoryMongo) Create(ctx context.Context, order *domain.Order)
 	}
 	created = order
 	created.ID = res.InsertedID.(primitive.ObjectID).Hex()
+	created.CreatedAt = write.CreatedAt
+	err = r.appendHistory(ctx, created, []domain.Change{domain.CreatedChange(created)})
 	return
 }
 
 func (r *OrderRepositoryMongo) Get(ctx context.Context, id, customerID string) (got *domain.Order, err error) {
 	fs := logrus.Fields{
+		"order_id":    id,
 		"customer_id": customerID,
 	}
-	dlog := logMongoDB(ctx, "OrderRepositoryMongo.Get", fs)
-	defer dlog(got, err)
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.Get", fs)
+	defer func() { dlog(got, err) }()
 
-	read := &orderModel{}
-	mongoID, _ := primitive.ObjectIDFromHex(id)
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.NotFoundError{OrderID: id}
+	}
 
-	cond := bson.M{"_id": mongoID}
+	read := &orderModel{}
+	cond := bson.M{"_id": mongoID, "customer_id": customerID}
 	if err = r.collection().FindOne(ctx, cond).Decode(read); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = domain.NotFoundError{OrderID: id}
+		}
 		return
 	}
 	got = r.unmarshal(read)
@@ -81,95 +100,244 @@ func (r *OrderRepositoryMongo) Update(
 	fs := logrus.Fields{
 		"order": order,
 	}
-	dlog := logMongoDB(ctx, "OrderRepositoryMongo.Update", fs)
-	defer dlog(updateRes, err)
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.Update", fs)
+	defer func() { dlog(updateRes, err) }()
 
 	if order == nil {
 		panic("nil order")
 	}
 
-	session, err := r.db.StartSession()
+	err = r.inTransaction(ctx, func(ctx context.Context) error {
+		oldOrder, err := r.Get(ctx, order.ID, order.CustomerID)
+		if err != nil {
+			return err
+		}
+		storedStatus := oldOrder.Status
+
+		updated, err := updateFn(ctx, order)
+		if err != nil {
+			return err
+		}
+		if err = oldOrder.Apply(updated); err != nil {
+			return err
+		}
+
+		mongoID, _ := primitive.ObjectIDFromHex(oldOrder.ID)
+
+		// 只在状态没有被并发修改时更新
+		updateRes, err = r.collection().UpdateOne(
+			ctx,
+			bson.M{"_id": mongoID, "customer_id": oldOrder.CustomerID, "status": storedStatus}, // can't add condition: `"id": mongoID"`, because id need mongoID.Hex()
+			bson.M{"$set": bson.M{
+				"status":             oldOrder.Status,
+				"payment_link":       oldOrder.PaymentLink,
+				"paid_at":            timePtr(oldOrder.PaidAt),
+				"estimated_ready_at": timePtr(oldOrder.EstimatedReadyAt),
+				"ready_at":           timePtr(oldOrder.ReadyAt),
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if updateRes.MatchedCount == 0 {
+			return domain.ConflictError{OrderID: order.ID}
+		}
+		return r.appendHistory(ctx, oldOrder, oldOrder.Changes())
+	})
+	err = conflictOr(err, order.ID)
+	return
+}
+
+// mongo 的 WriteConflict 错误码, 和 WithTransaction 重试用的错误标签
+const (
+	writeConflictCode = 112
+	transientTxnLabel = "TransientTransactionError"
+)
+
+// conflictOr 把并发写入的错误 (唯一索引冲突, 重试后仍然失败的写冲突) 转成 domain.ConflictError
+func conflictOr(err error, orderID string) error {
+	var se mongo.ServerError
+	if mongo.IsDuplicateKeyError(err) || errors.As(err, &se) && (se.HasErrorCode(writeConflictCode) || se.HasErrorLabel(transientTxnLabel)) {
+		return domain.ConflictError{OrderID: orderID}
+	}
+	return err
+}
+
+// 已经在 TransactorMongo.InTransaction 中时直接加入外层事务
+func (r *OrderRepositoryMongo) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return NewTransactorMongo(r.db).InTransaction(ctx, fn)
+}
+
+// stale orders are found by the creation time in the ObjectID, not created_at, which older documents lack
+func (r *OrderRepositoryMongo) FindUnpaid(ctx context.Context, createdBefore time.Time, limit int) (found []*domain.Order, err error) {
+	fs := logrus.Fields{
+		"created_before": createdBefore,
+		"limit":          limit,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.FindUnpaid", fs)
+	defer func() { dlog(len(found), err) }()
+
+	cur, err := r.collection().Find(
+		ctx,
+		bson.M{
+			"_id":    bson.M{"$lt": primitive.NewObjectIDFromTimestamp(createdBefore)},
+			"status": bson.M{"$in": []string{constants.OrderStatusPending, constants.OrderStatusWaitingForPayment}},
+		},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
 	if err != nil {
 		return
 	}
-	defer session.EndSession(ctx)
+	defer cur.Close(ctx)
 
-	if err = session.StartTransaction(); err != nil {
-		return err
+	var reads []*orderModel
+	if err = cur.All(ctx, &reads); err != nil {
+		return
 	}
-	defer func() {
-		if err == nil {
-			_ = session.CommitTransaction(ctx)
-		} else {
-			_ = session.AbortTransaction(ctx)
+	for _, read := range reads {
+		found = append(found, r.unmarshal(read))
+	}
+	return
+}
+
+// cursor is the hex ObjectID of the last order on the previous page
+func (r *OrderRepositoryMongo) List(ctx context.Context, filter domain.ListFilter) (page *domain.OrderPage, err error) {
+	fs := logrus.Fields{
+		"filter": filter,
+	}
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.List", fs)
+	defer func() { dlog(page, err) }()
+
+	if filter.Limit <= 0 {
+		return nil, domain.InvalidLimitError{Limit: filter.Limit}
+	}
+	idRange := bson.M{}
+	if !filter.CreatedAfter.IsZero() {
+		idRange["$gte"] = primitive.NewObjectIDFromTimestamp(filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		idRange["$lt"] = primitive.NewObjectIDFromTimestamp(filter.CreatedBefore)
+	}
+	if filter.Cursor != "" {
+		cursorID, perr := primitive.ObjectIDFromHex(filter.Cursor)
+		if perr != nil {
+			return nil, domain.InvalidCursorError{Cursor: filter.Cursor}
 		}
-	}()
+		// with both cursor and created_before, the smaller bound wins
+		if before, ok := idRange["$lt"].(primitive.ObjectID); !ok || bytes.Compare(cursorID[:], before[:]) < 0 {
+			idRange["$lt"] = cursorID
+		}
+	}
+
+	cond := bson.M{"customer_id": filter.CustomerID}
+	if len(idRange) > 0 {
+		cond["_id"] = idRange
+	}
+	if len(filter.Statuses) > 0 {
+		cond["status"] = bson.M{"$in": filter.Statuses}
+	}
 
-	oldOrder, err := r.Get(ctx, order.ID, order.CustomerID)
+	// one extra doc tells whether there is a next page
+	cur, err := r.collection().Find(
+		ctx,
+		cond,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(filter.Limit+1)),
+	)
 	if err != nil {
 		return
 	}
+	defer cur.Close(ctx)
 
-	updated, err := updateFn(ctx, order)
-	if err != nil {
+	var reads []*orderModel
+	if err = cur.All(ctx, &reads); err != nil {
 		return
 	}
+	page = &domain.OrderPage{}
+	if len(reads) > filter.Limit {
+		reads = reads[:filter.Limit]
+		page.NextCursor = reads[len(reads)-1].MongoID.Hex()
+	}
+	for _, read := range reads {
+		page.Orders = append(page.Orders, r.unmarshal(read))
+	}
+	return
+}
 
-	mongoID, _ := primitive.ObjectIDFromHex(oldOrder.ID)
+// EnsureIndexes creates the indexes List and the history rely on, it is safe to call on every start.
+func (r *OrderRepositoryMongo) EnsureIndexes(ctx context.Context) (err error) {
+	var name string
+	dlog := logging.MongoDB(ctx, "OrderRepositoryMongo.EnsureIndexes", nil)
+	defer func() { dlog(name, err) }()
 
-	updateRes, err = r.collection().UpdateOne(
-		ctx,
-		bson.M{"_id": mongoID, "customer_id": oldOrder.CustomerID}, // can't add condition: `"id": mongoID"`, because id need mongoID.Hex()
-		bson.M{"$set": bson.M{
-			"status":       updated.Status,
-			"payment_link": updated.PaymentLink,
-		}},
-	)
+	name, err = r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "customer_id", Value: 1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("customer_id_1__id_-1"),
+	})
+	if err != nil {
+		return
+	}
+	name, err = r.historyCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetName("order_id_1_version_1").SetUnique(true),
+	})
 	return
 }
 
 func (r *OrderRepositoryMongo) collection() *mongo.Collection {
-	return r.db.Database(dbName).Collection(collName)
+	return r.db.Database(r.database).Collection(r.coll)
 }
 
 func (r *OrderRepositoryMongo) marshalToModel(order *domain.Order) orderModel {
 	mongoID := primitive.NewObjectID()
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = mongoID.Timestamp()
+	}
 	return orderModel{
-		MongoID:     mongoID,
-		ID:          mongoID.Hex(),
-		CustomerID:  order.CustomerID,
-		Status:      order.Status,
-		PaymentLink: order.PaymentLink,
-		Items:       order.Items,
+		MongoID:          mongoID,
+		ID:               mongoID.Hex(),
+		CustomerID:       order.CustomerID,
+		Status:           order.Status,
+		PaymentLink:      order.PaymentLink,
+		Items:            order.Items,
+		CreatedAt:        createdAt,
+		PaidAt:           timePtr(order.PaidAt),
+		EstimatedReadyAt: timePtr(order.EstimatedReadyAt),
+		ReadyAt:          timePtr(order.ReadyAt),
 	}
 }
 
 func (r *OrderRepositoryMongo) unmarshal(read *orderModel) *domain.Order {
-	return &domain.Order{
+	o := &domain.Order{
 		ID:          read.MongoID.Hex(),
 		CustomerID:  read.CustomerID,
 		Status:      read.Status,
 		PaymentLink: read.PaymentLink,
 		Items:       read.Items,
+		CreatedAt:   read.CreatedAt,
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = read.MongoID.Timestamp()
+	}
+	if read.PaidAt != nil {
+		o.PaidAt = *read.PaidAt
 	}
+	if read.EstimatedReadyAt != nil {
+		o.EstimatedReadyAt = *read.EstimatedReadyAt
+	}
+	if read.ReadyAt != nil {
+		o.ReadyAt = *read.ReadyAt
+	}
+	return o
 }
 
-func logMongoDB(ctx context.Context, method string, fields logrus.Fields) (dlog func(resp any, err error)) {
-	start := time.Now()
-
-	// 拷贝字段，避免外部修改污染
-	fs := make(logrus.Fields, len(fields))
-	maps.Copy(fs, fields)
-
-	dlog = func(res any, err error) {
-		fs["mongo_res"] = res
-		fs["mongo_time_cost"] = time.Since(start)
-		if err == nil {
-			logrus.WithContext(ctx).WithFields(fs).Infof("%s ok", method)
-		} else {
-			fs["mongo_err"] = err.Error()
-			logrus.WithContext(ctx).WithFields(fs).Errorf("%s fail", method)
-		}
+// nil for the zero time, so that the field is left out
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
-	return
+	return &t
 }
@@ -0,0 +1,390 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/actor"
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// every domain.Repository adapter has to pass the same contract
+func TestOrderRepositoryInmem(t *testing.T) {
+	t.Parallel()
+	testRepository(t, NewOrderRepositoryInmem())
+}
+
+func TestOrderRepositoryMongo(t *testing.T) {
+	t.Parallel()
+	c, database := setupTestMongo(t)
+	repo := NewOrderRepositoryMongo(c)
+	repo.database = database
+	testRepository(t, repo)
+}
+
+func TestOrderRepositoryEventSourced(t *testing.T) {
+	t.Parallel()
+	c, database := setupTestMongo(t)
+	repo := NewOrderRepositoryEventSourced(c)
+	repo.database, repo.view.database = database, database
+	repo.SnapshotEvery = 2
+	testRepository(t, repo)
+
+	t.Run("read_events", func(t *testing.T) {
+		ctx := context.Background()
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		updated := *created
+		updated.PaymentLink = "https://pay.example/1"
+		updated.Status = constants.OrderStatusWaitingForPayment
+		require.NoError(t, repo.Update(ctx, created, func(context.Context, *domain.Order) (*domain.Order, error) {
+			return &updated, nil
+		}))
+
+		var (
+			names []string
+			last  int64
+		)
+		after := ""
+		for {
+			events, err := repo.ReadEvents(ctx, after, 3)
+			require.NoError(t, err)
+			if len(events) == 0 {
+				break
+			}
+			for _, e := range events {
+				if e.OrderID == created.ID {
+					names = append(names, e.Name)
+				}
+				// 位置是连续递增的序号
+				seq, err := strconv.ParseInt(e.Position, 10, 64)
+				require.NoError(t, err)
+				assert.Greater(t, seq, last)
+				last = seq
+			}
+			after = events[len(events)-1].Position
+		}
+		assert.Equal(t, []string{domain.EventOrderCreated, domain.EventOrderAwaitingPayment, domain.EventPaymentLinkAttached}, names)
+
+		_, err = repo.ReadEvents(ctx, "not-a-position", 3)
+		assert.ErrorAs(t, err, &domain.InvalidCursorError{})
+
+		// version 3 is past the snapshot at 2
+		got, err := repo.Get(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		assert.Equal(t, constants.OrderStatusWaitingForPayment, got.Status)
+		assert.Equal(t, "https://pay.example/1", got.PaymentLink)
+	})
+}
+
+func testRepository(t *testing.T, repo domain.Repository) {
+	ctx := context.Background()
+
+	t.Run("create_then_get", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+
+		got, err := repo.Get(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+		assert.Equal(t, customerID, got.CustomerID)
+		assert.Equal(t, constants.OrderStatusPending, got.Status)
+		assert.Len(t, got.Items, 1)
+	})
+
+	t.Run("get_other_customer", func(t *testing.T) {
+		created, err := repo.Create(ctx, testOrder(t, testCustomerID(t)))
+		require.NoError(t, err)
+
+		_, err = repo.Get(ctx, created.ID, "someone-else")
+		assert.ErrorAs(t, err, &domain.NotFoundError{})
+	})
+
+	t.Run("get_missing", func(t *testing.T) {
+		_, err := repo.Get(ctx, primitive.NewObjectID().Hex(), testCustomerID(t))
+		assert.ErrorAs(t, err, &domain.NotFoundError{})
+	})
+
+	t.Run("get_malformed_id", func(t *testing.T) {
+		_, err := repo.Get(ctx, "not-an-order-id", testCustomerID(t))
+		assert.ErrorAs(t, err, &domain.NotFoundError{})
+	})
+
+	t.Run("update", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		err = repo.Update(ctx, created, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+			o.Status = constants.OrderStatusWaitingForPayment
+			o.PaymentLink = "https://pay.example/link"
+			return o, nil
+		})
+		require.NoError(t, err)
+
+		got, err := repo.Get(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		assert.Equal(t, constants.OrderStatusWaitingForPayment, got.Status)
+		assert.Equal(t, "https://pay.example/link", got.PaymentLink)
+	})
+
+	t.Run("update_times", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		eta := time.Now().Add(10 * time.Minute)
+		for _, status := range []string{constants.OrderStatusWaitingForPayment, constants.OrderStatusPaid} {
+			err = repo.Update(ctx, created, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+				o.Status = status
+				o.EstimatedReadyAt = eta
+				return o, nil
+			})
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), got.CreatedAt, time.Minute)
+		assert.WithinDuration(t, time.Now(), got.PaidAt, time.Minute)
+		// 只有 paid 之后的估计才保存
+		assert.WithinDuration(t, eta, got.EstimatedReadyAt, time.Millisecond)
+		assert.True(t, got.ReadyAt.IsZero())
+	})
+
+	t.Run("update_invalid_transition", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		err = repo.Update(ctx, created, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+			o.Status = constants.OrderStatusReady
+			return o, nil
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		for _, status := range []string{
+			constants.OrderStatusWaitingForPayment,
+			constants.OrderStatusPaid,
+			constants.OrderStatusPaid, // redelivered, not a change
+		} {
+			require.NoError(t, repo.Update(actor.With(ctx, "payment"), created, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+				o.Status = status
+				return o, nil
+			}))
+		}
+
+		history, err := repo.History(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, e := range history {
+			assert.Equal(t, int64(i+1), e.Version)
+		}
+		assert.Equal(t, domain.ChangeCreated, history[0].Type)
+		assert.Len(t, history[0].Items, 1)
+		assert.Equal(t, domain.ChangeStatus, history[2].Type)
+		assert.Equal(t, constants.OrderStatusWaitingForPayment, history[2].Old)
+		assert.Equal(t, constants.OrderStatusPaid, history[2].New)
+		assert.Equal(t, "payment", history[2].Actor)
+
+		_, err = repo.History(ctx, created.ID, "someone-else")
+		assert.ErrorAs(t, err, &domain.NotFoundError{})
+	})
+
+	t.Run("update_concurrent", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		const n = 8
+		errs := make(chan error, n)
+		for range n {
+			go func() {
+				errs <- repo.Update(ctx, created, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+					updated := *o
+					updated.Status = constants.OrderStatusWaitingForPayment
+					return &updated, nil
+				})
+			}()
+		}
+		// 输掉的更新返回 ConflictError, 不会写出重复的版本
+		for range n {
+			if err := <-errs; err != nil {
+				assert.ErrorAs(t, err, &domain.ConflictError{})
+			}
+		}
+		history, err := repo.History(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, int64(2), history[1].Version)
+		assert.Equal(t, constants.OrderStatusWaitingForPayment, history[1].New)
+	})
+
+	t.Run("update_other_customer", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		stranger := *created
+		stranger.CustomerID = "someone-else"
+		stranger.Status = constants.OrderStatusPaid
+		err = repo.Update(ctx, &stranger, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+			return o, nil
+		})
+		assert.ErrorAs(t, err, &domain.NotFoundError{})
+
+		got, err := repo.Get(ctx, created.ID, customerID)
+		require.NoError(t, err)
+		assert.Equal(t, constants.OrderStatusPending, got.Status)
+	})
+
+	t.Run("list_pages", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		var want []string
+		for range 5 {
+			created, err := repo.Create(ctx, testOrder(t, customerID))
+			require.NoError(t, err)
+			want = append([]string{created.ID}, want...) // newest first
+		}
+		_, err := repo.Create(ctx, testOrder(t, "someone-else"))
+		require.NoError(t, err)
+
+		var (
+			got    []string
+			cursor string
+		)
+		for {
+			page, err := repo.List(ctx, domain.ListFilter{CustomerID: customerID, Cursor: cursor, Limit: 2})
+			require.NoError(t, err)
+			for _, o := range page.Orders {
+				got = append(got, o.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("list_status", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		waiting, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+		require.NoError(t, repo.Update(ctx, waiting, func(_ context.Context, o *domain.Order) (*domain.Order, error) {
+			o.Status = constants.OrderStatusWaitingForPayment
+			return o, nil
+		}))
+
+		page, err := repo.List(ctx, domain.ListFilter{
+			CustomerID: customerID,
+			Statuses:   []string{constants.OrderStatusWaitingForPayment},
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Orders, 1)
+		assert.Equal(t, waiting.ID, page.Orders[0].ID)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("list_created_range", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		_, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		page, err := repo.List(ctx, domain.ListFilter{
+			CustomerID:   customerID,
+			CreatedAfter: time.Now().Add(time.Hour),
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, page.Orders)
+
+		page, err = repo.List(ctx, domain.ListFilter{
+			CustomerID:    customerID,
+			CreatedAfter:  time.Now().Add(-time.Hour),
+			CreatedBefore: time.Now().Add(time.Hour),
+			Limit:         10,
+		})
+		require.NoError(t, err)
+		assert.Len(t, page.Orders, 1)
+	})
+
+	t.Run("list_invalid_cursor", func(t *testing.T) {
+		_, err := repo.List(ctx, domain.ListFilter{CustomerID: testCustomerID(t), Cursor: "not-a-cursor", Limit: 10})
+		assert.ErrorAs(t, err, &domain.InvalidCursorError{})
+	})
+
+	t.Run("find_unpaid", func(t *testing.T) {
+		customerID := testCustomerID(t)
+		created, err := repo.Create(ctx, testOrder(t, customerID))
+		require.NoError(t, err)
+
+		found, err := repo.FindUnpaid(ctx, time.Now().Add(time.Hour), 1000)
+		require.NoError(t, err)
+		var ids []string
+		for _, o := range found {
+			ids = append(ids, o.ID)
+		}
+		assert.Contains(t, ids, created.ID)
+	})
+}
+
+func testOrder(t *testing.T, customerID string) *domain.Order {
+	o, err := domain.NewPendingOrder(customerID, []*entity.Item{
+		{ID: "test-item", Name: "test item", Quantity: 1, PriceID: "test-price"},
+	})
+	require.NoError(t, err)
+	return o
+}
+
+// unique per test and per run, the mongo collection is shared
+func testCustomerID(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+}
+
+// needs the order-mongo from docker-compose.yml, skipped when it is not running.
+// Every test gets its own database, so tests using it can run in parallel.
+func setupTestMongo(t *testing.T) (*mongo.Client, string) {
+	uri := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s",
+		viper.GetString("mongo.user"),
+		viper.GetString("mongo.password"),
+		viper.GetString("mongo.host"),
+		viper.GetString("mongo.port"),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	c, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetServerSelectionTimeout(2*time.Second))
+	require.NoError(t, err)
+	if err = c.Ping(ctx, readpref.Primary()); err != nil {
+		t.Skipf("mongo not available: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Disconnect(context.Background()) })
+
+	database := viper.GetString("mongo.db-name") + "_" + t.Name()
+	require.NoError(t, c.Database(database).Drop(ctx))
+	return c, database
+}
@@ -0,0 +1,49 @@
+package adapters
+
+import "sync"
+
+// OrderWatcherInmem only sees the updates made by this process,
+// a client watching through another order instance is woken up by its resync instead.
+type OrderWatcherInmem struct {
+	lock     sync.Mutex
+	watchers map[string]map[chan struct{}]struct{}
+}
+
+func NewOrderWatcherInmem() *OrderWatcherInmem {
+	return &OrderWatcherInmem{watchers: make(map[string]map[chan struct{}]struct{})}
+}
+
+// impl domain.Watcher
+func (w *OrderWatcherInmem) Notify(orderID string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for ch := range w.watchers[orderID] {
+		// 缓冲为 1, 已经有未读的通知时不用再发
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (w *OrderWatcherInmem) Watch(orderID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.watchers[orderID] == nil {
+		w.watchers[orderID] = make(map[chan struct{}]struct{})
+	}
+	w.watchers[orderID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.lock.Lock()
+			defer w.lock.Unlock()
+			delete(w.watchers[orderID], ch)
+			if len(w.watchers[orderID]) == 0 {
+				delete(w.watchers, orderID)
+			}
+		})
+	}
+}
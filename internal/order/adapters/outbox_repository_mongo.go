@@ -0,0 +1,192 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/logging"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	outboxStatusPending = "pending"
+	outboxStatusSent    = "sent"
+)
+
+var outboxCollName = viper.GetString("mongo.outbox-coll-name")
+
+// impl domain.Outbox
+type OutboxRepositoryMongo struct {
+	db       *mongo.Client
+	database string
+}
+
+type outboxModel struct {
+	MongoID       primitive.ObjectID `bson:"_id"`
+	Dest          string             `bson:"dest"`
+	Broadcast     bool               `bson:"broadcast"`
+	Body          []byte             `bson:"body"`
+	Headers       map[string]any     `bson:"headers"`
+	Status        string             `bson:"status"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty"`
+}
+
+func NewOutboxRepositoryMongo(db *mongo.Client) *OutboxRepositoryMongo {
+	return &OutboxRepositoryMongo{db: db, database: dbName}
+}
+
+// Add must be called with the ctx given by TransactorMongo.InTransaction to be atomic with the order write.
+func (r *OutboxRepositoryMongo) Add(ctx context.Context, msgs ...*domain.OutboxMessage) (err error) {
+	var res *mongo.InsertManyResult
+	dlog := logging.MongoDB(ctx, "OutboxRepositoryMongo.Add", logrus.Fields{"outbox_msgs": msgs})
+	defer func() { dlog(res, err) }()
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	docs := make([]any, 0, len(msgs))
+	for _, m := range msgs {
+		docs = append(docs, r.marshalToModel(m))
+	}
+	res, err = r.collection().InsertMany(ctx, docs)
+	return
+}
+
+func (r *OutboxRepositoryMongo) ClaimPending(ctx context.Context, limit int, lease time.Duration) (claimed []*domain.OutboxMessage, err error) {
+	for len(claimed) < limit {
+		now := time.Now()
+		read := &outboxModel{}
+		err = r.collection().FindOneAndUpdate(
+			ctx,
+			bson.M{"status": outboxStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "created_at", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(read)
+		if err == mongo.ErrNoDocuments {
+			return claimed, nil
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, r.unmarshal(read))
+	}
+	return claimed, nil
+}
+
+func (r *OutboxRepositoryMongo) MarkSent(ctx context.Context, id string) (err error) {
+	var res *mongo.UpdateResult
+	dlog := logging.MongoDB(ctx, "OutboxRepositoryMongo.MarkSent", logrus.Fields{"outbox_id": id})
+	defer func() { dlog(res, err) }()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return
+	}
+	res, err = r.collection().UpdateByID(ctx, mongoID, bson.M{"$set": bson.M{
+		"status":  outboxStatusSent,
+		"sent_at": time.Now(),
+	}})
+	return
+}
+
+func (r *OutboxRepositoryMongo) MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) (err error) {
+	var res *mongo.UpdateResult
+	dlog := logging.MongoDB(ctx, "OutboxRepositoryMongo.MarkFailed", logrus.Fields{"outbox_id": id, "retry_at": retryAt})
+	defer func() { dlog(res, err) }()
+
+	mongoID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return
+	}
+	res, err = r.collection().UpdateByID(ctx, mongoID, bson.M{
+		"$inc": bson.M{"attempts": 1},
+		"$set": bson.M{
+			"last_error":      cause.Error(),
+			"next_attempt_at": retryAt,
+		},
+	})
+	return
+}
+
+// EnsureIndexes creates the index ClaimPending relies on, it is safe to call on every start.
+func (r *OutboxRepositoryMongo) EnsureIndexes(ctx context.Context) (err error) {
+	var name string
+	dlog := logging.MongoDB(ctx, "OutboxRepositoryMongo.EnsureIndexes", nil)
+	defer func() { dlog(name, err) }()
+
+	// status 相等, created_at 排序, next_attempt_at 范围
+	name, err = r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		Options: options.Index().SetName("status_1_created_at_1_next_attempt_at_1"),
+	})
+	return
+}
+
+func (r *OutboxRepositoryMongo) collection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(outboxCollName)
+}
+
+func (r *OutboxRepositoryMongo) marshalToModel(m *domain.OutboxMessage) outboxModel {
+	createdAt := m.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	return outboxModel{
+		MongoID:       primitive.NewObjectID(),
+		Dest:          m.Dest,
+		Broadcast:     m.Broadcast,
+		Body:          m.Body,
+		Headers:       m.Headers,
+		Status:        outboxStatusPending,
+		CreatedAt:     createdAt,
+		NextAttemptAt: createdAt,
+	}
+}
+
+func (r *OutboxRepositoryMongo) unmarshal(read *outboxModel) *domain.OutboxMessage {
+	return &domain.OutboxMessage{
+		ID:        read.MongoID.Hex(),
+		Dest:      read.Dest,
+		Broadcast: read.Broadcast,
+		Body:      read.Body,
+		Headers:   read.Headers,
+		Attempts:  read.Attempts,
+		CreatedAt: read.CreatedAt,
+	}
+}
+
+// impl domain.Transactor
+type TransactorMongo struct {
+	db *mongo.Client
+}
+
+func NewTransactorMongo(db *mongo.Client) *TransactorMongo {
+	return &TransactorMongo{db: db}
+}
+
+// InTransaction needs mongo running as a replica set, see docker-compose.yml.
+func (t *TransactorMongo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.db.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
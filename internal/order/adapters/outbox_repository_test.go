@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepositoryMongo(t *testing.T) {
+	t.Parallel()
+	c, database := setupTestMongo(t)
+	r := NewOutboxRepositoryMongo(c)
+	r.database = database
+	ctx := context.Background()
+	require.NoError(t, r.EnsureIndexes(ctx))
+
+	now := time.Now()
+	require.NoError(t, r.Add(ctx,
+		&domain.OutboxMessage{Dest: "order.created", Body: []byte(`{"ID":"1"}`), CreatedAt: now.Add(-2 * time.Second)},
+		&domain.OutboxMessage{Dest: "order.cancelled", Broadcast: true, Body: []byte(`{"ID":"2"}`), CreatedAt: now.Add(-time.Second)},
+	))
+
+	// oldest first
+	claimed, err := r.ClaimPending(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	first := claimed[0]
+	assert.Equal(t, "order.created", first.Dest)
+
+	// the lease hides the first one from other relays
+	claimed, err = r.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	second := claimed[0]
+	assert.Equal(t, "order.cancelled", second.Dest)
+	assert.True(t, second.Broadcast)
+
+	// a failed msg comes back at retryAt with one more attempt, a sent one never does
+	require.NoError(t, r.MarkSent(ctx, first.ID))
+	require.NoError(t, r.MarkFailed(ctx, second.ID, errors.New("broker down"), time.Now().Add(-time.Second)))
+	claimed, err = r.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, second.ID, claimed[0].ID)
+	assert.Equal(t, 1, claimed[0].Attempts)
+
+	claimed, err = r.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/peiyouyao/gorder/order/domain/saga"
+)
+
+// impl saga.Repository
+type SagaRepositoryInmem struct {
+	lock  sync.RWMutex
+	sagas map[string]*saga.Saga
+}
+
+func NewSagaRepositoryInmem() *SagaRepositoryInmem {
+	return &SagaRepositoryInmem{sagas: make(map[string]*saga.Saga)}
+}
+
+func (r *SagaRepositoryInmem) Create(_ context.Context, s *saga.Saga) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.sagas[s.OrderID]; ok {
+		return fmt.Errorf("saga of order %s already exists", s.OrderID)
+	}
+	r.sagas[s.OrderID] = copySaga(s)
+	return nil
+}
+
+func (r *SagaRepositoryInmem) Get(_ context.Context, orderID string) (*saga.Saga, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	s, ok := r.sagas[orderID]
+	if !ok {
+		return nil, saga.NotFoundError{OrderID: orderID}
+	}
+	return copySaga(s), nil
+}
+
+// updateFn runs under the lock, so there is never a conflict
+func (r *SagaRepositoryInmem) Update(ctx context.Context, orderID string, updateFn func(context.Context, *saga.Saga) error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s, ok := r.sagas[orderID]
+	if !ok {
+		return saga.NotFoundError{OrderID: orderID}
+	}
+	updated := copySaga(s)
+	if err := updateFn(ctx, updated); err != nil {
+		return err
+	}
+	r.sagas[orderID] = updated
+	return nil
+}
+
+func (r *SagaRepositoryInmem) FindTimedOut(_ context.Context, now time.Time, limit int) ([]*saga.Saga, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var res []*saga.Saga
+	for _, s := range r.sagas {
+		if len(res) == limit {
+			break
+		}
+		if s.TimedOut(now) || s.Status == saga.StatusCompensating {
+			res = append(res, copySaga(s))
+		}
+	}
+	return res, nil
+}
+
+func copySaga(s *saga.Saga) *saga.Saga {
+	c := *s
+	c.History = slices.Clone(s.History)
+	return &c
+}
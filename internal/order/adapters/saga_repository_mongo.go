@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	_ "github.com/peiyouyao/gorder/common/config"
+	"github.com/peiyouyao/gorder/common/logging"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var sagaCollName = viper.GetString("mongo.saga-coll-name")
+
+// impl saga.Repository
+type SagaRepositoryMongo struct {
+	db       *mongo.Client
+	database string
+}
+
+type sagaModel struct {
+	OrderID    string            `bson:"_id"`
+	CustomerID string            `bson:"customer_id"`
+	Step       string            `bson:"step"`
+	Status     string            `bson:"status"`
+	Deadline   *time.Time        `bson:"deadline,omitempty"`
+	History    []sagaRecordModel `bson:"history"`
+	CreatedAt  time.Time         `bson:"created_at"`
+	UpdatedAt  time.Time         `bson:"updated_at"`
+	Version    int64             `bson:"version"` // 乐观锁, 每次 Update 加一
+}
+
+type sagaRecordModel struct {
+	Step   string    `bson:"step"`
+	Action string    `bson:"action"`
+	At     time.Time `bson:"at"`
+	Reason string    `bson:"reason,omitempty"`
+}
+
+func NewSagaRepositoryMongo(db *mongo.Client) *SagaRepositoryMongo {
+	return &SagaRepositoryMongo{db: db, database: dbName}
+}
+
+// Create, like Update, joins the transaction of TransactorMongo.InTransaction when called with its ctx.
+func (r *SagaRepositoryMongo) Create(ctx context.Context, s *saga.Saga) (err error) {
+	var res *mongo.InsertOneResult
+	dlog := logging.MongoDB(ctx, "SagaRepositoryMongo.Create", logrus.Fields{"saga": s})
+	defer func() { dlog(res, err) }()
+
+	res, err = r.collection().InsertOne(ctx, r.marshalToModel(s, 0))
+	return
+}
+
+func (r *SagaRepositoryMongo) Get(ctx context.Context, orderID string) (got *saga.Saga, err error) {
+	dlog := logging.MongoDB(ctx, "SagaRepositoryMongo.Get", logrus.Fields{"order_id": orderID})
+	defer func() { dlog(got, err) }()
+
+	read, err := r.get(ctx, orderID)
+	if err != nil {
+		return
+	}
+	got = r.unmarshal(read)
+	return
+}
+
+func (r *SagaRepositoryMongo) Update(ctx context.Context, orderID string, updateFn func(context.Context, *saga.Saga) error) (err error) {
+	var res *mongo.UpdateResult
+	dlog := logging.MongoDB(ctx, "SagaRepositoryMongo.Update", logrus.Fields{"order_id": orderID})
+	defer func() { dlog(res, err) }()
+
+	read, err := r.get(ctx, orderID)
+	if err != nil {
+		return
+	}
+	s := r.unmarshal(read)
+	if err = updateFn(ctx, s); err != nil {
+		return
+	}
+	res, err = r.collection().ReplaceOne(
+		ctx,
+		bson.M{"_id": orderID, "version": read.Version},
+		r.marshalToModel(s, read.Version+1),
+	)
+	if err == nil && res.MatchedCount == 0 {
+		err = saga.ConflictError{OrderID: orderID}
+	}
+	return
+}
+
+func (r *SagaRepositoryMongo) FindTimedOut(ctx context.Context, now time.Time, limit int) (found []*saga.Saga, err error) {
+	fs := logrus.Fields{
+		"now":   now,
+		"limit": limit,
+	}
+	dlog := logging.MongoDB(ctx, "SagaRepositoryMongo.FindTimedOut", fs)
+	defer func() { dlog(len(found), err) }()
+
+	cur, err := r.collection().Find(
+		ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": string(saga.StatusRunning), "deadline": bson.M{"$lt": now}},
+			bson.M{"status": string(saga.StatusCompensating)},
+		}},
+		options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+
+	var reads []*sagaModel
+	if err = cur.All(ctx, &reads); err != nil {
+		return
+	}
+	for _, read := range reads {
+		found = append(found, r.unmarshal(read))
+	}
+	return
+}
+
+// EnsureIndexes creates the index FindTimedOut relies on, it is safe to call on every start.
+func (r *SagaRepositoryMongo) EnsureIndexes(ctx context.Context) (err error) {
+	var name string
+	dlog := logging.MongoDB(ctx, "SagaRepositoryMongo.EnsureIndexes", nil)
+	defer func() { dlog(name, err) }()
+
+	name, err = r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "deadline", Value: 1}},
+		Options: options.Index().SetName("status_1_deadline_1"),
+	})
+	return
+}
+
+func (r *SagaRepositoryMongo) get(ctx context.Context, orderID string) (*sagaModel, error) {
+	read := &sagaModel{}
+	if err := r.collection().FindOne(ctx, bson.M{"_id": orderID}).Decode(read); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, saga.NotFoundError{OrderID: orderID}
+		}
+		return nil, err
+	}
+	return read, nil
+}
+
+func (r *SagaRepositoryMongo) collection() *mongo.Collection {
+	return r.db.Database(r.database).Collection(sagaCollName)
+}
+
+func (r *SagaRepositoryMongo) marshalToModel(s *saga.Saga, version int64) sagaModel {
+	m := sagaModel{
+		OrderID:    s.OrderID,
+		CustomerID: s.CustomerID,
+		Step:       string(s.Step),
+		Status:     string(s.Status),
+		History:    make([]sagaRecordModel, 0, len(s.History)),
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+		Version:    version,
+	}
+	if !s.Deadline.IsZero() {
+		deadline := s.Deadline
+		m.Deadline = &deadline
+	}
+	for _, h := range s.History {
+		m.History = append(m.History, sagaRecordModel{
+			Step:   string(h.Step),
+			Action: h.Action,
+			At:     h.At,
+			Reason: h.Reason,
+		})
+	}
+	return m
+}
+
+func (r *SagaRepositoryMongo) unmarshal(read *sagaModel) *saga.Saga {
+	s := &saga.Saga{
+		OrderID:    read.OrderID,
+		CustomerID: read.CustomerID,
+		Step:       saga.Step(read.Step),
+		Status:     saga.Status(read.Status),
+		CreatedAt:  read.CreatedAt,
+		UpdatedAt:  read.UpdatedAt,
+	}
+	if read.Deadline != nil {
+		s.Deadline = *read.Deadline
+	}
+	for _, h := range read.History {
+		s.History = append(s.History, saga.Record{
+			Step:   saga.Step(h.Step),
+			Action: h.Action,
+			At:     h.At,
+			Reason: h.Reason,
+		})
+	}
+	return s
+}
@@ -12,7 +12,10 @@ import (
 	"github.com/peiyouyao/gorder/order/adapters/grpc"
 	"github.com/peiyouyao/gorder/order/app/command"
 	"github.com/peiyouyao/gorder/order/app/query"
-	amqp "github.com/rabbitmq/amqp091-go"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/peiyouyao/gorder/order/infrastructure/mq"
+	"github.com/peiyouyao/gorder/order/infrastructure/outbox"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -26,41 +29,66 @@ type Application struct {
 }
 
 type Commands struct {
-	CreateOrder command.CreateOrderHandler
-	UpdateOrder command.UpdateOrderHandler
+	CreateOrder  command.CreateOrderHandler
+	UpdateOrder  command.UpdateOrderHandler
+	CancelOrder  command.CancelOrderHandler
+	RejectOrder  command.RejectOrderHandler
+	ExpireOrders command.ExpireOrdersHandler
+
+	CompensateSagas command.CompensateSagasHandler
+
+	CommitReservation command.CommitReservationHandler
 }
 
 type Queries struct {
-	GetCustomerOrder query.GetCustomerOrderHandler
+	GetCustomerOrder   query.GetCustomerOrderHandler
+	ListCustomerOrders query.ListCustomerOrdersHandler
+	GetOrderSaga       query.GetOrderSagaHandler
+	GetOrderHistory    query.GetOrderHistoryHandler
+	WatchOrder         query.WatchOrderHandler
 }
 
 func NewApplication(ctx context.Context) (Application, func()) {
+	ctx, cancel := context.WithCancel(ctx)
 	stockClient, closeStockClient, err := grpcClient.NewStockGRPCClient(ctx)
 	if err != nil {
 		panic(err)
 	}
-	ch, closeConn := broker.Connect(
-		viper.GetString("rabbitmq.user"),
-		viper.GetString("rabbitmq.password"),
-		viper.GetString("rabbitmq.host"),
-		viper.GetString("rabbitmq.port"),
-	)
+	publisher, closeBroker := broker.Open(viper.GetString("order.service-name"))
 	stockGRPC := grpc.NewStockGRPC(stockClient)
 
-	return newAppliction(ctx, stockGRPC, ch), func() {
+	return newAppliction(ctx, stockGRPC, publisher), func() {
+		cancel()
 		_ = closeStockClient()
-		_ = ch.Close()
-		_ = closeConn()
+		_ = closeBroker()
 	}
 }
 
 func newAppliction(
-	_ context.Context,
+	ctx context.Context,
 	stockGRPC query.StockService,
-	ch *amqp.Channel,
+	publisher broker.Publisher,
 ) Application {
 	mongoCli := newMongoClient()
-	orderRepo := adapters.NewOrderRepositoryMongo(mongoCli)
+	orderRepo := newOrderRepository(ctx, mongoCli)
+	sagaRepo := adapters.NewSagaRepositoryMongo(mongoCli)
+	if err := sagaRepo.EnsureIndexes(ctx); err != nil {
+		logrus.Warnf("Ensure saga indexes fail err=%v", err)
+	}
+	sagaTimeouts := newSagaTimeouts()
+	watcher := adapters.NewOrderWatcherInmem()
+	transactor := adapters.NewTransactorMongo(mongoCli)
+	orderOutbox := adapters.NewOutboxRepositoryMongo(mongoCli)
+	if err := orderOutbox.EnsureIndexes(ctx); err != nil {
+		logrus.Warnf("Ensure outbox indexes fail err=%v", err)
+	}
+	eventPublisher := &mq.OutboxEventPublisher{Outbox: orderOutbox}
+	idempotencyStore := adapters.NewIdempotencyStoreRedis()
+	go outbox.NewRelay(orderOutbox, &mq.BrokerEventPublisher{
+		Publisher: publisher,
+		Producer:  viper.GetString("order.service-name"),
+	}).Run(ctx)
+
 	logger := logrus.NewEntry(logrus.StandardLogger())
 	metrics := metrics.NewPrometheusMetricsClient(&metrics.PrometheusMetricsClientConfig{
 		Host:        viper.GetString("order.metrics-addr"),
@@ -69,15 +97,54 @@ func newAppliction(
 
 	return Application{
 		Commands: Commands{
-			CreateOrder: command.NewCreateOrderHandler(orderRepo, stockGRPC, ch, logger, metrics),
-			UpdateOrder: command.NewUpdateOrderHandler(orderRepo, logger, metrics),
+			CreateOrder:  command.NewCreateOrderHandler(orderRepo, stockGRPC, transactor, eventPublisher, idempotencyStore, sagaRepo, sagaTimeouts, logger, metrics),
+			UpdateOrder:  command.NewUpdateOrderHandler(orderRepo, sagaRepo, sagaTimeouts, watcher, logger, metrics),
+			CancelOrder:  command.NewCancelOrderHandler(orderRepo, transactor, eventPublisher, sagaRepo, watcher, logger, metrics),
+			RejectOrder:  command.NewRejectOrderHandler(orderRepo, transactor, eventPublisher, sagaRepo, watcher, logger, metrics),
+			ExpireOrders: command.NewExpireOrdersHandler(orderRepo, transactor, eventPublisher, sagaRepo, watcher, logger, metrics),
+
+			CompensateSagas: command.NewCompensateSagasHandler(orderRepo, sagaRepo, sagaTimeouts, transactor, eventPublisher, watcher, logger, metrics),
+
+			CommitReservation: command.NewCommitReservationHandler(stockGRPC, logger, metrics),
 		},
 		Queries: Queries{
-			GetCustomerOrder: query.NewGetCustomerOrderHandler(orderRepo, logger, metrics),
+			GetCustomerOrder:   query.NewGetCustomerOrderHandler(orderRepo, logger, metrics),
+			ListCustomerOrders: query.NewListCustomerOrdersHandler(orderRepo, logger, metrics),
+			GetOrderSaga:       query.NewGetOrderSagaHandler(sagaRepo, logger, metrics),
+			GetOrderHistory:    query.NewGetOrderHistoryHandler(orderRepo, logger, metrics),
+			WatchOrder:         query.NewWatchOrderHandler(orderRepo, watcher, viper.GetDuration("order.watch.resync")*time.Second, logger, metrics),
 		},
 	}
 }
 
+// order.repository 选择订单仓库: mongo 或 event-sourced
+func newOrderRepository(ctx context.Context, mongoCli *mongo.Client) domain.Repository {
+	var repo interface {
+		domain.Repository
+		EnsureIndexes(ctx context.Context) error
+	}
+	switch kind := viper.GetString("order.repository"); kind {
+	case "", "mongo":
+		repo = adapters.NewOrderRepositoryMongo(mongoCli)
+	case "event-sourced":
+		repo = adapters.NewOrderRepositoryEventSourced(mongoCli)
+	default:
+		logrus.Panicf("unknown order.repository %q", kind)
+	}
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		logrus.Warnf("Ensure order indexes fail err=%v", err)
+	}
+	return repo
+}
+
+func newSagaTimeouts() saga.Timeouts {
+	return saga.Timeouts{
+		saga.StepCreatePaymentLink: viper.GetDuration("order.saga.timeouts.create-payment-link") * time.Second,
+		saga.StepAwaitPayment:      viper.GetDuration("order.saga.timeouts.await-payment") * time.Second,
+		saga.StepCook:              viper.GetDuration("order.saga.timeouts.cook") * time.Second,
+	}
+}
+
 func newMongoClient() *mongo.Client {
 	uri := fmt.Sprintf(
 		"mongodb://%s:%s@%s:%s",
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type CancelOrder struct {
+	CustomerID string
+	OrderID    string
+	Reason     string
+}
+
+type CancelOrderHandler decorator.CommandHandler[CancelOrder, interface{}]
+
+type cancelOrderHandler struct {
+	orderRepo      domain.Repository
+	transactor     domain.Transactor
+	eventPublisher domain.EventPublisher
+	sagaRepo       saga.Repository
+	watcher        domain.Watcher
+}
+
+func NewCancelOrderHandler(
+	orderRepo domain.Repository,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+	sagaRepo saga.Repository,
+	watcher domain.Watcher,
+	logger *logrus.Entry,
+	metricClient metrics.MetricsClient,
+) CancelOrderHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	if transactor == nil {
+		panic("nil transactor")
+	}
+	if eventPublisher == nil {
+		panic("nil eventPublisher")
+	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
+	return decorator.ApplyCommandDecorators[CancelOrder, interface{}](
+		cancelOrderHandler{
+			orderRepo:      orderRepo,
+			transactor:     transactor,
+			eventPublisher: eventPublisher,
+			sagaRepo:       sagaRepo,
+			watcher:        watcher,
+		},
+		logger,
+		metricClient,
+	)
+}
+
+// 取消订单, 广播 order.cancelled, stock 收到后归还库存
+func (c cancelOrderHandler) Handle(ctx context.Context, cmd CancelOrder) (interface{}, error) {
+	err := c.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		o, err := c.orderRepo.Get(ctx, cmd.OrderID, cmd.CustomerID)
+		if err != nil {
+			return err
+		}
+		if err = o.Cancel(); err != nil {
+			return err
+		}
+
+		logrus.Tracef("orderRepo.Update start order=%v", *o)
+		if err = c.orderRepo.Update(ctx, o, func(_ context.Context, order *domain.Order) (*domain.Order, error) {
+			return order, nil
+		}); err != nil {
+			return err
+		}
+
+		if err = c.eventPublisher.Broadcast(ctx, domain.DomainEvent{
+			Dest: broker.EventOrderCancelled,
+			Data: o.Entity(),
+		}); err != nil {
+			return errors.Wrap(err, "failed to save order cancelled event")
+		}
+		return endSaga(ctx, c.sagaRepo, o.ID, strings.TrimSuffix("order cancelled: "+cmd.Reason, ": "))
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.watcher.Notify(cmd.OrderID)
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id": cmd.OrderID,
+		"reason":   cmd.Reason,
+	}).Info("Order cancelled")
+	return nil, nil
+}
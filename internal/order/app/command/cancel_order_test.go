@@ -0,0 +1,65 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app/command"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := adapters.NewOrderRepositoryInmem()
+	publisher := &fakePublisher{}
+	watcher := adapters.NewOrderWatcherInmem()
+	handler := command.NewCancelOrderHandler(
+		orderRepo,
+		fakeTransactor{},
+		publisher,
+		adapters.NewSagaRepositoryInmem(),
+		watcher,
+		logrus.NewEntry(logrus.StandardLogger()),
+		metrics.NoMetrics{},
+	)
+	pending, err := domain.NewPendingOrder("customer-1", []*entity.Item{{ID: "item-1", Quantity: 1}})
+	require.NoError(t, err)
+	o, err := orderRepo.Create(ctx, pending)
+	require.NoError(t, err)
+
+	changed, stop := watcher.Watch(o.ID)
+	defer stop()
+	_, err = handler.Handle(ctx, command.CancelOrder{CustomerID: o.CustomerID, OrderID: o.ID, Reason: "changed my mind"})
+	require.NoError(t, err)
+
+	got, err := orderRepo.Get(ctx, o.ID, o.CustomerID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.OrderStatusCancelled, got.Status)
+	assert.Equal(t, []string{broker.EventOrderCancelled}, publisher.dests)
+	assertNotified(t, changed)
+
+	// 已支付的订单不能取消, 也不通知
+	paid, err := orderRepo.Create(ctx, &domain.Order{
+		CustomerID: "customer-1",
+		Status:     constants.OrderStatusPaid,
+		Items:      []*entity.Item{{ID: "item-1", Quantity: 1}},
+	})
+	require.NoError(t, err)
+	changed, stop = watcher.Watch(paid.ID)
+	defer stop()
+	_, err = handler.Handle(ctx, command.CancelOrder{CustomerID: paid.CustomerID, OrderID: paid.ID})
+	assert.ErrorAs(t, err, &domain.InvalidTransitionError{})
+	select {
+	case <-changed:
+		t.Error("notified of a failed cancel")
+	default:
+	}
+}
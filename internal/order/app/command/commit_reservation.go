@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/app/query"
+	"github.com/sirupsen/logrus"
+)
+
+type CommitReservation struct {
+	OrderID string
+}
+
+type CommitReservationHandler decorator.CommandHandler[CommitReservation, interface{}]
+
+type commitReservationHandler struct {
+	stockGRPC query.StockService
+}
+
+func NewCommitReservationHandler(
+	stockGRPC query.StockService,
+	logger *logrus.Entry,
+	metricClient metrics.MetricsClient,
+) CommitReservationHandler {
+	if stockGRPC == nil {
+		panic("nil stockGRPC")
+	}
+	return decorator.ApplyCommandDecorators[CommitReservation, interface{}](
+		commitReservationHandler{stockGRPC: stockGRPC},
+		logger,
+		metricClient,
+	)
+}
+
+// 订单已支付, 让 stock 把预占的库存转为正式扣减
+func (c commitReservationHandler) Handle(ctx context.Context, cmd CommitReservation) (interface{}, error) {
+	logrus.Tracef("stockGRPC.CommitReservation start order_id=%s", cmd.OrderID)
+	if err := c.stockGRPC.CommitReservation(ctx, cmd.OrderID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+)
+
+type CompensateSagas struct {
+	Now   time.Time
+	Limit int
+}
+
+// returns how many sagas were compensated or failed
+type CompensateSagasHandler decorator.CommandHandler[CompensateSagas, int]
+
+type compensateSagasHandler struct {
+	orderRepo    domain.Repository
+	sagaRepo     saga.Repository
+	sagaTimeouts saga.Timeouts
+	expirer      expireOrdersHandler
+	rejecter     rejectOrderHandler
+}
+
+func NewCompensateSagasHandler(
+	orderRepo domain.Repository,
+	sagaRepo saga.Repository,
+	sagaTimeouts saga.Timeouts,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+	watcher domain.Watcher,
+	logger *logrus.Entry,
+	metricClient metrics.MetricsClient,
+) CompensateSagasHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	if transactor == nil {
+		panic("nil transactor")
+	}
+	if eventPublisher == nil {
+		panic("nil eventPublisher")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
+	return decorator.ApplyCommandDecorators[CompensateSagas, int](
+		compensateSagasHandler{
+			orderRepo:    orderRepo,
+			sagaRepo:     sagaRepo,
+			sagaTimeouts: sagaTimeouts,
+			expirer: expireOrdersHandler{
+				orderRepo:      orderRepo,
+				transactor:     transactor,
+				eventPublisher: eventPublisher,
+				sagaRepo:       sagaRepo,
+				watcher:        watcher,
+			},
+			rejecter: rejectOrderHandler{
+				orderRepo:      orderRepo,
+				transactor:     transactor,
+				eventPublisher: eventPublisher,
+				sagaRepo:       sagaRepo,
+				watcher:        watcher,
+			},
+		},
+		logger,
+		metricClient,
+	)
+}
+
+/*
+对超时的 saga 执行当前步骤的补偿:
+  - create_payment_link, await_payment: 过期订单, 广播 order.expired, stock 归还库存, payment 关闭支付链接
+  - cook: 拒绝订单, 广播 order.rejected, payment 自动退款, saga 标记为 failed 直到退款完成
+
+saga 在订单和库存预占成功后才保存, 没有 reserve_stock 的补偿; 找不到订单的 saga 标记为 failed.
+*/
+func (c compensateSagasHandler) Handle(ctx context.Context, cmd CompensateSagas) (int, error) {
+	timedOut, err := c.sagaRepo.FindTimedOut(ctx, cmd.Now, cmd.Limit)
+	if err != nil {
+		return 0, err
+	}
+
+	compensated := 0
+	for _, s := range timedOut {
+		if err = c.compensate(ctx, s); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": s.OrderID,
+				"step":     s.Step,
+				"err":      err.Error(),
+			}).Warn("Compensate saga fail")
+			continue
+		}
+		compensated++
+	}
+	return compensated, nil
+}
+
+func (c compensateSagasHandler) compensate(ctx context.Context, s *saga.Saga) error {
+	o, err := c.orderRepo.Get(ctx, s.OrderID, s.CustomerID)
+	if errors.As(err, &domain.NotFoundError{}) {
+		// nothing left to compensate, stop picking the saga up
+		return c.update(ctx, s.OrderID, func(s *saga.Saga) error {
+			s.Fail("order not found", time.Now())
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+	step, ok := saga.StepOf(o.Status)
+	switch {
+	case !ok:
+		// cancelled or expired while the saga was not looking
+		return c.update(ctx, s.OrderID, func(s *saga.Saga) error {
+			s.Compensated("order "+o.Status, time.Now())
+			return nil
+		})
+	case step != s.Step && s.Status == saga.StatusRunning:
+		// the order moved on but advancing the saga failed, catch up instead of compensating
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id": s.OrderID,
+			"from":     s.Step,
+			"to":       step,
+		}).Info("Saga behind its order")
+		return c.update(ctx, s.OrderID, func(s *saga.Saga) error {
+			return s.Advance(step, time.Now(), c.sagaTimeouts)
+		})
+	}
+
+	reason := fmt.Sprintf("step %s timed out", s.Step)
+	if err = c.update(ctx, s.OrderID, func(s *saga.Saga) error {
+		return s.Compensate(reason, time.Now())
+	}); err != nil {
+		return err
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id": s.OrderID,
+		"step":     s.Step,
+	}).Warn("Compensating saga")
+
+	switch s.Step {
+	case saga.StepCreatePaymentLink, saga.StepAwaitPayment:
+		// marks the saga compensated in the same transaction
+		return c.expirer.expire(ctx, s.OrderID, s.CustomerID, reason)
+	default:
+		// marks the saga failed in the same transaction, payment refunds the order
+		return c.rejecter.reject(ctx, s.OrderID, s.CustomerID, reason+", waiting for refund")
+	}
+}
+
+func (c compensateSagasHandler) update(ctx context.Context, orderID string, fn func(s *saga.Saga) error) error {
+	return c.sagaRepo.Update(ctx, orderID, func(_ context.Context, s *saga.Saga) error {
+		return fn(s)
+	})
+}
@@ -0,0 +1,103 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app/command"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompensateSagas(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := adapters.NewOrderRepositoryInmem()
+	sagaRepo := adapters.NewSagaRepositoryInmem()
+	publisher := &fakePublisher{}
+	watcher := adapters.NewOrderWatcherInmem()
+	timeouts := saga.Timeouts{saga.StepAwaitPayment: time.Minute, saga.StepCook: time.Minute}
+	handler := command.NewCompensateSagasHandler(
+		orderRepo,
+		sagaRepo,
+		timeouts,
+		fakeTransactor{},
+		publisher,
+		watcher,
+		logrus.NewEntry(logrus.StandardLogger()),
+		metrics.NoMetrics{},
+	)
+
+	// 返回一个停在 step 的 saga 对应的订单
+	start := func(status string, step saga.Step) *domain.Order {
+		o, err := orderRepo.Create(ctx, &domain.Order{
+			CustomerID:  "customer-1",
+			Status:      status,
+			PaymentLink: "https://pay.example/1",
+			Items:       []*entity.Item{{ID: "item-1", Quantity: 1}},
+		})
+		require.NoError(t, err)
+		s, err := saga.New(o.ID, o.CustomerID, time.Now(), timeouts)
+		require.NoError(t, err)
+		require.NoError(t, s.Advance(step, time.Now(), timeouts))
+		require.NoError(t, sagaRepo.Create(ctx, s))
+		return o
+	}
+	paid := start(constants.OrderStatusPaid, saga.StepCook)
+	unpaid := start(constants.OrderStatusWaitingForPayment, saga.StepAwaitPayment)
+	missing, err := saga.New("order-missing", "customer-1", time.Now(), timeouts)
+	require.NoError(t, err)
+	require.NoError(t, missing.Advance(saga.StepAwaitPayment, time.Now(), timeouts))
+	require.NoError(t, sagaRepo.Create(ctx, missing))
+
+	rejected, stopRejected := watcher.Watch(paid.ID)
+	defer stopRejected()
+	expired, stopExpired := watcher.Watch(unpaid.ID)
+	defer stopExpired()
+	n, err := handler.Handle(ctx, command.CompensateSagas{Now: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	// 没有按时做好的订单被拒绝, payment 收到 order.rejected 后退款
+	o, err := orderRepo.Get(ctx, paid.ID, paid.CustomerID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.OrderStatusRejected, o.Status)
+	s, err := sagaRepo.Get(ctx, paid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusFailed, s.Status)
+	assertNotified(t, rejected)
+
+	o, err = orderRepo.Get(ctx, unpaid.ID, unpaid.CustomerID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.OrderStatusExpired, o.Status)
+	s, err = sagaRepo.Get(ctx, unpaid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompensated, s.Status)
+	assertNotified(t, expired)
+	assert.ElementsMatch(t, []string{broker.EventOrderRejected, broker.EventOrderExpired}, publisher.dests)
+
+	// 找不到订单的 saga 不再被捡起
+	s, err = sagaRepo.Get(ctx, missing.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusFailed, s.Status)
+	n, err = handler.Handle(ctx, command.CompensateSagas{Now: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func assertNotified(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	default:
+		t.Error("watcher not notified")
+	}
+}
@@ -2,7 +2,12 @@ package command
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/peiyouyao/gorder/common/broker"
 	"github.com/peiyouyao/gorder/common/convert"
@@ -11,15 +16,16 @@ import (
 	"github.com/peiyouyao/gorder/common/metrics"
 	"github.com/peiyouyao/gorder/order/app/query"
 	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
 	"github.com/pkg/errors"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 )
 
 type CreateOrder struct {
-	CustomerID string
-	Items      []*entity.ItemWithQuantity
+	CustomerID     string
+	Items          []*entity.ItemWithQuantity
+	IdempotencyKey string // optional
 }
 
 type CreateOrderResult struct {
@@ -29,15 +35,23 @@ type CreateOrderResult struct {
 type CreateOrderHandler decorator.CommandHandler[CreateOrder, *CreateOrderResult]
 
 type createOrderHandler struct {
-	orderRepo domain.Repository
-	stockGRPC query.StockService
-	channel   *amqp.Channel
+	orderRepo        domain.Repository
+	stockGRPC        query.StockService
+	transactor       domain.Transactor
+	eventPublisher   domain.EventPublisher
+	idempotencyStore IdempotencyStore
+	sagaRepo         saga.Repository
+	sagaTimeouts     saga.Timeouts
 }
 
 func NewCreateOrderHandler(
 	orderRepo domain.Repository,
 	stockGRPC query.StockService,
-	channel *amqp.Channel,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+	idempotencyStore IdempotencyStore,
+	sagaRepo saga.Repository,
+	sagaTimeouts saga.Timeouts,
 	logger *logrus.Entry,
 	metricClient metrics.MetricsClient,
 ) CreateOrderHandler {
@@ -47,31 +61,77 @@ func NewCreateOrderHandler(
 	if stockGRPC == nil {
 		panic("nil stockGRPC")
 	}
-	if channel == nil {
-		panic("nil channel ")
+	if transactor == nil {
+		panic("nil transactor")
+	}
+	if eventPublisher == nil {
+		panic("nil eventPublisher")
+	}
+	if idempotencyStore == nil {
+		panic("nil idempotencyStore")
+	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
 	}
 	return decorator.ApplyCommandDecorators[CreateOrder, *CreateOrderResult](
 		createOrderHandler{
-			orderRepo: orderRepo,
-			stockGRPC: stockGRPC,
-			channel:   channel,
+			orderRepo:        orderRepo,
+			stockGRPC:        stockGRPC,
+			transactor:       transactor,
+			eventPublisher:   eventPublisher,
+			idempotencyStore: idempotencyStore,
+			sagaRepo:         sagaRepo,
+			sagaTimeouts:     sagaTimeouts,
 		},
 		logger,
 		metricClient,
 	)
 }
 
-func (c createOrderHandler) Handle(ctx context.Context, cmd CreateOrder) (*CreateOrderResult, error) {
-	q, err := c.channel.QueueDeclare(broker.EventOrderCreated, true, false, false, false, nil)
+func (c createOrderHandler) Handle(ctx context.Context, cmd CreateOrder) (res *CreateOrderResult, err error) {
+	if cmd.IdempotencyKey == "" {
+		return c.create(ctx, cmd)
+	}
+
+	// key 按 customer 隔离, 不同用户可以用同一个 key
+	key := cmd.CustomerID + ":" + cmd.IdempotencyKey
+	fingerprint := fingerprintOf(cmd)
+	claimed, existing, err := c.idempotencyStore.Claim(ctx, key, IdempotencyRecord{Fingerprint: fingerprint})
 	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim idempotency key")
+	}
+	if !claimed {
+		logrus.WithContext(ctx).WithField("idempotency_key", cmd.IdempotencyKey).Info("Replay create order")
+		switch {
+		case existing.Fingerprint != fingerprint:
+			return nil, ErrIdempotencyKeyReused
+		case existing.OrderID == "":
+			return nil, ErrIdempotencyKeyInProgress
+		default:
+			return &CreateOrderResult{OrderID: existing.OrderID}, nil
+		}
+	}
+
+	if res, err = c.create(ctx, cmd); err != nil {
+		if rerr := c.idempotencyStore.Release(ctx, key); rerr != nil {
+			logrus.WithContext(ctx).Warnf("Release idempotency key fail key=%s err=%v", key, rerr)
+		}
 		return nil, err
 	}
+	// the order exists already, a failure here only means a retry with the key gets ErrIdempotencyKeyInProgress until the claim expires
+	if err := c.idempotencyStore.Complete(ctx, key, IdempotencyRecord{Fingerprint: fingerprint, OrderID: res.OrderID}); err != nil {
+		logrus.WithContext(ctx).Warnf("Complete idempotency key fail key=%s err=%v", key, err)
+	}
+	return res, nil
+}
 
+func (c createOrderHandler) create(ctx context.Context, cmd CreateOrder) (*CreateOrderResult, error) {
 	t := otel.Tracer("rabbitmq")
-	ctx, span := t.Start(ctx, fmt.Sprintf("rabbitmq.%s.publish", q.Name))
+	ctx, span := t.Start(ctx, fmt.Sprintf("rabbitmq.%s.publish", broker.EventOrderCreated))
 	defer span.End()
 
-	validItems, err := c.validate(ctx, cmd.Items)
+	packed := packItems(cmd.Items)
+	validItems, err := c.validate(ctx, packed)
 	if err != nil {
 		return nil, err
 	}
@@ -81,37 +141,69 @@ func (c createOrderHandler) Handle(ctx context.Context, cmd CreateOrder) (*Creat
 		return nil, err
 	}
 
-	logrus.Trace("orderRepo.Create start")
-	o, err := c.orderRepo.Create(ctx, pendingOrder)
+	// order 和 order.created 事件写在同一个事务里, 由 outbox relay 投递到 mq
+	var (
+		o        *domain.Order
+		reserved []string // 事务可能被重试, 每次都是新的 order id
+	)
+	err = c.transactor.InTransaction(ctx, func(ctx context.Context) (err error) {
+		logrus.Trace("orderRepo.Create start")
+		if o, err = c.orderRepo.Create(ctx, pendingOrder); err != nil {
+			logrus.Tracef("orderRepo.Create fail err=%v", err)
+			return err
+		}
+		logrus.Tracef("orderRepo.Create ok order=%v", *o)
+
+		reserveAt := time.Now()
+		if err = c.stockGRPC.ReserveItems(ctx, o.ID, convert.ItemWithQuantityEntitiesToProtos(packed)); err != nil {
+			return errors.Wrap(err, "failed to reserve stock")
+		}
+		reserved = append(reserved, o.ID)
+
+		if err = c.eventPublisher.Publish(ctx, domain.DomainEvent{
+			Dest: broker.EventOrderCreated,
+			Data: o.Entity(),
+		}); err != nil {
+			return errors.Wrap(err, "failed to save order created event")
+		}
+		return c.startSaga(ctx, o, reserveAt)
+	})
+	for _, id := range reserved {
+		if err == nil && id == o.ID {
+			continue
+		}
+		c.releaseReservation(ctx, id)
+	}
 	if err != nil {
-		logrus.Tracef("orderRepo.Create fail err=%v", err)
 		return nil, err
 	}
-	logrus.Tracef("orderRepo.Create ok order=%v", *o)
 
-	logrus.Trace("broker.PublishEvent start")
-	r := &broker.PublishEventReq{
-		Channel:  c.channel,
-		Routing:  broker.Direct,
-		Queue:    q.Name,
-		Exchange: "",
-		Body:     *o,
-	}
-	err = broker.PublishEvent(ctx, r)
+	return &CreateOrderResult{OrderID: o.ID}, nil
+}
+
+// 库存已预占, saga 从 create_payment_link 开始计时
+func (c createOrderHandler) startSaga(ctx context.Context, o *domain.Order, reserveAt time.Time) error {
+	s, err := saga.New(o.ID, o.CustomerID, reserveAt, c.sagaTimeouts)
 	if err != nil {
-		logrus.Tracef("broker.PublishEvent fail err=%v", err)
-		return nil, errors.Wrap(err, "failed to publish order created event")
+		return err
+	}
+	if err = s.Advance(saga.StepCreatePaymentLink, time.Now(), c.sagaTimeouts); err != nil {
+		return err
 	}
-	logrus.Tracef("broker.PublishEvent ok broker.PublishEventReq=%v", *r)
+	return errors.Wrap(c.sagaRepo.Create(ctx, s), "failed to save order saga")
+}
 
-	return &CreateOrderResult{OrderID: o.ID}, nil
+// best effort, stock releases expired reservations itself as well
+func (c createOrderHandler) releaseReservation(ctx context.Context, orderID string) {
+	if err := c.stockGRPC.ReleaseReservation(ctx, orderID); err != nil {
+		logrus.WithContext(ctx).Warnf("Release reservation fail order_id=%s err=%v", orderID, err)
+	}
 }
 
 func (c createOrderHandler) validate(ctx context.Context, items []*entity.ItemWithQuantity) ([]*entity.Item, error) {
 	if len(items) == 0 {
 		return nil, errors.New("must have at least one item")
 	}
-	items = packItems(items)
 	resp, err := c.stockGRPC.CheckIfItemsInStock(ctx, convert.ItemWithQuantityEntitiesToProtos(items))
 	if err != nil {
 		return nil, err
@@ -133,3 +225,17 @@ func packItems(items []*entity.ItemWithQuantity) []*entity.ItemWithQuantity {
 	}
 	return res
 }
+
+// same items in any order or split across lines give the same fingerprint
+func fingerprintOf(cmd CreateOrder) string {
+	items := packItems(cmd.Items)
+	slices.SortFunc(items, func(a, b *entity.ItemWithQuantity) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	h := sha256.New()
+	h.Write([]byte(cmd.CustomerID))
+	for _, it := range items {
+		fmt.Fprintf(h, "|%s:%d", it.ID, it.Quantity)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/common/genproto/stockpb"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app/command"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl query.StockService, every item is in stock
+type fakeStock struct {
+	err error // returned by ReserveItems
+}
+
+func (f *fakeStock) CheckIfItemsInStock(_ context.Context, items []*orderpb.ItemWithQuantity) (*stockpb.CheckIfItemsInStockResponse, error) {
+	resp := &stockpb.CheckIfItemsInStockResponse{InStock: 1}
+	for _, it := range items {
+		resp.Items = append(resp.Items, &orderpb.Item{ID: it.ID, Name: it.ID, Quantity: it.Quantity, PriceID: "price-" + it.ID})
+	}
+	return resp, nil
+}
+
+func (f *fakeStock) GetItems(context.Context, []string) ([]*orderpb.Item, error) {
+	return nil, nil
+}
+
+func (f *fakeStock) ReserveItems(context.Context, string, []*orderpb.ItemWithQuantity) error {
+	return f.err
+}
+
+func (f *fakeStock) CommitReservation(context.Context, string) error {
+	return nil
+}
+
+func (f *fakeStock) ReleaseReservation(context.Context, string) error {
+	return nil
+}
+
+// impl domain.Transactor, no rollback
+type fakeTransactor struct{}
+
+func (fakeTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// impl domain.EventPublisher, keeps the destinations
+type fakePublisher struct {
+	dests []string
+}
+
+func (f *fakePublisher) Publish(_ context.Context, event domain.DomainEvent) error {
+	f.dests = append(f.dests, event.Dest)
+	return nil
+}
+
+func (f *fakePublisher) Broadcast(ctx context.Context, event domain.DomainEvent) error {
+	return f.Publish(ctx, event)
+}
+
+// impl command.IdempotencyStore
+type fakeIdempotencyStore struct {
+	completeErr error
+	records     map[string]command.IdempotencyRecord
+}
+
+func (f *fakeIdempotencyStore) Claim(_ context.Context, key string, record command.IdempotencyRecord) (bool, *command.IdempotencyRecord, error) {
+	if existing, ok := f.records[key]; ok {
+		return false, &existing, nil
+	}
+	f.records[key] = record
+	return true, nil, nil
+}
+
+func (f *fakeIdempotencyStore) Complete(_ context.Context, key string, record command.IdempotencyRecord) error {
+	if f.completeErr != nil {
+		return f.completeErr
+	}
+	f.records[key] = record
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Release(_ context.Context, key string) error {
+	delete(f.records, key)
+	return nil
+}
+
+func TestCreateOrder_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	stock := &fakeStock{}
+	publisher := &fakePublisher{}
+	store := &fakeIdempotencyStore{records: make(map[string]command.IdempotencyRecord)}
+	handler := command.NewCreateOrderHandler(
+		adapters.NewOrderRepositoryInmem(),
+		stock,
+		fakeTransactor{},
+		publisher,
+		store,
+		adapters.NewSagaRepositoryInmem(),
+		saga.Timeouts{},
+		logrus.NewEntry(logrus.StandardLogger()),
+		metrics.NoMetrics{},
+	)
+	cmd := command.CreateOrder{
+		CustomerID:     "customer-1",
+		Items:          []*entity.ItemWithQuantity{{ID: "item-1", Quantity: 1}, {ID: "item-2", Quantity: 2}},
+		IdempotencyKey: "key-1",
+	}
+
+	first, err := handler.Handle(ctx, cmd)
+	require.NoError(t, err)
+
+	// 同样的请求, 顺序不同也返回第一次的订单
+	retry := cmd
+	retry.Items = []*entity.ItemWithQuantity{{ID: "item-2", Quantity: 2}, {ID: "item-1", Quantity: 1}}
+	res, err := handler.Handle(ctx, retry)
+	require.NoError(t, err)
+	assert.Equal(t, first.OrderID, res.OrderID)
+	assert.Equal(t, []string{broker.EventOrderCreated}, publisher.dests)
+
+	other := cmd
+	other.Items = []*entity.ItemWithQuantity{{ID: "item-1", Quantity: 3}}
+	_, err = handler.Handle(ctx, other)
+	assert.ErrorIs(t, err, command.ErrIdempotencyKeyReused)
+
+	// 另一个用户可以用同一个 key
+	otherCustomer := cmd
+	otherCustomer.CustomerID = "customer-2"
+	res, err = handler.Handle(ctx, otherCustomer)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.OrderID, res.OrderID)
+
+	// 订单建好了但没能 Complete, key 一直是进行中
+	inProgress := cmd
+	inProgress.IdempotencyKey = "key-2"
+	store.completeErr = errors.New("redis down")
+	_, err = handler.Handle(ctx, inProgress)
+	require.NoError(t, err)
+	store.completeErr = nil
+	_, err = handler.Handle(ctx, inProgress)
+	assert.ErrorIs(t, err, command.ErrIdempotencyKeyInProgress)
+
+	// 失败的请求释放 key, 可以用它重试
+	failed := cmd
+	failed.IdempotencyKey = "key-3"
+	stock.err = errors.New("stock down")
+	_, err = handler.Handle(ctx, failed)
+	require.Error(t, err)
+	stock.err = nil
+	_, err = handler.Handle(ctx, failed)
+	require.NoError(t, err)
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type ExpireOrders struct {
+	CreatedBefore time.Time
+	Limit         int
+}
+
+// returns how many orders were expired
+type ExpireOrdersHandler decorator.CommandHandler[ExpireOrders, int]
+
+type expireOrdersHandler struct {
+	orderRepo      domain.Repository
+	transactor     domain.Transactor
+	eventPublisher domain.EventPublisher
+	sagaRepo       saga.Repository
+	watcher        domain.Watcher
+}
+
+func NewExpireOrdersHandler(
+	orderRepo domain.Repository,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+	sagaRepo saga.Repository,
+	watcher domain.Watcher,
+	logger *logrus.Entry,
+	metricClient metrics.MetricsClient,
+) ExpireOrdersHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	if transactor == nil {
+		panic("nil transactor")
+	}
+	if eventPublisher == nil {
+		panic("nil eventPublisher")
+	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
+	return decorator.ApplyCommandDecorators[ExpireOrders, int](
+		expireOrdersHandler{
+			orderRepo:      orderRepo,
+			transactor:     transactor,
+			eventPublisher: eventPublisher,
+			sagaRepo:       sagaRepo,
+			watcher:        watcher,
+		},
+		logger,
+		metricClient,
+	)
+}
+
+// 过期未支付订单, 广播 order.expired, stock 归还库存, payment 关闭支付链接
+func (e expireOrdersHandler) Handle(ctx context.Context, cmd ExpireOrders) (int, error) {
+	unpaid, err := e.orderRepo.FindUnpaid(ctx, cmd.CreatedBefore, cmd.Limit)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, u := range unpaid {
+		if err = e.expire(ctx, u.ID, u.CustomerID, "order not paid in time"); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": u.ID,
+				"err":      err.Error(),
+			}).Warn("Expire order fail")
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (e expireOrdersHandler) expire(ctx context.Context, orderID, customerID, reason string) error {
+	err := e.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		// re-read inside the transaction, the order may have been paid since FindUnpaid
+		o, err := e.orderRepo.Get(ctx, orderID, customerID)
+		if err != nil {
+			return err
+		}
+		if err = o.Expire(); err != nil {
+			return err
+		}
+
+		logrus.Tracef("orderRepo.Update start order=%v", *o)
+		if err = e.orderRepo.Update(ctx, o, func(_ context.Context, order *domain.Order) (*domain.Order, error) {
+			return order, nil
+		}); err != nil {
+			return err
+		}
+
+		if err = e.eventPublisher.Broadcast(ctx, domain.DomainEvent{
+			Dest: broker.EventOrderExpired,
+			Data: o.Entity(),
+		}); err != nil {
+			return errors.Wrap(err, "failed to save order expired event")
+		}
+		return endSaga(ctx, e.sagaRepo, o.ID, reason)
+	})
+	if err != nil {
+		return err
+	}
+	e.watcher.Notify(orderID)
+	return nil
+}
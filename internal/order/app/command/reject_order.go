@@ -0,0 +1,119 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type RejectOrder struct {
+	CustomerID string
+	OrderID    string
+	Reason     string
+}
+
+type RejectOrderHandler decorator.CommandHandler[RejectOrder, interface{}]
+
+type rejectOrderHandler struct {
+	orderRepo      domain.Repository
+	transactor     domain.Transactor
+	eventPublisher domain.EventPublisher
+	sagaRepo       saga.Repository
+	watcher        domain.Watcher
+}
+
+func NewRejectOrderHandler(
+	orderRepo domain.Repository,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+	sagaRepo saga.Repository,
+	watcher domain.Watcher,
+	logger *logrus.Entry,
+	metricClient metrics.MetricsClient,
+) RejectOrderHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	if transactor == nil {
+		panic("nil transactor")
+	}
+	if eventPublisher == nil {
+		panic("nil eventPublisher")
+	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
+	return decorator.ApplyCommandDecorators[RejectOrder, interface{}](
+		rejectOrderHandler{
+			orderRepo:      orderRepo,
+			transactor:     transactor,
+			eventPublisher: eventPublisher,
+			sagaRepo:       sagaRepo,
+			watcher:        watcher,
+		},
+		logger,
+		metricClient,
+	)
+}
+
+/*
+厨房拒绝已支付的订单, 广播 order.rejected 开始退款.
+saga 标记为 failed, 直到退款完成都需要人关注.
+*/
+func (r rejectOrderHandler) Handle(ctx context.Context, cmd RejectOrder) (interface{}, error) {
+	if cmd.Reason == "" {
+		return nil, errors.New("empty reject reason")
+	}
+	if err := r.reject(ctx, cmd.OrderID, cmd.CustomerID, "order rejected by the kitchen, waiting for refund: "+cmd.Reason); err != nil {
+		return nil, err
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id": cmd.OrderID,
+		"reason":   cmd.Reason,
+	}).Info("Order rejected")
+	return nil, nil
+}
+
+// 拒绝订单并把 saga 标记为 failed, sagaReason 记录在 saga 中
+func (r rejectOrderHandler) reject(ctx context.Context, orderID, customerID, sagaReason string) error {
+	err := r.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		o, err := r.orderRepo.Get(ctx, orderID, customerID)
+		if err != nil {
+			return err
+		}
+		if err = o.Reject(); err != nil {
+			return err
+		}
+		if len(o.Changes()) == 0 {
+			// redelivered, already rejected
+			return nil
+		}
+
+		if err = r.orderRepo.Update(ctx, o, func(_ context.Context, order *domain.Order) (*domain.Order, error) {
+			return order, nil
+		}); err != nil {
+			return err
+		}
+		if err = r.eventPublisher.Broadcast(ctx, domain.DomainEvent{
+			Dest: broker.EventOrderRejected,
+			Data: o.Entity(),
+		}); err != nil {
+			return errors.Wrap(err, "failed to save order rejected event")
+		}
+		return failSaga(ctx, r.sagaRepo, o.ID, sagaReason)
+	})
+	if err != nil {
+		return err
+	}
+	r.watcher.Notify(orderID)
+	return nil
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+)
+
+// advanceSaga moves the saga to the step of o's status, orders created before sagas existed have none.
+func advanceSaga(ctx context.Context, sagaRepo saga.Repository, timeouts saga.Timeouts, o *domain.Order) error {
+	step, ok := saga.StepOf(o.Status)
+	if !ok {
+		return nil
+	}
+	err := sagaRepo.Update(ctx, o.ID, func(_ context.Context, s *saga.Saga) error {
+		return s.Advance(step, time.Now(), timeouts)
+	})
+	if errors.As(err, &saga.NotFoundError{}) {
+		logrus.WithContext(ctx).WithField("order_id", o.ID).Debug("Order has no saga")
+		return nil
+	}
+	return err
+}
+
+// endSaga records that the order was cancelled or expired, stock and payment compensate on the broadcast event.
+func endSaga(ctx context.Context, sagaRepo saga.Repository, orderID, reason string) error {
+	err := sagaRepo.Update(ctx, orderID, func(_ context.Context, s *saga.Saga) error {
+		s.Compensated(reason, time.Now())
+		return nil
+	})
+	if errors.As(err, &saga.NotFoundError{}) {
+		return nil
+	}
+	return err
+}
+
+// failSaga leaves the saga for a person, like a paid order that was not cooked in time.
+func failSaga(ctx context.Context, sagaRepo saga.Repository, orderID, reason string) error {
+	err := sagaRepo.Update(ctx, orderID, func(_ context.Context, s *saga.Saga) error {
+		s.Fail(reason, time.Now())
+		return nil
+	})
+	if errors.As(err, &saga.NotFoundError{}) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"context"
+	"errors"
+)
+
+// IdempotencyStore remembers which order an Idempotency-Key created.
+type IdempotencyStore interface {
+	// Claim reserves key for a request, when the key is taken it returns false and the stored record.
+	// The claim expires after a short while unless Complete is called, so a crashed request does not hold the key.
+	Claim(ctx context.Context, key string, record IdempotencyRecord) (bool, *IdempotencyRecord, error)
+	// Complete stores the result of the request that claimed key.
+	Complete(ctx context.Context, key string, record IdempotencyRecord) error
+	// Release frees key after a failed request so the client can retry with it.
+	Release(ctx context.Context, key string) error
+}
+
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"` // hash of the request the key was first used with
+	OrderID     string `json:"order_id"`    // empty while the first request is still running
+}
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
@@ -3,9 +3,11 @@ package command
 import (
 	"context"
 
+	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/decorator"
 	"github.com/peiyouyao/gorder/common/metrics"
 	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,20 +19,36 @@ type UpdateOrder struct {
 type UpdateOrderHandler decorator.CommandHandler[UpdateOrder, interface{}]
 
 type updateOrderHandler struct {
-	orderRepo domain.Repository
-	// stockGRPC
+	orderRepo    domain.Repository
+	sagaRepo     saga.Repository
+	sagaTimeouts saga.Timeouts
+	watcher      domain.Watcher
 }
 
 func NewUpdateOrderHandler(
 	orderRepo domain.Repository,
+	sagaRepo saga.Repository,
+	sagaTimeouts saga.Timeouts,
+	watcher domain.Watcher,
 	logger *logrus.Entry,
 	metricsClient metrics.MetricsClient,
 ) UpdateOrderHandler {
 	if orderRepo == nil {
 		panic("nil orderRepo")
 	}
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
 	return decorator.ApplyCommandDecorators[UpdateOrder, interface{}](
-		updateOrderHandler{orderRepo: orderRepo},
+		updateOrderHandler{
+			orderRepo:    orderRepo,
+			sagaRepo:     sagaRepo,
+			sagaTimeouts: sagaTimeouts,
+			watcher:      watcher,
+		},
 		logger,
 		metricsClient,
 	)
@@ -43,11 +61,32 @@ func (u updateOrderHandler) Handle(ctx context.Context, cmd UpdateOrder) (interf
 			return o, nil
 		}
 	}
+	var updated *domain.Order
+	updateFn := cmd.UpdateFn
+	cmd.UpdateFn = func(ctx context.Context, o *domain.Order) (res *domain.Order, err error) {
+		res, err = updateFn(ctx, o)
+		updated = res
+		return
+	}
 	logrus.Tracef("orderRepo.Update start order=%v", *cmd.Order)
 	if err := u.orderRepo.Update(ctx, cmd.Order, cmd.UpdateFn); err != nil {
 		logrus.Tracef("orderRepo.Update fail err=%v", err)
 		return nil, err
 	}
 	logrus.Trace("orderRepo.Update ok")
+	u.watcher.Notify(cmd.Order.ID)
+
+	// best effort, CompensateSagas catches a lagging saga up with its order before compensating
+	err := advanceSaga(ctx, u.sagaRepo, u.sagaTimeouts, updated)
+	if err == nil && updated.Status == constants.OrderStatusRefunded {
+		// 全额退款后订单的 saga 不再需要人关注, 比如被厨房拒绝的订单
+		err = endSaga(ctx, u.sagaRepo, updated.ID, "payment refunded")
+	}
+	if err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"order_id": updated.ID,
+			"err":      err.Error(),
+		}).Warn("Advance saga fail")
+	}
 	return nil, nil
 }
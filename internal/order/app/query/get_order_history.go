@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+)
+
+type GetOrderHistory struct {
+	CustomerID string
+	OrderID    string
+}
+
+type GetOrderHistoryHandler decorator.QueryHandler[GetOrderHistory, []*domain.HistoryEntry]
+
+type getOrderHistoryHandler struct {
+	orderRepo domain.Repository
+}
+
+func NewGetOrderHistoryHandler(
+	orderRepo domain.Repository,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) GetOrderHistoryHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	return decorator.ApplyQueryDecorators[GetOrderHistory, []*domain.HistoryEntry](
+		getOrderHistoryHandler{orderRepo: orderRepo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (g getOrderHistoryHandler) Handle(ctx context.Context, query GetOrderHistory) ([]*domain.HistoryEntry, error) {
+	return g.orderRepo.History(ctx, query.OrderID, query.CustomerID)
+}
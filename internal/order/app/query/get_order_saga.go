@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+)
+
+type GetOrderSaga struct {
+	CustomerID string
+	OrderID    string
+}
+
+type GetOrderSagaHandler decorator.QueryHandler[GetOrderSaga, *saga.Saga]
+
+type getOrderSagaHandler struct {
+	sagaRepo saga.Repository
+}
+
+func NewGetOrderSagaHandler(
+	sagaRepo saga.Repository,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) GetOrderSagaHandler {
+	if sagaRepo == nil {
+		panic("nil sagaRepo")
+	}
+	return decorator.ApplyQueryDecorators[GetOrderSaga, *saga.Saga](
+		getOrderSagaHandler{sagaRepo: sagaRepo},
+		logger,
+		metricsClient,
+	)
+}
+
+// 其他用户的 saga 和不存在的一样返回 NotFoundError
+func (g getOrderSagaHandler) Handle(ctx context.Context, query GetOrderSaga) (*saga.Saga, error) {
+	s, err := g.sagaRepo.Get(ctx, query.OrderID)
+	if errors.As(err, &saga.NotFoundError{}) {
+		return nil, domain.NotFoundError{OrderID: query.OrderID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.CustomerID != query.CustomerID {
+		return nil, domain.NotFoundError{OrderID: query.OrderID}
+	}
+	return s, nil
+}
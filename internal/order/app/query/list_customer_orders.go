@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+type ListCustomerOrders struct {
+	CustomerID    string
+	Statuses      []string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Cursor        string
+	Limit         int
+}
+
+type ListCustomerOrdersHandler decorator.QueryHandler[ListCustomerOrders, *domain.OrderPage]
+
+type listCustomerOrdersHandler struct {
+	orderRepo domain.Repository
+}
+
+func NewListCustomerOrdersHandler(
+	orderRepo domain.Repository,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) ListCustomerOrdersHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	return decorator.ApplyQueryDecorators[ListCustomerOrders, *domain.OrderPage](
+		listCustomerOrdersHandler{orderRepo: orderRepo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (l listCustomerOrdersHandler) Handle(
+	ctx context.Context,
+	query ListCustomerOrders,
+) (*domain.OrderPage, error) {
+	if query.CustomerID == "" {
+		return nil, errors.New("empty customerID")
+	}
+	limit := query.Limit
+	if limit < 0 {
+		return nil, domain.InvalidLimitError{Limit: limit}
+	}
+	// 0 是没有传
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	return l.orderRepo.List(ctx, domain.ListFilter{
+		CustomerID:    query.CustomerID,
+		Statuses:      query.Statuses,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		Cursor:        query.Cursor,
+		Limit:         limit,
+	})
+}
@@ -0,0 +1,44 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app/query"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCustomerOrders_Limit(t *testing.T) {
+	ctx := context.Background()
+	repo := adapters.NewOrderRepositoryInmem()
+	handler := query.NewListCustomerOrdersHandler(repo, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	for range 3 {
+		pending, err := domain.NewPendingOrder("customer-1", []*entity.Item{{ID: "item-1", Quantity: 1}})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, pending)
+		require.NoError(t, err)
+	}
+
+	// 没传 limit 用默认值, 一页就能放下
+	page, err := handler.Handle(ctx, query.ListCustomerOrders{CustomerID: "customer-1"})
+	require.NoError(t, err)
+	assert.Len(t, page.Orders, 3)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = handler.Handle(ctx, query.ListCustomerOrders{CustomerID: "customer-1", Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Orders, 2)
+	assert.Equal(t, page.Orders[1].ID, page.NextCursor)
+
+	_, err = handler.Handle(ctx, query.ListCustomerOrders{CustomerID: "customer-1", Limit: -1})
+	assert.ErrorAs(t, err, &domain.InvalidLimitError{})
+
+	_, err = repo.List(ctx, domain.ListFilter{CustomerID: "customer-1"})
+	assert.ErrorAs(t, err, &domain.InvalidLimitError{})
+}
@@ -10,4 +10,7 @@ import (
 type StockService interface {
 	CheckIfItemsInStock(ctx context.Context, items []*orderpb.ItemWithQuantity) (*stockpb.CheckIfItemsInStockResponse, error)
 	GetItems(ctx context.Context, itemsIDs []string) ([]*orderpb.Item, error)
+	ReserveItems(ctx context.Context, orderID string, items []*orderpb.ItemWithQuantity) error
+	CommitReservation(ctx context.Context, orderID string) error
+	ReleaseReservation(ctx context.Context, orderID string) error
 }
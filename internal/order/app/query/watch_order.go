@@ -0,0 +1,102 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+)
+
+type WatchOrder struct {
+	CustomerID string
+	OrderID    string
+	// Send gets the order now, then again each time its status, payment link or estimated ready time changes
+	Send func(*domain.Order) error `json:"-"`
+	// KeepAlive is optional, called on each resync that found no change
+	KeepAlive func() error `json:"-"`
+}
+
+// WatchOrderHandler returns once the order is final, the context is done or Send fails
+type WatchOrderHandler decorator.QueryHandler[WatchOrder, interface{}]
+
+type watchOrderHandler struct {
+	orderRepo domain.Repository
+	watcher   domain.Watcher
+	resync    time.Duration
+}
+
+func NewWatchOrderHandler(
+	orderRepo domain.Repository,
+	watcher domain.Watcher,
+	resync time.Duration,
+	logger *logrus.Entry,
+	metricsClient metrics.MetricsClient,
+) WatchOrderHandler {
+	if orderRepo == nil {
+		panic("nil orderRepo")
+	}
+	if watcher == nil {
+		panic("nil watcher")
+	}
+	if resync <= 0 {
+		panic("non-positive resync")
+	}
+	return decorator.ApplyQueryDecorators[WatchOrder, interface{}](
+		watchOrderHandler{orderRepo: orderRepo, watcher: watcher, resync: resync},
+		logger,
+		metricsClient,
+	)
+}
+
+func (w watchOrderHandler) Handle(ctx context.Context, query WatchOrder) (interface{}, error) {
+	// 先订阅再读, 读和订阅之间的更新不会丢
+	changed, stop := w.watcher.Watch(query.OrderID)
+	defer stop()
+	ticker := time.NewTicker(w.resync)
+	defer ticker.Stop()
+
+	var last *domain.Order
+	for {
+		o, err := w.orderRepo.Get(ctx, query.OrderID, query.CustomerID)
+		if err != nil {
+			if last != nil && ctx.Err() != nil {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if last == nil || o.Status != last.Status || o.PaymentLink != last.PaymentLink || !o.EstimatedReadyAt.Equal(last.EstimatedReadyAt) {
+			if err = query.Send(o); err != nil {
+				return nil, err
+			}
+			last = o
+		} else if query.KeepAlive != nil {
+			if err = query.KeepAlive(); err != nil {
+				return nil, err
+			}
+		}
+		if isFinal(o.Status) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// the customer is done with the order, a late refund is not streamed
+func isFinal(status string) bool {
+	switch status {
+	case constants.OrderStatusReady, constants.OrderStatusCancelled, constants.OrderStatusExpired, constants.OrderStatusRejected,
+		constants.OrderStatusPartiallyRefunded, constants.OrderStatusRefunded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app/command"
+	"github.com/peiyouyao/gorder/order/app/query"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/domain/saga"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchOrder(t *testing.T) {
+	for name, statuses := range map[string][]string{
+		"ready": {
+			constants.OrderStatusWaitingForPayment,
+			constants.OrderStatusPaid,
+			constants.OrderStatusReady,
+		},
+		// 没有支付就取消的订单不会再变化
+		"cancelled unpaid": {
+			constants.OrderStatusWaitingForPayment,
+			constants.OrderStatusCancelled,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			testWatchOrder(t, statuses)
+		})
+	}
+}
+
+// testWatchOrder walks a pending order through statuses, the stream must end after the last one
+func testWatchOrder(t *testing.T, statuses []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	repo := adapters.NewOrderRepositoryInmem()
+	watcher := adapters.NewOrderWatcherInmem()
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	handler := query.NewWatchOrderHandler(repo, watcher, time.Hour, logger, metrics.NoMetrics{})
+	update := command.NewUpdateOrderHandler(repo, adapters.NewSagaRepositoryInmem(), saga.Timeouts{}, watcher, logger, metrics.NoMetrics{})
+
+	pending, err := domain.NewPendingOrder("customer-1", []*entity.Item{{ID: "item-1", Quantity: 1}})
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, pending)
+	require.NoError(t, err)
+
+	sent := make(chan *domain.Order)
+	done := make(chan error)
+	go func() {
+		_, err := handler.Handle(ctx, query.WatchOrder{
+			CustomerID: "customer-1",
+			OrderID:    created.ID,
+			Send: func(o *domain.Order) error {
+				select {
+				case sent <- o:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		})
+		done <- err
+	}()
+	assert.Equal(t, constants.OrderStatusPending, (<-sent).Status)
+
+	for _, status := range statuses {
+		o := *created
+		o.Status = status
+		_, err = update.Handle(ctx, command.UpdateOrder{
+			Order: &o,
+			UpdateFn: func(_ context.Context, order *domain.Order) (*domain.Order, error) {
+				return order, nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, status, (<-sent).Status)
+	}
+	select {
+	case err = <-done:
+		assert.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("stream still open")
+	}
+}
+
+func TestWatchOrder_NotFound(t *testing.T) {
+	handler := query.NewWatchOrderHandler(adapters.NewOrderRepositoryInmem(), adapters.NewOrderWatcherInmem(), time.Hour, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+	_, err := handler.Handle(context.Background(), query.WatchOrder{
+		CustomerID: "customer-1",
+		OrderID:    "missing",
+		Send:       func(*domain.Order) error { return nil },
+	})
+	assert.ErrorAs(t, err, &domain.NotFoundError{})
+}
@@ -1,13 +1,39 @@
 package order
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type DomainEvent struct {
-	Dest string
-	Data any
+	ID         string // becomes the broker message id, empty lets the broker pick one
+	Dest       string
+	Data       any
+	OccurredAt time.Time // zero means now
 }
 
 type EventPublisher interface {
 	Publish(ctx context.Context, event DomainEvent) error
 	Broadcast(ctx context.Context, event DomainEvent) error
 }
+
+// OutboxMessage is a serialized DomainEvent waiting in the outbox to be relayed to the broker.
+type OutboxMessage struct {
+	ID        string
+	Dest      string
+	Broadcast bool
+	Body      []byte
+	Headers   map[string]any // tracing headers captured when the event was raised
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// Outbox stores events in the same transaction as the order changes that raised them,
+// a relay then delivers them to the broker.
+type Outbox interface {
+	Add(ctx context.Context, msgs ...*OutboxMessage) error
+	// ClaimPending hides the returned messages from other relays for lease.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*OutboxMessage, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error
+}
@@ -0,0 +1,129 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/peiyouyao/gorder/common/constants"
+)
+
+// 事件溯源仓库中的事件名
+const (
+	EventOrderCreated           = "OrderCreated"
+	EventPaymentLinkAttached    = "PaymentLinkAttached"
+	EventOrderAwaitingPayment   = "OrderAwaitingPayment"
+	EventOrderPaid              = "OrderPaid"
+	EventOrderCooking           = "OrderCooking"
+	EventOrderReady             = "OrderReady"
+	EventOrderCancelled         = "OrderCancelled"
+	EventOrderExpired           = "OrderExpired"
+	EventOrderRejected          = "OrderRejected"
+	EventOrderRefunded          = "OrderRefunded"
+	EventOrderPartiallyRefunded = "OrderPartiallyRefunded"
+	EventReadyTimeEstimated     = "ReadyTimeEstimated"
+	// a status without its own event
+	EventOrderStatusChanged = "OrderStatusChanged"
+)
+
+// Event is a HistoryEntry as the event-sourced repository stores it.
+type Event struct {
+	HistoryEntry
+	Name     string
+	Position string // where the event is in the stream of all orders, see EventStream
+}
+
+// EventName names the event a history entry is stored as.
+func EventName(e *HistoryEntry) string {
+	switch e.Type {
+	case ChangeCreated:
+		return EventOrderCreated
+	case ChangePaymentLink:
+		return EventPaymentLinkAttached
+	case ChangeEstimatedReadyAt:
+		return EventReadyTimeEstimated
+	}
+	switch e.New {
+	case constants.OrderStatusWaitingForPayment:
+		return EventOrderAwaitingPayment
+	case constants.OrderStatusPaid:
+		return EventOrderPaid
+	case constants.OrderStatusCooking:
+		return EventOrderCooking
+	case constants.OrderStatusReady:
+		return EventOrderReady
+	case constants.OrderStatusCancelled:
+		return EventOrderCancelled
+	case constants.OrderStatusExpired:
+		return EventOrderExpired
+	case constants.OrderStatusRejected:
+		return EventOrderRejected
+	case constants.OrderStatusRefunded:
+		return EventOrderRefunded
+	case constants.OrderStatusPartiallyRefunded:
+		return EventOrderPartiallyRefunded
+	}
+	return EventOrderStatusChanged
+}
+
+// Snapshot is an order as it was after Version events.
+type Snapshot struct {
+	Order   *Order
+	Version int64
+}
+
+/*
+Replay 从 from (nil 表示从头开始) 依次应用 entries, 重建订单.
+entries 必须紧接着 from 的版本且连续; 事件是已经发生的事实, 不再校验状态转换.
+*/
+func Replay(from *Snapshot, entries []*HistoryEntry) (*Snapshot, error) {
+	s := &Snapshot{}
+	if from != nil {
+		o := *from.Order
+		o.Items = slices.Clone(from.Order.Items)
+		o.changes = nil
+		s = &Snapshot{Order: &o, Version: from.Version}
+	}
+	for _, e := range entries {
+		if e.Version != s.Version+1 {
+			return nil, fmt.Errorf("order %s: event version %d after version %d", e.OrderID, e.Version, s.Version)
+		}
+		switch {
+		case e.Type == ChangeCreated:
+			if s.Order != nil {
+				return nil, fmt.Errorf("order %s: created twice", e.OrderID)
+			}
+			s.Order = &Order{
+				ID:         e.OrderID,
+				CustomerID: e.CustomerID,
+				Status:     e.New,
+				Items:      slices.Clone(e.Items),
+				CreatedAt:  e.At,
+			}
+		case s.Order == nil:
+			return nil, fmt.Errorf("order %s: %s before created", e.OrderID, e.Type)
+		case e.Type == ChangeStatus:
+			s.Order.Status = e.New
+			s.Order.stamp(e.At)
+		case e.Type == ChangePaymentLink:
+			s.Order.PaymentLink = e.New
+		case e.Type == ChangeEstimatedReadyAt:
+			at, err := parseTime(e.New)
+			if err != nil {
+				return nil, fmt.Errorf("order %s: %w", e.OrderID, err)
+			}
+			s.Order.EstimatedReadyAt = at
+		default:
+			return nil, fmt.Errorf("order %s: unknown change %q", e.OrderID, e.Type)
+		}
+		s.Version = e.Version
+	}
+	return s, nil
+}
+
+// EventStream is the events of all orders in the order they were stored, projections follow it.
+type EventStream interface {
+	// ReadEvents returns at most limit events after position, "" starts from the first event.
+	// Continue with the Position of the last event returned.
+	ReadEvents(ctx context.Context, after string, limit int) ([]*Event, error)
+}
@@ -0,0 +1,78 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEntries() []*HistoryEntry {
+	return []*HistoryEntry{
+		{OrderID: "order-1", CustomerID: "customer-1", Version: 1, Type: ChangeCreated, New: constants.OrderStatusPending, Items: []*entity.Item{{ID: "item-1", Quantity: 1}}},
+		{OrderID: "order-1", Version: 2, Type: ChangeStatus, Old: constants.OrderStatusPending, New: constants.OrderStatusWaitingForPayment},
+		{OrderID: "order-1", Version: 3, Type: ChangePaymentLink, New: "https://pay.example/1"},
+		{OrderID: "order-1", Version: 4, Type: ChangeStatus, Old: constants.OrderStatusWaitingForPayment, New: constants.OrderStatusPaid},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	entries := testEntries()
+	s, err := Replay(nil, entries)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), s.Version)
+	assert.Equal(t, "customer-1", s.Order.CustomerID)
+	assert.Equal(t, constants.OrderStatusPaid, s.Order.Status)
+	assert.Equal(t, "https://pay.example/1", s.Order.PaymentLink)
+	assert.Len(t, s.Order.Items, 1)
+
+	// resuming from a snapshot gives the same order and leaves the snapshot alone
+	snap, err := Replay(nil, entries[:2])
+	require.NoError(t, err)
+	resumed, err := Replay(snap, entries[2:])
+	require.NoError(t, err)
+	assert.Equal(t, s, resumed)
+	assert.Equal(t, constants.OrderStatusWaitingForPayment, snap.Order.Status)
+
+	assert.Equal(t, []string{EventOrderCreated, EventOrderAwaitingPayment, EventPaymentLinkAttached, EventOrderPaid},
+		[]string{EventName(entries[0]), EventName(entries[1]), EventName(entries[2]), EventName(entries[3])})
+}
+
+func TestReplay_Gap(t *testing.T) {
+	entries := testEntries()
+	_, err := Replay(nil, append(entries[:1], entries[2:]...))
+	assert.Error(t, err)
+
+	_, err = Replay(nil, entries[1:])
+	assert.Error(t, err)
+}
+
+func TestReplay_Times(t *testing.T) {
+	o, err := NewPendingOrder("customer-1", []*entity.Item{{ID: "item-1", Quantity: 1}})
+	require.NoError(t, err)
+	o.ID = "order-1"
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusWaitingForPayment))
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusPaid))
+	eta := time.Now().Add(10 * time.Minute)
+	require.NoError(t, o.UpdateEstimatedReadyAt(eta))
+	require.NoError(t, o.UpdateEstimatedReadyAt(eta), "the same estimate is not a change")
+
+	entries := NewHistory(o, 0, append([]Change{CreatedChange(o)}, o.Changes()...), "test", "")
+	require.Len(t, entries, 4)
+	assert.Equal(t, EventReadyTimeEstimated, EventName(entries[3]))
+	s, err := Replay(nil, entries)
+	require.NoError(t, err)
+	assert.True(t, s.Order.CreatedAt.Equal(o.CreatedAt))
+	assert.True(t, s.Order.PaidAt.Equal(o.PaidAt))
+	assert.True(t, s.Order.EstimatedReadyAt.Equal(eta))
+	assert.True(t, s.Order.ReadyAt.IsZero())
+
+	// a late estimate for a ready order is dropped
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusReady))
+	assert.False(t, o.ReadyAt.IsZero())
+	require.NoError(t, o.UpdateEstimatedReadyAt(eta.Add(time.Minute)))
+	assert.True(t, o.EstimatedReadyAt.Equal(eta))
+}
@@ -0,0 +1,90 @@
+package order
+
+import (
+	"time"
+
+	"github.com/peiyouyao/gorder/common/entity"
+)
+
+const (
+	ChangeCreated     = "created"
+	ChangeStatus      = "status_changed"
+	ChangePaymentLink = "payment_link_changed"
+	// Old and New are RFC 3339 times, Old is empty for the first estimate
+	ChangeEstimatedReadyAt = "estimated_ready_at_changed"
+)
+
+// Change is one field of an order changing, recorded by the Order method that changed it.
+type Change struct {
+	Type string
+	Old  string
+	New  string
+	At   time.Time
+}
+
+/*
+HistoryEntry 是保存下来的 Change, 只追加不修改.
+Version 在一个订单内从 1 (created) 开始连续递增, 按 Version 重放所有 entry 可以重建订单.
+*/
+type HistoryEntry struct {
+	OrderID    string
+	CustomerID string
+	Version    int64
+	Type       string
+	Old        string
+	New        string
+	Items      []*entity.Item // only on ChangeCreated
+	At         time.Time
+	Actor      string // service that made the change
+	TraceID    string
+}
+
+// NewHistory turns the changes into entries numbered after the lastVersion already stored.
+func NewHistory(o *Order, lastVersion int64, changes []Change, actor, traceID string) []*HistoryEntry {
+	entries := make([]*HistoryEntry, 0, len(changes))
+	for i, c := range changes {
+		e := &HistoryEntry{
+			OrderID:    o.ID,
+			CustomerID: o.CustomerID,
+			Version:    lastVersion + int64(i) + 1,
+			Type:       c.Type,
+			Old:        c.Old,
+			New:        c.New,
+			At:         c.At,
+			Actor:      actor,
+			TraceID:    traceID,
+		}
+		if c.Type == ChangeCreated {
+			e.Items = o.Items
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// CreatedChange is the first history entry of o.
+func CreatedChange(o *Order) Change {
+	at := o.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return Change{
+		Type: ChangeCreated,
+		New:  o.Status,
+		At:   at,
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/entity"
@@ -16,6 +17,13 @@ type Order struct {
 	Status      string
 	PaymentLink string
 	Items       []*entity.Item
+	// 零值表示还没有, PaidAt 和 ReadyAt 由 UpdateStatus 记录
+	CreatedAt        time.Time
+	PaidAt           time.Time
+	EstimatedReadyAt time.Time // estimated by the kitchen
+	ReadyAt          time.Time
+
+	changes []Change // made since the order was loaded, the repository saves them to the history
 }
 
 func NewOrder(id, customerID, status, paymentLink string, items []*entity.Item) (*Order, error) {
@@ -55,6 +63,7 @@ func NewPendingOrder(customerID string, items []*entity.Item) (*Order, error) {
 		Status:      constants.OrderStatusPending,
 		Items:       items,
 		PaymentLink: "",
+		CreatedAt:   time.Now(),
 	}, nil
 }
 
@@ -69,6 +78,9 @@ func (o *Order) UpdatePaymentLink(link string) error {
 	if link == "" {
 		return errors.New("cannot update empty paymentLink")
 	}
+	if link != o.PaymentLink {
+		o.record(ChangePaymentLink, o.PaymentLink, link)
+	}
 	o.PaymentLink = link
 	return nil
 }
@@ -78,23 +90,145 @@ func (o *Order) UpdateItems(items []*entity.Item) error {
 	return nil
 }
 
+// UpdateStatus records the transition, updating to the current status is a no-op so redelivered updates pass.
 func (o *Order) UpdateStatus(to string) error {
+	if to == o.Status || o.passed(to) {
+		return nil
+	}
 	if !o.isValidStatusTransition(to) {
-		return fmt.Errorf("cannot transit from '%s' to '%s'", o.Status, to)
+		return InvalidTransitionError{From: o.Status, To: to}
 	}
+	at := o.record(ChangeStatus, o.Status, to)
 	o.Status = to
+	o.stamp(at)
+	return nil
+}
+
+// UpdateEstimatedReadyAt takes the kitchen's estimate, it is dropped once the order left the kitchen.
+func (o *Order) UpdateEstimatedReadyAt(at time.Time) error {
+	if at.IsZero() {
+		return errors.New("cannot update empty estimatedReadyAt")
+	}
+	if o.Status != constants.OrderStatusPaid && o.Status != constants.OrderStatusCooking {
+		return nil
+	}
+	if !at.Equal(o.EstimatedReadyAt) {
+		o.record(ChangeEstimatedReadyAt, formatTime(o.EstimatedReadyAt), formatTime(at))
+	}
+	o.EstimatedReadyAt = at
 	return nil
 }
 
+// stamp keeps when the order got to its status
+func (o *Order) stamp(at time.Time) {
+	switch o.Status {
+	case constants.OrderStatusPaid:
+		o.PaidAt = at
+	case constants.OrderStatusReady:
+		o.ReadyAt = at
+	}
+}
+
+// Apply takes the status, payment link and estimated ready time of updated through
+// UpdateStatus, UpdatePaymentLink and UpdateEstimatedReadyAt,
+// repositories call it on the stored order with what the update function returned.
+func (o *Order) Apply(updated *Order) error {
+	if err := o.UpdateStatus(updated.Status); err != nil {
+		return err
+	}
+	if updated.PaymentLink != "" {
+		if err := o.UpdatePaymentLink(updated.PaymentLink); err != nil {
+			return err
+		}
+	}
+	if !updated.EstimatedReadyAt.IsZero() {
+		return o.UpdateEstimatedReadyAt(updated.EstimatedReadyAt)
+	}
+	return nil
+}
+
+// Entity is the order as other services see it.
+func (o *Order) Entity() *entity.Order {
+	return &entity.Order{
+		ID:               o.ID,
+		CustomerID:       o.CustomerID,
+		Status:           o.Status,
+		PaymentLink:      o.PaymentLink,
+		Items:            o.Items,
+		CreatedAt:        o.CreatedAt,
+		PaidAt:           o.PaidAt,
+		EstimatedReadyAt: o.EstimatedReadyAt,
+		ReadyAt:          o.ReadyAt,
+	}
+}
+
+// Changes returns what changed since the order was loaded, oldest first.
+func (o *Order) Changes() []Change {
+	return o.changes
+}
+
+func (o *Order) record(typ, from, to string) time.Time {
+	at := time.Now()
+	o.changes = append(o.changes, Change{
+		Type: typ,
+		Old:  from,
+		New:  to,
+		At:   at,
+	})
+	return at
+}
+
+// Cancel is only allowed before the order is paid.
+func (o *Order) Cancel() error {
+	return o.UpdateStatus(constants.OrderStatusCancelled)
+}
+
+// Reject is for the kitchen, only a paid order that is not ready yet can be rejected.
+func (o *Order) Reject() error {
+	return o.UpdateStatus(constants.OrderStatusRejected)
+}
+
+// Expire releases an order that was never paid within the payment ttl.
+func (o *Order) Expire() error {
+	return o.UpdateStatus(constants.OrderStatusExpired)
+}
+
 func (o *Order) isValidStatusTransition(to string) bool {
 	switch o.Status {
 	default:
 		return false
 	case constants.OrderStatusPending:
-		return slices.Contains([]string{constants.OrderStatusWaitingForPayment}, to)
+		return slices.Contains([]string{constants.OrderStatusWaitingForPayment, constants.OrderStatusCancelled, constants.OrderStatusExpired}, to)
 	case constants.OrderStatusWaitingForPayment:
-		return slices.Contains([]string{constants.OrderStatusPaid}, to)
+		return slices.Contains([]string{constants.OrderStatusPaid, constants.OrderStatusCancelled, constants.OrderStatusExpired}, to)
+	case constants.OrderStatusPaid:
+		return slices.Contains([]string{constants.OrderStatusCooking, constants.OrderStatusReady, constants.OrderStatusRejected}, to) || isRefund(to)
+	case constants.OrderStatusCooking:
+		return slices.Contains([]string{constants.OrderStatusReady, constants.OrderStatusRejected}, to) || isRefund(to)
+	case constants.OrderStatusReady, constants.OrderStatusRejected:
+		return isRefund(to)
+	// 订单关闭后才到的支付会被退款
+	case constants.OrderStatusCancelled, constants.OrderStatusExpired:
+		return isRefund(to)
+	case constants.OrderStatusPartiallyRefunded:
+		return to == constants.OrderStatusRefunded
+	}
+}
+
+func isRefund(status string) bool {
+	return status == constants.OrderStatusRefunded || status == constants.OrderStatusPartiallyRefunded
+}
+
+/*
+passed 表示订单已经越过了 to, 迟到的更新直接忽略:
+全额退款后才到的部分退款, 退款后重投的 order.paid, 以及订单取消或过期后才到的 order.paid (payment 会退款).
+*/
+func (o *Order) passed(to string) bool {
+	switch to {
+	case constants.OrderStatusPartiallyRefunded:
+		return o.Status == constants.OrderStatusRefunded
 	case constants.OrderStatusPaid:
-		return slices.Contains([]string{constants.OrderStatusReady}, to)
+		return isRefund(o.Status) || o.Status == constants.OrderStatusCancelled || o.Status == constants.OrderStatusExpired
 	}
+	return false
 }
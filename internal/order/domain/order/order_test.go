@@ -0,0 +1,39 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateStatus_Refund(t *testing.T) {
+	newOrder := func(status string) *Order {
+		o, err := NewOrder("order-1", "customer-1", status, "https://pay.example/1", []*entity.Item{{ID: "item-1", Quantity: 1}})
+		require.NoError(t, err)
+		return o
+	}
+
+	o := newOrder(constants.OrderStatusRejected)
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusPartiallyRefunded))
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusRefunded))
+	require.Len(t, o.Changes(), 2)
+	assert.Equal(t, EventOrderPartiallyRefunded, EventName(&HistoryEntry{Type: ChangeStatus, New: o.Changes()[0].New}))
+
+	// late updates are dropped
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusPartiallyRefunded))
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusPaid))
+	assert.Equal(t, constants.OrderStatusRefunded, o.Status)
+	assert.Len(t, o.Changes(), 2)
+	assert.Error(t, o.UpdateStatus(constants.OrderStatusCooking))
+
+	// a payment that arrives after the order expired is refunded, the order is never paid
+	o = newOrder(constants.OrderStatusExpired)
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusPaid))
+	assert.Equal(t, constants.OrderStatusExpired, o.Status)
+	require.NoError(t, o.UpdateStatus(constants.OrderStatusRefunded))
+
+	assert.Error(t, newOrder(constants.OrderStatusWaitingForPayment).UpdateStatus(constants.OrderStatusRefunded))
+}
@@ -3,16 +3,48 @@ package order
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 type Repository interface {
 	Create(ctx context.Context, order *Order) (*Order, error)
 	Get(ctx context.Context, id, customerID string) (*Order, error)
+	// Update calls updateFn with order and applies the returned status and payment link
+	// to the stored order with Order.Apply, so invalid transitions fail.
+	// It fails with a ConflictError when the stored order changed in between.
 	Update(
 		ctx context.Context,
 		order *Order,
 		updateFn func(context.Context, *Order) (*Order, error),
 	) error
+	// FindUnpaid returns at most limit orders created before createdBefore that are still waiting to be paid.
+	FindUnpaid(ctx context.Context, createdBefore time.Time, limit int) ([]*Order, error)
+	// List returns one page of a customer's orders, newest first.
+	List(ctx context.Context, filter ListFilter) (*OrderPage, error)
+	// History returns every change of the order, oldest first.
+	// Create and Update append to it, the actor and trace id are taken from ctx.
+	History(ctx context.Context, id, customerID string) ([]*HistoryEntry, error)
+}
+
+// ListFilter narrows List, zero values mean no restriction.
+type ListFilter struct {
+	CustomerID    string
+	Statuses      []string
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+	Cursor        string    // OrderPage.NextCursor of the previous page
+	Limit         int       // must be positive
+}
+
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor string // empty on the last page
+}
+
+// Transactor runs fn in one storage transaction,
+// every Repository and Outbox call made with the ctx passed to fn commits or rolls back together.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type NotFoundError struct {
@@ -22,3 +54,36 @@ type NotFoundError struct {
 func (e NotFoundError) Error() string {
 	return fmt.Sprintf("order %s not found", e.OrderID)
 }
+
+type ConflictError struct {
+	OrderID string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("order %s changed concurrently", e.OrderID)
+}
+
+// InvalidTransitionError means the order is past the status the update wants, like paying a cancelled order.
+type InvalidTransitionError struct {
+	From, To string
+}
+
+func (e InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot transit from '%s' to '%s'", e.From, e.To)
+}
+
+type InvalidCursorError struct {
+	Cursor string
+}
+
+func (e InvalidCursorError) Error() string {
+	return fmt.Sprintf("invalid cursor %q", e.Cursor)
+}
+
+type InvalidLimitError struct {
+	Limit int
+}
+
+func (e InvalidLimitError) Error() string {
+	return fmt.Sprintf("invalid limit %d", e.Limit)
+}
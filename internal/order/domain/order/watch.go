@@ -0,0 +1,9 @@
+package order
+
+// Watcher wakes up whoever watches an order after it changed, they read the order again themselves.
+type Watcher interface {
+	Notify(orderID string)
+	// Watch returns a channel that receives after each Notify for orderID, notifications that arrive
+	// before the last one was received are merged. stop must be called once done watching.
+	Watch(orderID string) (changed <-chan struct{}, stop func())
+}
@@ -0,0 +1,34 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, s *Saga) error
+	Get(ctx context.Context, orderID string) (*Saga, error)
+	// Update loads the saga, lets updateFn change it and saves it,
+	// it fails with a ConflictError when the saga changed in between.
+	Update(ctx context.Context, orderID string, updateFn func(context.Context, *Saga) error) error
+	// FindTimedOut returns at most limit running sagas whose step deadline is before now,
+	// and sagas left in StatusCompensating.
+	FindTimedOut(ctx context.Context, now time.Time, limit int) ([]*Saga, error)
+}
+
+type NotFoundError struct {
+	OrderID string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("saga of order %s not found", e.OrderID)
+}
+
+type ConflictError struct {
+	OrderID string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("saga of order %s changed concurrently", e.OrderID)
+}
@@ -0,0 +1,178 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+)
+
+type Step string
+
+const (
+	StepReserveStock      Step = "reserve_stock"
+	StepCreatePaymentLink Step = "create_payment_link"
+	StepAwaitPayment      Step = "await_payment"
+	StepCook              Step = "cook"
+	StepReady             Step = "ready"
+)
+
+// Steps in the order an order goes through them.
+var Steps = []Step{StepReserveStock, StepCreatePaymentLink, StepAwaitPayment, StepCook, StepReady}
+
+type Status string
+
+const (
+	StatusRunning      Status = "running"
+	StatusCompleted    Status = "completed"
+	StatusCompensating Status = "compensating"
+	StatusCompensated  Status = "compensated"
+	// StatusFailed needs a person, the step timed out and could not be compensated.
+	StatusFailed Status = "failed"
+)
+
+// 历史记录中的动作
+const (
+	ActionStarted     = "started"
+	ActionDone        = "done"
+	ActionTimedOut    = "timed_out"
+	ActionCompensated = "compensated"
+	ActionFailed      = "failed"
+)
+
+type Record struct {
+	Step   Step
+	Action string
+	At     time.Time
+	Reason string
+}
+
+// Timeouts is how long each step may take, steps missing from it never time out.
+type Timeouts map[Step]time.Duration
+
+/*
+Saga 记录一个订单在 reserve_stock -> create_payment_link -> await_payment -> cook -> ready 中走到了哪一步.
+每一步开始时按 Timeouts 设置 Deadline, 超时后由 CompensateSagas 执行这一步的补偿.
+*/
+type Saga struct {
+	OrderID    string
+	CustomerID string
+	Step       Step
+	Status     Status
+	Deadline   time.Time // zero when the saga is not running or the step has no timeout
+	History    []Record
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+var ErrNotRunning = errors.New("saga not running")
+
+// New starts a saga at StepReserveStock.
+func New(orderID, customerID string, now time.Time, timeouts Timeouts) (*Saga, error) {
+	if orderID == "" {
+		return nil, errors.New("empty orderID")
+	}
+	if customerID == "" {
+		return nil, errors.New("empty customerID")
+	}
+	s := &Saga{
+		OrderID:    orderID,
+		CustomerID: customerID,
+		Status:     StatusRunning,
+		CreatedAt:  now,
+	}
+	s.start(StepReserveStock, now, timeouts)
+	return s, nil
+}
+
+// StepOf is the step an order in status is at, false for statuses that end the saga early.
+func StepOf(status string) (Step, bool) {
+	switch status {
+	case constants.OrderStatusPending:
+		return StepCreatePaymentLink, true
+	case constants.OrderStatusWaitingForPayment:
+		return StepAwaitPayment, true
+	case constants.OrderStatusPaid, constants.OrderStatusCooking:
+		return StepCook, true
+	case constants.OrderStatusReady:
+		return StepReady, true
+	}
+	return "", false
+}
+
+// Advance finishes the current step and starts to, a step at or before the current one is ignored
+// so that redelivered updates do nothing. Reaching StepReady completes the saga.
+func (s *Saga) Advance(to Step, now time.Time, timeouts Timeouts) error {
+	if !slices.Contains(Steps, to) {
+		return fmt.Errorf("unknown saga step %q", to)
+	}
+	if slices.Index(Steps, to) <= slices.Index(Steps, s.Step) {
+		return nil
+	}
+	if s.Status != StatusRunning {
+		return fmt.Errorf("%w: order_id=%s status=%s", ErrNotRunning, s.OrderID, s.Status)
+	}
+	s.record(ActionDone, now, "")
+	s.start(to, now, timeouts)
+	if to == StepReady {
+		s.record(ActionDone, now, "")
+		s.Status = StatusCompleted
+		s.Deadline = time.Time{}
+	}
+	return nil
+}
+
+func (s *Saga) TimedOut(now time.Time) bool {
+	return s.Status == StatusRunning && !s.Deadline.IsZero() && now.After(s.Deadline)
+}
+
+// Compensate marks the current step as timed out, the compensating action runs next.
+func (s *Saga) Compensate(reason string, now time.Time) error {
+	if s.Status != StatusRunning && s.Status != StatusCompensating {
+		return fmt.Errorf("%w: order_id=%s status=%s", ErrNotRunning, s.OrderID, s.Status)
+	}
+	if s.Status == StatusRunning {
+		s.record(ActionTimedOut, now, reason)
+		s.Status = StatusCompensating
+		s.Deadline = time.Time{}
+	}
+	return nil
+}
+
+// Compensated ends the saga once the compensating action is done,
+// it is also used when the order is cancelled or expired outside the saga.
+func (s *Saga) Compensated(reason string, now time.Time) {
+	if s.Status == StatusCompensated {
+		return
+	}
+	s.record(ActionCompensated, now, reason)
+	s.Status = StatusCompensated
+	s.Deadline = time.Time{}
+}
+
+func (s *Saga) Fail(reason string, now time.Time) {
+	s.record(ActionFailed, now, reason)
+	s.Status = StatusFailed
+	s.Deadline = time.Time{}
+}
+
+func (s *Saga) start(step Step, now time.Time, timeouts Timeouts) {
+	s.Step = step
+	s.Deadline = time.Time{}
+	if d, ok := timeouts[step]; ok && d > 0 {
+		s.Deadline = now.Add(d)
+	}
+	s.record(ActionStarted, now, "")
+}
+
+func (s *Saga) record(action string, now time.Time, reason string) {
+	s.History = append(s.History, Record{
+		Step:   s.Step,
+		Action: action,
+		At:     now,
+		Reason: reason,
+	})
+	s.UpdatedAt = now
+}
@@ -0,0 +1,64 @@
+package saga
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTimeouts = Timeouts{
+	StepCreatePaymentLink: time.Minute,
+	StepAwaitPayment:      time.Hour,
+}
+
+func TestSaga_HappyPath(t *testing.T) {
+	now := time.Now()
+	s, err := New("order-1", "customer-1", now, testTimeouts)
+	require.NoError(t, err)
+
+	for _, status := range []string{
+		constants.OrderStatusPending,
+		constants.OrderStatusWaitingForPayment,
+		constants.OrderStatusWaitingForPayment, // redelivered update
+		constants.OrderStatusPaid,
+		constants.OrderStatusReady,
+	} {
+		step, ok := StepOf(status)
+		require.True(t, ok)
+		require.NoError(t, s.Advance(step, now, testTimeouts))
+	}
+
+	assert.Equal(t, StepReady, s.Step)
+	assert.Equal(t, StatusCompleted, s.Status)
+	assert.True(t, s.Deadline.IsZero())
+	var started []Step
+	for _, r := range s.History {
+		if r.Action == ActionStarted {
+			started = append(started, r.Step)
+		}
+	}
+	assert.Equal(t, Steps, started)
+}
+
+func TestSaga_TimeoutThenCompensate(t *testing.T) {
+	now := time.Now()
+	s, err := New("order-1", "customer-1", now, testTimeouts)
+	require.NoError(t, err)
+	require.NoError(t, s.Advance(StepCreatePaymentLink, now, testTimeouts))
+
+	assert.False(t, s.TimedOut(now.Add(time.Second)))
+	assert.True(t, s.TimedOut(now.Add(2*time.Minute)))
+
+	require.NoError(t, s.Compensate("step create_payment_link timed out", now))
+	assert.Equal(t, StatusCompensating, s.Status)
+	assert.False(t, s.TimedOut(now.Add(2*time.Minute)))
+	s.Compensated("order expired", now)
+	assert.Equal(t, StatusCompensated, s.Status)
+
+	// a late payment update can not restart it
+	assert.ErrorIs(t, s.Advance(StepAwaitPayment, now, testTimeouts), ErrNotRunning)
+	assert.Equal(t, ActionCompensated, s.History[len(s.History)-1].Action)
+}
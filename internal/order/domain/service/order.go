@@ -11,11 +11,16 @@ import (
 
 type OrderDomainService struct {
 	Repo           domain.Repository
-	EventPublisher domain.EventPublisher
+	Transactor     domain.Transactor
+	EventPublisher domain.EventPublisher // backed by the outbox, see mq.OutboxEventPublisher
 }
 
-func NewOrderDomainService(repo domain.Repository, eventPublisher domain.EventPublisher) *OrderDomainService {
-	return &OrderDomainService{Repo: repo, EventPublisher: eventPublisher}
+func NewOrderDomainService(
+	repo domain.Repository,
+	transactor domain.Transactor,
+	eventPublisher domain.EventPublisher,
+) *OrderDomainService {
+	return &OrderDomainService{Repo: repo, Transactor: transactor, EventPublisher: eventPublisher}
 }
 
 func (s *OrderDomainService) CreateOrder(ctx context.Context, order domain.Order) (res *entity.Order, err error) {
@@ -23,23 +28,23 @@ func (s *OrderDomainService) CreateOrder(ctx context.Context, order domain.Order
 		domain.Identity{CustomerID: order.CustomerID, OrderID: order.ID},
 		&order,
 	)
-	o, err := s.Repo.Create(ctx, root.Order)
-	if err != nil {
-		return
-	}
 
-	if err = s.EventPublisher.Publish(ctx, domain.DomainEvent{
-		Dest: broker.EventOrderCreated,
-		Data: o,
-	}); err != nil {
-		return nil, errors.Wrapf(err, "publish event error||q.Name=%s", broker.EventOrderCreated)
+	var o *domain.Order
+	err = s.Transactor.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if o, err = s.Repo.Create(ctx, root.Order); err != nil {
+			return err
+		}
+		if err = s.EventPublisher.Publish(ctx, domain.DomainEvent{
+			Dest: broker.EventOrderCreated,
+			Data: o.Entity(),
+		}); err != nil {
+			return errors.Wrapf(err, "publish event error||q.Name=%s", broker.EventOrderCreated)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &entity.Order{
-		ID:          o.ID,
-		CustomerID:  o.CustomerID,
-		Status:      o.Status,
-		PaymentLink: o.PaymentLink,
-		Items:       o.Items,
-	}, nil
+	return o.Entity(), nil
 }
@@ -0,0 +1,57 @@
+package compensation
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/order/app"
+	"github.com/peiyouyao/gorder/order/app/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+定时扫描步骤超时的 saga, 执行补偿
+*/
+type Scheduler struct {
+	app       app.Application
+	interval  time.Duration
+	batchSize int
+}
+
+func NewScheduler(app app.Application) *Scheduler {
+	return &Scheduler{
+		app:       app,
+		interval:  viper.GetDuration("order.saga.scheduler.interval") * time.Second,
+		batchSize: viper.GetInt("order.saga.scheduler.batch-size"),
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	logrus.WithField("interval", s.interval).Info("Saga compensation scheduler started")
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Saga compensation scheduler stopped")
+			return
+		case <-ticker.C:
+			s.compensateTimedOut(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) compensateTimedOut(ctx context.Context) {
+	n, err := s.app.Commands.CompensateSagas.Handle(ctx, command.CompensateSagas{
+		Now:   time.Now(),
+		Limit: s.batchSize,
+	})
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("Compensate timed out sagas fail err=%v", err)
+		return
+	}
+	if n > 0 {
+		logrus.WithContext(ctx).Infof("Compensated %d timed out sagas", n)
+	}
+}
@@ -2,115 +2,105 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/convert"
 	"github.com/peiyouyao/gorder/order/app"
 	"github.com/peiyouyao/gorder/order/app/command"
 	domain "github.com/peiyouyao/gorder/order/domain/order"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 )
 
 /*
-消费 mq 中 order.paid 消息, 更新 order状态为 paid
+消费 mq 中 order.paid 消息, 更新 order状态为 paid, 并 commit 预占的库存
 */
 type Consumer struct {
-	app app.Application
+	app   app.Application
+	dedup *broker.Dedup
 }
 
 func NewConsumer(app app.Application) *Consumer {
 	return &Consumer{
-		app: app,
+		app:   app,
+		dedup: broker.NewDedup("order"),
 	}
 }
 
-func (c *Consumer) Listen(ch *amqp.Channel) {
-	q, err := ch.QueueDeclare(broker.EventOrderPaid, true, false, true, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-	err = ch.QueueBind(q.Name, "", broker.EventOrderPaid, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-	msgs, err := ch.Consume(q.Name, "", false, false, false, false, nil)
-	if err != nil {
-		logrus.Fatal(err)
-	}
-
-	for msg := range msgs {
-		c.handleMessage(ch, msg, q)
-	}
+func (c *Consumer) Listen(ctx context.Context, sub broker.Subscriber) error {
+	// 持久队列, 重启期间到期的重试消息按队列名投回来, 不会丢
+	return sub.Subscribe(ctx, broker.Subscription{
+		Queue:    "order." + broker.EventOrderPaid,
+		Exchange: broker.EventOrderPaid,
+	}, c.handleMessage)
 }
 
-func (c *Consumer) handleMessage(ch *amqp.Channel, msg amqp.Delivery, q amqp.Queue) { // order的consume只执行更新订单
+func (c *Consumer) handleMessage(d broker.Delivery) { // order的consume只执行更新订单
+	msg := d.Message()
 	logrus.WithFields(logrus.Fields{
-		"from_q": q.Name,
-		"msg_id": msg.MessageId,
+		"from_q": d.Queue(),
+		"msg_id": msg.ID,
 	}).Info("Receive order.paid msg")
 	logrus.Trace("Receive order.paid")
 
 	tr := otel.Tracer("rabbitmq")
 	ctx, span := tr.Start(
 		broker.ExtractRabbitMQHeaders(context.Background(), msg.Headers),
-		fmt.Sprintf("rabbitmq.%s.consume", q.Name),
+		fmt.Sprintf("rabbitmq.%s.consume", d.Queue()),
 	)
 	defer span.End()
 
-	var err error
-	defer func() {
+	c.dedup.Handle(ctx, d, func(ctx context.Context) error {
+		env, err := broker.DecodeEvent(msg, broker.EventOrderPaid)
 		if err != nil {
-			fs := logrus.Fields{
-				"q_name": q.Name,
-				"q_msg":  msg,
-				"err":    err.Error(),
-			}
-			logrus.WithContext(ctx).WithFields(fs).Warn("MQ consume fail")
-			_ = msg.Nack(false, false)
-		} else {
-			logrus.WithContext(ctx).Info("MQ consume ok")
-			_ = msg.Ack(false)
+			logrus.Warnf("decode_event_fail || err=%s", err.Error())
+			return broker.Unprocessable(err)
 		}
-	}()
-
-	o := &domain.Order{}
-	if err = json.Unmarshal(msg.Body, o); err != nil {
-		logrus.Warnf("unmarshal_fail || err=%s", err.Error())
-		return
-	}
-	logrus.Tracef("paid.order=%v", *o)
+		paid := env.GetOrder()
+		o := &domain.Order{
+			ID:          paid.ID,
+			CustomerID:  paid.CustomerID,
+			Status:      paid.Status,
+			PaymentLink: paid.PaymentLink,
+			Items:       convert.ItemProtosToEntities(paid.Items),
+		}
+		logrus.Tracef("paid.order=%v", *o)
 
-	logrus.Trace("app.Commands.UpdateOrder.Handle start")
-	_, err = c.app.Commands.UpdateOrder.Handle(ctx, command.UpdateOrder{
-		Order: o,
-		UpdateFn: func(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-			if err := order.IsPaid(); err != nil {
-				return nil, err
+		logrus.Trace("app.Commands.UpdateOrder.Handle start")
+		// the paid transition is recorded as made by the service that published order.paid
+		_, err = c.app.Commands.UpdateOrder.Handle(actor.With(ctx, msg.Producer), command.UpdateOrder{
+			Order: o,
+			UpdateFn: func(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+				if err := order.IsPaid(); err != nil {
+					return nil, err
+				}
+				return order, nil
+			},
+		})
+		if err != nil {
+			fs := logrus.Fields{
+				"order_id": o.ID,
+				"q_name":   d.Queue(),
+				"q_msg":    msg,
+				"err":      err.Error(),
 			}
-			return order, nil
-		},
-	})
-	if err != nil {
-		fs := logrus.Fields{
-			"order_id": o.ID,
-			"q_name":   q.Name,
-			"q_msg":    msg,
-			"err":      err.Error(),
+			logrus.WithContext(ctx).WithFields(fs).Error("Update order fail")
+			return err
 		}
-		logrus.WithContext(ctx).WithFields(fs).Error("Update order fail")
-		// retry
-		if err = broker.HandleRetry(ctx, ch, &msg); err != nil {
-			logrus.WithFields(logrus.Fields{
-				"msg_id": msg.MessageId,
-				"err":    err.Error(),
-			}).Warn("Retry fail")
+
+		// a retry repeats the paid update above, which is idempotent
+		if _, err = c.app.Commands.CommitReservation.Handle(ctx, command.CommitReservation{OrderID: o.ID}); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": o.ID,
+				"err":      err.Error(),
+			}).Error("Commit reservation fail")
+			return err
 		}
-		return
-	}
 
-	span.AddEvent("order.update")
-	logrus.Info("Consume ok")
+		span.AddEvent("order.update")
+		logrus.Info("Consume ok")
+		return nil
+	})
 }
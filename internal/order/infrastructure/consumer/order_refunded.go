@@ -0,0 +1,76 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peiyouyao/gorder/common/actor"
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/order/app/command"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// 消费 payment 在 stripe 退款后广播的 order.refunded, 包括在 stripe 后台直接做的退款
+func (c *Consumer) ListenOrderRefunded(ctx context.Context, sub broker.Subscriber) error {
+	return sub.Subscribe(ctx, broker.Subscription{
+		Queue:    "order." + broker.EventOrderRefunded,
+		Exchange: broker.EventOrderRefunded,
+	}, c.handleOrderRefunded)
+}
+
+func (c *Consumer) handleOrderRefunded(d broker.Delivery) {
+	msg := d.Message()
+	logrus.WithFields(logrus.Fields{
+		"from_q": d.Queue(),
+		"msg_id": msg.ID,
+	}).Info("Receive order.refunded msg")
+
+	tr := otel.Tracer("rabbitmq")
+	ctx, span := tr.Start(
+		broker.ExtractRabbitMQHeaders(context.Background(), msg.Headers),
+		fmt.Sprintf("rabbitmq.%s.consume", d.Queue()),
+	)
+	defer span.End()
+
+	c.dedup.Handle(ctx, d, func(ctx context.Context) error {
+		env, err := broker.DecodeEvent(msg, broker.EventOrderRefunded)
+		if err != nil {
+			logrus.WithField("err", err.Error()).Warn("Decode event fail")
+			return broker.Unprocessable(err)
+		}
+		refunded := env.GetOrder()
+		if refunded.Status != constants.OrderStatusRefunded && refunded.Status != constants.OrderStatusPartiallyRefunded {
+			return broker.Unprocessable(fmt.Errorf("order.refunded with status %s, order_id=%s", refunded.Status, refunded.ID))
+		}
+		o := &domain.Order{
+			ID:          refunded.ID,
+			CustomerID:  refunded.CustomerID,
+			Status:      refunded.Status,
+			PaymentLink: refunded.PaymentLink,
+			Items:       convert.ItemProtosToEntities(refunded.Items),
+		}
+
+		// payment 通过 grpc 更新过的话这里不会再有变化
+		_, err = c.app.Commands.UpdateOrder.Handle(actor.With(ctx, msg.Producer), command.UpdateOrder{
+			Order: o,
+			UpdateFn: func(_ context.Context, order *domain.Order) (*domain.Order, error) {
+				return order, nil
+			},
+		})
+		if err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": o.ID,
+				"err":      err.Error(),
+			}).Error("Update refunded order fail")
+			return err
+		}
+
+		span.AddEvent("order.refunded")
+		logrus.Info("Consume order.refunded ok")
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package expiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/order/app"
+	"github.com/peiyouyao/gorder/order/app/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+定时扫描超过 order.payment-ttl 仍未支付的订单, 将其过期
+*/
+type Scheduler struct {
+	app        app.Application
+	paymentTTL time.Duration
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewScheduler(app app.Application) *Scheduler {
+	return &Scheduler{
+		app:        app,
+		paymentTTL: viper.GetDuration("order.payment-ttl") * time.Second,
+		interval:   viper.GetDuration("order.expiry-scheduler.interval") * time.Second,
+		batchSize:  viper.GetInt("order.expiry-scheduler.batch-size"),
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{
+		"payment_ttl": s.paymentTTL,
+		"interval":    s.interval,
+	}).Info("Order expiry scheduler started")
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Order expiry scheduler stopped")
+			return
+		case <-ticker.C:
+			s.expireUnpaid(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) expireUnpaid(ctx context.Context) {
+	n, err := s.app.Commands.ExpireOrders.Handle(ctx, command.ExpireOrders{
+		CreatedBefore: time.Now().Add(-s.paymentTTL),
+		Limit:         s.batchSize,
+	})
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("Expire unpaid orders fail err=%v", err)
+		return
+	}
+	if n > 0 {
+		logrus.WithContext(ctx).Infof("Expired %d unpaid orders", n)
+	}
+}
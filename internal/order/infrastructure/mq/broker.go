@@ -0,0 +1,44 @@
+package mq
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+)
+
+// impl domain.EventPublisher interface
+type BrokerEventPublisher struct {
+	Publisher broker.Publisher
+	Producer  string
+}
+
+func (p *BrokerEventPublisher) Publish(ctx context.Context, event domain.DomainEvent) error {
+	return broker.PublishEvent(ctx, &broker.PublishEventReq{
+		Publisher:  p.Publisher,
+		Routing:    broker.Direct,
+		Queue:      event.Dest,
+		Exchange:   "",
+		Body:       event.Data,
+		Producer:   p.Producer,
+		OccurredAt: event.OccurredAt,
+		MessageID:  event.ID,
+		// 没有队列接收时报错, outbox relay 稍后重试
+		Mandatory: true,
+	})
+}
+
+func (p *BrokerEventPublisher) Broadcast(ctx context.Context, event domain.DomainEvent) error {
+	return broker.PublishEvent(ctx, &broker.PublishEventReq{
+		Publisher:  p.Publisher,
+		Routing:    broker.Fanout,
+		Queue:      "",
+		Exchange:   event.Dest,
+		Body:       event.Data,
+		Producer:   p.Producer,
+		OccurredAt: event.OccurredAt,
+		MessageID:  event.ID,
+		// 没有队列接收时报错, outbox relay 稍后重试
+		Mandatory: true,
+	})
+}
@@ -0,0 +1,49 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/entity"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// impl domain.EventPublisher interface
+// events are only written to the outbox, outbox.Relay hands them to BrokerEventPublisher later
+type OutboxEventPublisher struct {
+	Outbox domain.Outbox
+}
+
+func (p *OutboxEventPublisher) Publish(ctx context.Context, event domain.DomainEvent) error {
+	return p.add(ctx, event, false)
+}
+
+func (p *OutboxEventPublisher) Broadcast(ctx context.Context, event domain.DomainEvent) error {
+	return p.add(ctx, event, true)
+}
+
+func (p *OutboxEventPublisher) add(ctx context.Context, event domain.DomainEvent, broadcast bool) error {
+	body, err := marshalData(event.Data)
+	if err != nil {
+		return err
+	}
+	return p.Outbox.Add(ctx, &domain.OutboxMessage{
+		Dest:      event.Dest,
+		Broadcast: broadcast,
+		Body:      body,
+		Headers:   broker.InjectRabbitMQHeaders(ctx),
+		CreatedAt: time.Now(),
+	})
+}
+
+// 订单按 orderpb.Order 的 json 保存, 时间是 unix 毫秒, relay 发送时 broker 能直接解析
+func marshalData(data any) ([]byte, error) {
+	if o, ok := data.(*entity.Order); ok {
+		return protojson.Marshal(convert.OrderEntityToProto(o))
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/tracing"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+轮询 outbox, 把和订单同一事务写入的事件投递到 mq, 失败则退避重试
+*/
+type Relay struct {
+	outbox     domain.Outbox
+	publisher  domain.EventPublisher
+	interval   time.Duration
+	batchSize  int
+	lease      time.Duration
+	maxBackoff time.Duration
+}
+
+func NewRelay(outbox domain.Outbox, publisher domain.EventPublisher) *Relay {
+	if outbox == nil {
+		panic("nil outbox")
+	}
+	if publisher == nil {
+		panic("nil publisher")
+	}
+	return &Relay{
+		outbox:     outbox,
+		publisher:  publisher,
+		interval:   viper.GetDuration("order.outbox-relay.interval") * time.Second,
+		batchSize:  viper.GetInt("order.outbox-relay.batch-size"),
+		lease:      viper.GetDuration("order.outbox-relay.lease") * time.Second,
+		maxBackoff: viper.GetDuration("order.outbox-relay.max-backoff") * time.Second,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	logrus.WithField("interval", r.interval).Info("Outbox relay started")
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			r.relayPending(ctx)
+		}
+	}
+}
+
+func (r *Relay) relayPending(ctx context.Context) {
+	msgs, err := r.outbox.ClaimPending(ctx, r.batchSize, r.lease)
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("Claim outbox msgs fail err=%v", err)
+	}
+	for _, m := range msgs {
+		r.relay(ctx, m)
+	}
+}
+
+func (r *Relay) relay(ctx context.Context, m *domain.OutboxMessage) {
+	ctx, span := tracing.Start(broker.ExtractRabbitMQHeaders(ctx, m.Headers), "outbox.relay")
+	defer span.End()
+
+	publish := r.publisher.Publish
+	if m.Broadcast {
+		publish = r.publisher.Broadcast
+	}
+	err := publish(ctx, domain.DomainEvent{
+		ID:         m.ID,
+		Dest:       m.Dest,
+		Data:       json.RawMessage(m.Body),
+		OccurredAt: m.CreatedAt,
+	})
+	if err != nil {
+		retryAt := time.Now().Add(r.backoff(m.Attempts))
+		msg := "Relay outbox msg fail"
+		if broker.IsUnroutable(err) {
+			// 消费方的队列还没声明, 留在 outbox 里等它上线
+			msg = "Relay outbox msg unroutable, no queue bound"
+		}
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"outbox_id": m.ID,
+			"dest":      m.Dest,
+			"attempts":  m.Attempts + 1,
+			"retry_at":  retryAt,
+			"err":       err.Error(),
+		}).Warn(msg)
+		if err = r.outbox.MarkFailed(ctx, m.ID, err, retryAt); err != nil {
+			logrus.WithContext(ctx).Warnf("Mark outbox msg failed fail outbox_id=%s err=%v", m.ID, err)
+		}
+		return
+	}
+
+	if err = r.outbox.MarkSent(ctx, m.ID); err != nil {
+		// the lease expires and the msg is published again with the same id, consumers drop it
+		logrus.WithContext(ctx).Warnf("Mark outbox msg sent fail outbox_id=%s err=%v", m.ID, err)
+	}
+}
+
+// 1s, 2s, 4s ... capped by maxBackoff
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := time.Second << min(attempts, 16)
+	return min(d, r.maxBackoff)
+}
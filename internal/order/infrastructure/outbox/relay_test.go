@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOutbox struct {
+	pending []*domain.OutboxMessage
+	sent    []string
+	failed  map[string]time.Time
+}
+
+func (f *fakeOutbox) Add(_ context.Context, msgs ...*domain.OutboxMessage) error {
+	f.pending = append(f.pending, msgs...)
+	return nil
+}
+
+func (f *fakeOutbox) ClaimPending(_ context.Context, limit int, _ time.Duration) ([]*domain.OutboxMessage, error) {
+	n := min(limit, len(f.pending))
+	claimed := f.pending[:n]
+	f.pending = f.pending[n:]
+	return claimed, nil
+}
+
+func (f *fakeOutbox) MarkSent(_ context.Context, id string) error {
+	f.sent = append(f.sent, id)
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(_ context.Context, id string, _ error, retryAt time.Time) error {
+	f.failed[id] = retryAt
+	return nil
+}
+
+type fakePublisher struct {
+	published, broadcast []domain.DomainEvent
+	err                  error
+}
+
+func (f *fakePublisher) Publish(_ context.Context, e domain.DomainEvent) error {
+	f.published = append(f.published, e)
+	return f.err
+}
+
+func (f *fakePublisher) Broadcast(_ context.Context, e domain.DomainEvent) error {
+	f.broadcast = append(f.broadcast, e)
+	return f.err
+}
+
+func newTestRelay(o *fakeOutbox, p *fakePublisher) *Relay {
+	return &Relay{outbox: o, publisher: p, batchSize: 10, lease: time.Minute, maxBackoff: 10 * time.Second}
+}
+
+func TestRelay(t *testing.T) {
+	o := &fakeOutbox{failed: map[string]time.Time{}}
+	require.NoError(t, o.Add(context.Background(),
+		&domain.OutboxMessage{ID: "1", Dest: "order.created", Body: []byte(`{}`)},
+		&domain.OutboxMessage{ID: "2", Dest: "order.cancelled", Broadcast: true, Body: []byte(`{}`)},
+	))
+	p := &fakePublisher{}
+	newTestRelay(o, p).relayPending(context.Background())
+
+	require.Len(t, p.published, 1)
+	assert.Equal(t, "1", p.published[0].ID)
+	assert.Equal(t, "order.created", p.published[0].Dest)
+	require.Len(t, p.broadcast, 1)
+	assert.Equal(t, "2", p.broadcast[0].ID)
+	assert.Equal(t, []string{"1", "2"}, o.sent)
+	assert.Empty(t, o.failed)
+}
+
+func TestRelay_Fail(t *testing.T) {
+	o := &fakeOutbox{failed: map[string]time.Time{}}
+	require.NoError(t, o.Add(context.Background(), &domain.OutboxMessage{ID: "1", Dest: "order.created", Attempts: 2}))
+	p := &fakePublisher{err: errors.New("broker down")}
+	newTestRelay(o, p).relayPending(context.Background())
+
+	assert.Empty(t, o.sent)
+	require.Contains(t, o.failed, "1")
+	// third attempt waits 4s
+	assert.WithinDuration(t, time.Now().Add(4*time.Second), o.failed["1"], time.Second)
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	r := newTestRelay(nil, nil)
+	assert.Equal(t, time.Second, r.backoff(0))
+	assert.Equal(t, 8*time.Second, r.backoff(3))
+	assert.Equal(t, 10*time.Second, r.backoff(4))
+	assert.Equal(t, 10*time.Second, r.backoff(100))
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/broker"
 	_ "github.com/peiyouyao/gorder/common/config"
 	"github.com/peiyouyao/gorder/common/discovery"
@@ -12,7 +16,9 @@ import (
 	"github.com/peiyouyao/gorder/common/server"
 	"github.com/peiyouyao/gorder/common/tracing"
 	"github.com/peiyouyao/gorder/order/app"
+	"github.com/peiyouyao/gorder/order/infrastructure/compensation"
 	"github.com/peiyouyao/gorder/order/infrastructure/consumer"
+	"github.com/peiyouyao/gorder/order/infrastructure/expiry"
 	"github.com/peiyouyao/gorder/order/ports"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -25,15 +31,17 @@ func init() {
 
 func main() {
 	serviceName := viper.GetString("order.service-name")
+	actor.SetService(serviceName)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// SIGINT / SIGTERM 后停止接收消息, 等处理中的消息 ack 完再退出
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	shutdown, err := tracing.InitJaegerProvider(viper.GetString("jaeger.url"), serviceName)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer shutdown(ctx)
+	defer shutdown(context.Background())
 
 	application, cleanup := app.NewApplication(ctx)
 	defer cleanup()
@@ -46,29 +54,64 @@ func main() {
 		_ = deregisterFn()
 	}()
 
-	ch, closeCh := broker.Connect(
-		viper.GetString("rabbitmq.user"),
-		viper.GetString("rabbitmq.password"),
-		viper.GetString("rabbitmq.host"),
-		viper.GetString("rabbitmq.port"),
-	)
+	b, closeBroker := broker.Open(serviceName)
+	// 消费者 drain 完后才关闭
 	defer func() {
-		_ = ch.Close()
-		_ = closeCh()
+		_ = closeBroker()
 	}()
-	go consumer.NewConsumer(application).Listen(ch)
+	// 死信队列只有 rabbitmq 有
+	var dlq *broker.DLQ
+	if viper.GetString("broker.driver") != broker.DriverMemory {
+		q, closeDLQ, err := broker.DialDLQ(
+			viper.GetString("rabbitmq.user"),
+			viper.GetString("rabbitmq.password"),
+			viper.GetString("rabbitmq.host"),
+			viper.GetString("rabbitmq.port"),
+		)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		defer func() {
+			_ = closeDLQ()
+		}()
+		dlq = q
+	}
+	var consumers sync.WaitGroup
+	consumers.Add(2)
+	c := consumer.NewConsumer(application)
+	go func() {
+		defer consumers.Done()
+		if err := c.Listen(ctx, b); err != nil {
+			logrus.WithField("consumer", "order").Warnf("Consumer stopped err=%v", err)
+		}
+	}()
+	go func() {
+		defer consumers.Done()
+		if err := c.ListenOrderRefunded(ctx, b); err != nil {
+			logrus.WithField("consumer", "order."+broker.EventOrderRefunded).Warnf("Consumer stopped err=%v", err)
+		}
+	}()
+	go expiry.NewScheduler(application).Run(ctx)
+	go compensation.NewScheduler(application).Run(ctx)
 
 	go server.RunGRPCServer(serviceName, func(server *grpc.Server) {
 		svc := ports.NewGRPCServer(application)
 		orderpb.RegisterOrderServiceServer(server, svc)
 	})
 
-	server.RunHTTPServer(serviceName, func(router *gin.Engine) {
+	go server.RunHTTPServer(serviceName, func(router *gin.Engine) {
 		router.StaticFile("/success", "../../public/success.html")
 		ports.RegisterHandlersWithOptions(router, &ports.HTTPServer{App: application}, ports.GinServerOptions{
 			BaseURL:      "/api",
 			Middlewares:  nil,
 			ErrorHandler: nil,
 		})
+		if dlq != nil {
+			server.RegisterDLQAdmin(router.Group("/api/admin"), dlq)
+		}
 	})
+
+	<-ctx.Done()
+	logrus.Info("Receive signal, draining consumers ...")
+	consumers.Wait()
 }
@@ -2,6 +2,8 @@ package ports
 
 import (
 	context "context"
+	"errors"
+	"time"
 
 	"github.com/peiyouyao/gorder/common/convert"
 	"github.com/peiyouyao/gorder/common/genproto/orderpb"
@@ -25,15 +27,21 @@ func NewGRPCServer(app app.Application) *GRPCServer {
 	return &GRPCServer{app: app}
 }
 
-func (s *GRPCServer) CreateOrder(ctx context.Context, request *orderpb.CreateOrderRequest) (*emptypb.Empty, error) {
-	_, err := s.app.Commands.CreateOrder.Handle(ctx, command.CreateOrder{
-		CustomerID: request.CustomerID,
-		Items:      convert.ItemWithQuantityProtosToEntities(request.Items),
+func (s *GRPCServer) CreateOrder(ctx context.Context, request *orderpb.CreateOrderRequest) (*orderpb.CreateOrderResponse, error) {
+	r, err := s.app.Commands.CreateOrder.Handle(ctx, command.CreateOrder{
+		CustomerID:     request.CustomerID,
+		Items:          convert.ItemWithQuantityProtosToEntities(request.Items),
+		IdempotencyKey: request.IdempotencyKey,
 	})
-	if err != nil {
+	switch {
+	case errors.Is(err, command.ErrIdempotencyKeyReused):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, command.ErrIdempotencyKeyInProgress):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	return &emptypb.Empty{}, nil
+	return &orderpb.CreateOrderResponse{OrderID: r.OrderID}, nil
 }
 
 func (s *GRPCServer) GetOrder(ctx context.Context, request *orderpb.GetOrderRequest) (*orderpb.Order, error) {
@@ -42,15 +50,9 @@ func (s *GRPCServer) GetOrder(ctx context.Context, request *orderpb.GetOrderRequ
 		OrderID:    request.OrderID,
 	})
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
-	}
-	return &orderpb.Order{
-		ID:          o.ID,
-		CustomerID:  o.CustomerID,
-		Status:      o.Status,
-		Items:       convert.ItemEntitiesToProtos(o.Items),
-		PaymentLink: o.PaymentLink,
-	}, nil
+		return nil, toStatus(err)
+	}
+	return convert.OrderEntityToProto(o.Entity()), nil
 }
 
 func (s *GRPCServer) UpdateOrder(ctx context.Context, request *orderpb.Order) (_ *emptypb.Empty, err error) {
@@ -66,6 +68,10 @@ func (s *GRPCServer) UpdateOrder(ctx context.Context, request *orderpb.Order) (_
 		err = status.Error(codes.Internal, err.Error())
 		return
 	}
+	// 其他时间由 order 自己记录
+	if request.EstimatedReadyAt > 0 {
+		order.EstimatedReadyAt = time.UnixMilli(request.EstimatedReadyAt)
+	}
 	logrus.Tracef("domain.NewOrder order=%v", *order)
 
 	logrus.Trace("app.Commands.UpdateOrder.Handle start")
@@ -77,7 +83,116 @@ func (s *GRPCServer) UpdateOrder(ctx context.Context, request *orderpb.Order) (_
 	})
 	if err != nil {
 		logrus.Trace("app.Commands.UpdateOrder.Handle fail")
+		err = toStatus(err)
 	}
 	logrus.Trace("app.Commands.UpdateOrder.Handle ok")
 	return
 }
+
+func (s *GRPCServer) CancelOrder(ctx context.Context, request *orderpb.CancelOrderRequest) (*emptypb.Empty, error) {
+	_, err := s.app.Commands.CancelOrder.Handle(ctx, command.CancelOrder{
+		CustomerID: request.CustomerID,
+		OrderID:    request.OrderID,
+		Reason:     request.Reason,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *GRPCServer) RejectOrder(ctx context.Context, request *orderpb.RejectOrderRequest) (*emptypb.Empty, error) {
+	_, err := s.app.Commands.RejectOrder.Handle(ctx, command.RejectOrder{
+		CustomerID: request.CustomerID,
+		OrderID:    request.OrderID,
+		Reason:     request.Reason,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *GRPCServer) ListOrders(ctx context.Context, request *orderpb.ListOrdersRequest) (*orderpb.ListOrdersResponse, error) {
+	q := query.ListCustomerOrders{
+		CustomerID: request.CustomerID,
+		Statuses:   request.Statuses,
+		Cursor:     request.Cursor,
+		Limit:      int(request.Limit),
+	}
+	if request.CreatedAfter > 0 {
+		q.CreatedAfter = time.Unix(request.CreatedAfter, 0)
+	}
+	if request.CreatedBefore > 0 {
+		q.CreatedBefore = time.Unix(request.CreatedBefore, 0)
+	}
+
+	page, err := s.app.Queries.ListCustomerOrders.Handle(ctx, q)
+	if err != nil {
+		var (
+			cursorErr domain.InvalidCursorError
+			limitErr  domain.InvalidLimitError
+		)
+		if errors.As(err, &cursorErr) || errors.As(err, &limitErr) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &orderpb.ListOrdersResponse{NextCursor: page.NextCursor}
+	for _, o := range page.Orders {
+		resp.Orders = append(resp.Orders, convert.OrderEntityToProto(o.Entity()))
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetOrderHistory(ctx context.Context, request *orderpb.GetOrderRequest) (*orderpb.OrderHistory, error) {
+	entries, err := s.app.Queries.GetOrderHistory.Handle(ctx, query.GetOrderHistory{
+		CustomerID: request.CustomerID,
+		OrderID:    request.OrderID,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &orderpb.OrderHistory{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &orderpb.OrderHistoryEntry{
+			Version: e.Version,
+			Type:    e.Type,
+			Old:     e.Old,
+			New:     e.New,
+			Items:   convert.ItemEntitiesToProtos(e.Items),
+			At:      e.At.UnixMilli(),
+			Actor:   e.Actor,
+			TraceID: e.TraceID,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) WatchOrder(request *orderpb.GetOrderRequest, stream orderpb.OrderService_WatchOrderServer) error {
+	_, err := s.app.Queries.WatchOrder.Handle(stream.Context(), query.WatchOrder{
+		CustomerID: request.CustomerID,
+		OrderID:    request.OrderID,
+		Send: func(o *domain.Order) error {
+			return stream.Send(convert.OrderEntityToProto(o.Entity()))
+		},
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+func toStatus(err error) error {
+	var notFound domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.As(err, &domain.InvalidTransitionError{}) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.As(err, &domain.ConflictError{}) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
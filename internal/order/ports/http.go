@@ -16,6 +16,7 @@ import (
 	"github.com/peiyouyao/gorder/order/app/command"
 	"github.com/peiyouyao/gorder/order/app/dto"
 	"github.com/peiyouyao/gorder/order/app/query"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
 )
 
 type HTTPServer struct {
@@ -23,7 +24,7 @@ type HTTPServer struct {
 	App                 app.Application
 }
 
-func (s *HTTPServer) PostCustomerCustomerIdOrders(c *gin.Context, customerID string) {
+func (s *HTTPServer) PostCustomerCustomerIdOrders(c *gin.Context, customerID string, params PostCustomerCustomerIdOrdersParams) {
 	var (
 		req  client.CreateOrderRequest
 		err  error
@@ -42,11 +43,18 @@ func (s *HTTPServer) PostCustomerCustomerIdOrders(c *gin.Context, customerID str
 		err = myerrors.NewWithError(constants.ErrnoInvalidParams, err)
 		return
 	}
-	r, err := s.App.Commands.CreateOrder.Handle(c.Request.Context(), command.CreateOrder{
+	cmd := command.CreateOrder{
 		CustomerID: req.CustomerId,
 		Items:      convert.ItemWithQuantityClientsToEntities(req.Items),
-	})
+	}
+	if params.IdempotencyKey != nil {
+		cmd.IdempotencyKey = *params.IdempotencyKey
+	}
+	r, err := s.App.Commands.CreateOrder.Handle(c.Request.Context(), cmd)
 	if err != nil {
+		if errors.Is(err, command.ErrIdempotencyKeyReused) || errors.Is(err, command.ErrIdempotencyKeyInProgress) {
+			err = myerrors.NewWithError(constants.ErrnoConflict, err)
+		}
 		return
 	}
 
@@ -71,15 +79,211 @@ func (s *HTTPServer) GetCustomerCustomerIdOrdersOrderId(c *gin.Context, customer
 		OrderID:    orderID,
 	})
 	if err != nil {
+		err = withNotFound(err)
+		return
+	}
+	resp.Order = convert.OrderEntityToClient(o.Entity())
+}
+
+func (s *HTTPServer) GetCustomerCustomerIdOrders(c *gin.Context, customerID string, params GetCustomerCustomerIdOrdersParams) {
+	var (
+		err  error
+		resp client.OrderList
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	q := query.ListCustomerOrders{CustomerID: customerID}
+	if params.Status != nil {
+		q.Statuses = *params.Status
+	}
+	if params.CreatedAfter != nil {
+		q.CreatedAfter = *params.CreatedAfter
+	}
+	if params.CreatedBefore != nil {
+		q.CreatedBefore = *params.CreatedBefore
+	}
+	if params.Cursor != nil {
+		q.Cursor = *params.Cursor
+	}
+	if params.Limit != nil {
+		q.Limit = int(*params.Limit)
+	}
+
+	page, err := s.App.Queries.ListCustomerOrders.Handle(c.Request.Context(), q)
+	if err != nil {
+		var (
+			cursorErr domain.InvalidCursorError
+			limitErr  domain.InvalidLimitError
+		)
+		if errors.As(err, &cursorErr) || errors.As(err, &limitErr) {
+			err = myerrors.NewWithError(constants.ErrnoInvalidParams, err)
+		}
+		return
+	}
+	resp.Orders = make([]client.Order, 0, len(page.Orders))
+	for _, o := range page.Orders {
+		resp.Orders = append(resp.Orders, *convert.OrderEntityToClient(o.Entity()))
+	}
+	resp.NextCursor = page.NextCursor
+}
+
+func (s *HTTPServer) PostCustomerCustomerIdOrdersOrderIdCancel(c *gin.Context, customerID string, orderID string) {
+	var (
+		req client.CancelOrderRequest
+		err error
+	)
+	defer func() {
+		s.Response(c, err, nil)
+	}()
+
+	if c.Request.ContentLength > 0 {
+		if err = c.ShouldBind(&req); err != nil {
+			err = myerrors.NewWithError(constants.ErrnoBindRequest, err)
+			return
+		}
+	}
+
+	cmd := command.CancelOrder{CustomerID: customerID, OrderID: orderID}
+	if req.Reason != nil {
+		cmd.Reason = *req.Reason
+	}
+	if _, err = s.App.Commands.CancelOrder.Handle(c.Request.Context(), cmd); err != nil {
+		// 已经支付或关闭的订单不能取消
+		if errors.As(err, &domain.InvalidTransitionError{}) {
+			err = myerrors.NewWithError(constants.ErrnoConflict, err)
+			return
+		}
+		err = withNotFound(err)
+	}
+}
+
+func (s *HTTPServer) GetCustomerCustomerIdOrdersOrderIdSaga(c *gin.Context, customerID string, orderID string) {
+	var (
+		err  error
+		resp struct {
+			Saga *client.Saga `json:"saga"`
+		}
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	sg, err := s.App.Queries.GetOrderSaga.Handle(c.Request.Context(), query.GetOrderSaga{
+		CustomerID: customerID,
+		OrderID:    orderID,
+	})
+	if err != nil {
+		err = withNotFound(err)
 		return
 	}
-	resp.Order = &client.Order{
-		Id:          o.ID,
-		CustomerId:  o.CustomerID,
-		Status:      o.Status,
-		Items:       convert.ItemEntitiesToClients(o.Items),
-		PaymentLink: o.PaymentLink,
+	resp.Saga = &client.Saga{
+		OrderId:   sg.OrderID,
+		Step:      string(sg.Step),
+		Status:    string(sg.Status),
+		History:   make([]client.SagaRecord, 0, len(sg.History)),
+		CreatedAt: sg.CreatedAt,
+		UpdatedAt: sg.UpdatedAt,
+	}
+	if !sg.Deadline.IsZero() {
+		resp.Saga.Deadline = &sg.Deadline
+	}
+	for _, r := range sg.History {
+		record := client.SagaRecord{
+			Step:   string(r.Step),
+			Action: r.Action,
+			At:     r.At,
+		}
+		if r.Reason != "" {
+			record.Reason = &r.Reason
+		}
+		resp.Saga.History = append(resp.Saga.History, record)
+	}
+}
+
+func (s *HTTPServer) GetCustomerCustomerIdOrdersOrderIdHistory(c *gin.Context, customerID string, orderID string) {
+	var (
+		err  error
+		resp struct {
+			History []client.OrderHistoryEntry `json:"history"`
+		}
+	)
+	defer func() {
+		s.Response(c, err, resp)
+	}()
+
+	entries, err := s.App.Queries.GetOrderHistory.Handle(c.Request.Context(), query.GetOrderHistory{
+		CustomerID: customerID,
+		OrderID:    orderID,
+	})
+	if err != nil {
+		err = withNotFound(err)
+		return
+	}
+	resp.History = make([]client.OrderHistoryEntry, 0, len(entries))
+	for _, e := range entries {
+		entry := client.OrderHistoryEntry{
+			Version: e.Version,
+			Type:    e.Type,
+			At:      e.At,
+			Actor:   e.Actor,
+		}
+		if e.Old != "" {
+			entry.Old = &e.Old
+		}
+		if e.New != "" {
+			entry.New = &e.New
+		}
+		if e.TraceID != "" {
+			entry.TraceId = &e.TraceID
+		}
+		if len(e.Items) > 0 {
+			items := convert.ItemEntitiesToClients(e.Items)
+			entry.Items = &items
+		}
+		resp.History = append(resp.History, entry)
+	}
+}
+
+// GetCustomerCustomerIdOrdersOrderIdEvents streams the order as server-sent events,
+// errors before the first event are answered like any other request.
+func (s *HTTPServer) GetCustomerCustomerIdOrdersOrderIdEvents(c *gin.Context, customerID string, orderID string) {
+	started := false
+	_, err := s.App.Queries.WatchOrder.Handle(c.Request.Context(), query.WatchOrder{
+		CustomerID: customerID,
+		OrderID:    orderID,
+		Send: func(o *domain.Order) error {
+			if !started {
+				started = true
+				c.Header("Cache-Control", "no-cache")
+				c.Header("Connection", "keep-alive")
+				c.Header("X-Accel-Buffering", "no") // 不让 nginx 缓冲
+			}
+			c.SSEvent("order", convert.OrderEntityToClient(o.Entity()))
+			c.Writer.Flush()
+			return nil
+		},
+		KeepAlive: func() error {
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		},
+	})
+	if err != nil && !started {
+		s.Response(c, withNotFound(err), nil)
+	}
+}
+
+// withNotFound tags domain.NotFoundError so that it is answered with 404
+func withNotFound(err error) error {
+	var notFound domain.NotFoundError
+	if errors.As(err, &notFound) {
+		return myerrors.NewWithError(constants.ErrnoNotFound, err)
 	}
+	return err
 }
 
 func (s *HTTPServer) validate(req *client.CreateOrderRequest) error {
@@ -0,0 +1,74 @@
+package ports_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/order/adapters"
+	"github.com/peiyouyao/gorder/order/app"
+	"github.com/peiyouyao/gorder/order/app/command"
+	domain "github.com/peiyouyao/gorder/order/domain/order"
+	"github.com/peiyouyao/gorder/order/ports"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl domain.Transactor
+type inlineTransactor struct{}
+
+func (inlineTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestCancelOrder_Paid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := adapters.NewOrderRepositoryInmem()
+	paid, err := repo.Create(context.Background(), &domain.Order{
+		CustomerID: "customer-1",
+		Status:     constants.OrderStatusPaid,
+		Items:      []*entity.Item{{ID: "item-1", Quantity: 1}},
+	})
+	require.NoError(t, err)
+
+	// 已支付的订单在发布事件和结束 saga 之前就失败了, 用不到 publisher
+	cancel := command.NewCancelOrderHandler(
+		repo,
+		inlineTransactor{},
+		struct{ domain.EventPublisher }{},
+		adapters.NewSagaRepositoryInmem(),
+		adapters.NewOrderWatcherInmem(),
+		logrus.NewEntry(logrus.StandardLogger()),
+		metrics.NoMetrics{},
+	)
+	router := gin.New()
+	ports.RegisterHandlers(router, &ports.HTTPServer{App: app.Application{Commands: app.Commands{CancelOrder: cancel}}})
+
+	do := func(orderID string) (int, int) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/customer/customer-1/orders/"+orderID+"/cancel", nil))
+		var resp struct {
+			Errno int `json:"errno"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Errno
+	}
+
+	code, errno := do(paid.ID)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, constants.ErrnoConflict, errno)
+	got, err := repo.Get(context.Background(), paid.ID, "customer-1")
+	require.NoError(t, err)
+	assert.Equal(t, constants.OrderStatusPaid, got.Status)
+
+	code, errno = do("missing")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, constants.ErrnoNotFound, errno)
+}
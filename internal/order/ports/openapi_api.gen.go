@@ -14,11 +14,26 @@ import (
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /customer/{customer_id}/orders)
+	GetCustomerCustomerIdOrders(c *gin.Context, customerId string, params GetCustomerCustomerIdOrdersParams)
+
 	// (POST /customer/{customer_id}/orders)
-	PostCustomerCustomerIdOrders(c *gin.Context, customerId string)
+	PostCustomerCustomerIdOrders(c *gin.Context, customerId string, params PostCustomerCustomerIdOrdersParams)
 
 	// (GET /customer/{customer_id}/orders/{order_id})
 	GetCustomerCustomerIdOrdersOrderId(c *gin.Context, customerId string, orderId string)
+
+	// (POST /customer/{customer_id}/orders/{order_id}/cancel)
+	PostCustomerCustomerIdOrdersOrderIdCancel(c *gin.Context, customerId string, orderId string)
+
+	// (GET /customer/{customer_id}/orders/{order_id}/events)
+	GetCustomerCustomerIdOrdersOrderIdEvents(c *gin.Context, customerId string, orderId string)
+
+	// (GET /customer/{customer_id}/orders/{order_id}/history)
+	GetCustomerCustomerIdOrdersOrderIdHistory(c *gin.Context, customerId string, orderId string)
+
+	// (GET /customer/{customer_id}/orders/{order_id}/saga)
+	GetCustomerCustomerIdOrdersOrderIdSaga(c *gin.Context, customerId string, orderId string)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -30,6 +45,73 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// GetCustomerCustomerIdOrders operation middleware
+func (siw *ServerInterfaceWrapper) GetCustomerCustomerIdOrders(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "customer_id" -------------
+	var customerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "customer_id", c.Param("customer_id"), &customerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter customer_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCustomerCustomerIdOrdersParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", c.Request.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter created_after: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", c.Request.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter created_before: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCustomerCustomerIdOrders(c, customerId, params)
+}
+
 // PostCustomerCustomerIdOrders operation middleware
 func (siw *ServerInterfaceWrapper) PostCustomerCustomerIdOrders(c *gin.Context) {
 
@@ -44,6 +126,30 @@ func (siw *ServerInterfaceWrapper) PostCustomerCustomerIdOrders(c *gin.Context)
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostCustomerCustomerIdOrdersParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Idempotency-Key, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Idempotency-Key: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -51,7 +157,7 @@ func (siw *ServerInterfaceWrapper) PostCustomerCustomerIdOrders(c *gin.Context)
 		}
 	}
 
-	siw.Handler.PostCustomerCustomerIdOrders(c, customerId)
+	siw.Handler.PostCustomerCustomerIdOrders(c, customerId, params)
 }
 
 // GetCustomerCustomerIdOrdersOrderId operation middleware
@@ -87,6 +193,138 @@ func (siw *ServerInterfaceWrapper) GetCustomerCustomerIdOrdersOrderId(c *gin.Con
 	siw.Handler.GetCustomerCustomerIdOrdersOrderId(c, customerId, orderId)
 }
 
+// PostCustomerCustomerIdOrdersOrderIdCancel operation middleware
+func (siw *ServerInterfaceWrapper) PostCustomerCustomerIdOrdersOrderIdCancel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "customer_id" -------------
+	var customerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "customer_id", c.Param("customer_id"), &customerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter customer_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostCustomerCustomerIdOrdersOrderIdCancel(c, customerId, orderId)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetCustomerCustomerIdOrdersOrderIdEvents(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "customer_id" -------------
+	var customerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "customer_id", c.Param("customer_id"), &customerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter customer_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCustomerCustomerIdOrdersOrderIdEvents(c, customerId, orderId)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetCustomerCustomerIdOrdersOrderIdHistory(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "customer_id" -------------
+	var customerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "customer_id", c.Param("customer_id"), &customerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter customer_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCustomerCustomerIdOrdersOrderIdHistory(c, customerId, orderId)
+}
+
+// GetCustomerCustomerIdOrdersOrderIdSaga operation middleware
+func (siw *ServerInterfaceWrapper) GetCustomerCustomerIdOrdersOrderIdSaga(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "customer_id" -------------
+	var customerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "customer_id", c.Param("customer_id"), &customerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter customer_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "order_id" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "order_id", c.Param("order_id"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCustomerCustomerIdOrdersOrderIdSaga(c, customerId, orderId)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -114,6 +352,11 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/customer/:customer_id/orders", wrapper.GetCustomerCustomerIdOrders)
 	router.POST(options.BaseURL+"/customer/:customer_id/orders", wrapper.PostCustomerCustomerIdOrders)
 	router.GET(options.BaseURL+"/customer/:customer_id/orders/:order_id", wrapper.GetCustomerCustomerIdOrdersOrderId)
+	router.POST(options.BaseURL+"/customer/:customer_id/orders/:order_id/cancel", wrapper.PostCustomerCustomerIdOrdersOrderIdCancel)
+	router.GET(options.BaseURL+"/customer/:customer_id/orders/:order_id/events", wrapper.GetCustomerCustomerIdOrdersOrderIdEvents)
+	router.GET(options.BaseURL+"/customer/:customer_id/orders/:order_id/history", wrapper.GetCustomerCustomerIdOrdersOrderIdHistory)
+	router.GET(options.BaseURL+"/customer/:customer_id/orders/:order_id/saga", wrapper.GetCustomerCustomerIdOrdersOrderIdSaga)
 }
@@ -33,7 +33,7 @@ func before() {
 
 func TestCreateOrder_success(t *testing.T) {
 	customerID := "123"
-	rsp, err := client.PostCustomerCustomerIdOrdersWithResponse(ctx, customerID,
+	rsp, err := client.PostCustomerCustomerIdOrdersWithResponse(ctx, customerID, nil,
 		sw.PostCustomerCustomerIdOrdersJSONRequestBody{
 			CustomerId: customerID,
 			Items: []sw.ItemWithQuantity{
@@ -55,7 +55,7 @@ func TestCreateOrder_success(t *testing.T) {
 
 func TestCreateOrder_invalidParam(t *testing.T) {
 	customerID := "123"
-	rsp, err := client.PostCustomerCustomerIdOrdersWithResponse(ctx, customerID,
+	rsp, err := client.PostCustomerCustomerIdOrdersWithResponse(ctx, customerID, nil,
 		sw.PostCustomerCustomerIdOrdersJSONRequestBody{
 			CustomerId: customerID,
 			Items:      nil,
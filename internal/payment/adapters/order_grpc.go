@@ -2,9 +2,12 @@ package adapters
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/peiyouyao/gorder/common/genproto/orderpb"
 	"github.com/peiyouyao/gorder/common/tracing"
+	"github.com/peiyouyao/gorder/payment/domain"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -21,5 +24,8 @@ func (o OrderGRPC) UpdateOrder(ctx context.Context, order *orderpb.Order) (err e
 	defer span.End()
 
 	_, err = o.client.UpdateOrder(ctx, order)
+	if st := status.Convert(err); st.Code() == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %s", domain.ErrOrderMovedOn, st.Message())
+	}
 	return status.Convert(err).Err()
 }
@@ -19,6 +19,8 @@ type Application struct {
 
 type Commands struct {
 	CreatePayment command.CreatePaymentHandler
+	ExpirePayment command.ExpirePaymentHandler
+	RefundPayment command.RefundPaymentHandler
 }
 
 func NewApplication(ctx context.Context) (Application, func()) {
@@ -48,6 +50,8 @@ func newApplication(
 	return Application{
 		Commands: Commands{
 			CreatePayment: command.NewCreatePaymentHandler(processor, orderGRPC, logger, metrics),
+			ExpirePayment: command.NewExpirePaymentHandler(processor, logger, metrics),
+			RefundPayment: command.NewRefundPaymentHandler(processor, orderGRPC, logger, metrics),
 		},
 	}
 }
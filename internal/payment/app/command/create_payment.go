@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"errors"
 
 	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/convert"
@@ -43,11 +44,17 @@ func (c createPaymentHandler) Handle(ctx context.Context, cmd CreatePayment) (li
 
 	logrus.Trace("orderGRPC.UpdateOrder start")
 	err = c.orderGRPC.UpdateOrder(ctx, convert.OrderEntityToProto(newOrder)) // 发送 grpc 给 order
+	if errors.Is(err, domain.ErrOrderMovedOn) {
+		// 生成链接前订单已经取消或过期, order.cancelled 时还没有链接可关, 这里关掉刚生成的
+		logrus.WithContext(ctx).WithField("order_id", cmd.Order.ID).Info("Order moved on, expire the new payment link")
+		return "", c.processor.ExpirePaymentLink(ctx, newOrder)
+	}
 	if err != nil {
 		logrus.Trace("orderGRPC.UpdateOrder fail")
+		return
 	}
 	logrus.Trace("orderGRPC.UpdateOrder ok")
-	return link, err
+	return link, nil
 }
 
 func NewCreatePaymentHandler(
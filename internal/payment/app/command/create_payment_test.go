@@ -0,0 +1,62 @@
+package command_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/genproto/orderpb"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/payment/app/command"
+	"github.com/peiyouyao/gorder/payment/domain"
+	"github.com/peiyouyao/gorder/payment/infrastructure/processor"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// impl command.OrderService
+type fakeOrderService struct {
+	updated []*orderpb.Order
+	err     error
+}
+
+func (f *fakeOrderService) UpdateOrder(_ context.Context, o *orderpb.Order) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.updated = append(f.updated, o)
+	return nil
+}
+
+func testOrder() *entity.Order {
+	return entity.NewOrder("order-1", "customer-1", constants.OrderStatusPending, "", []*entity.Item{
+		{ID: "item-1", Name: "item", Quantity: 1, PriceID: "price-1"},
+	})
+}
+
+func TestCreatePayment(t *testing.T) {
+	orders := &fakeOrderService{}
+	h := command.NewCreatePaymentHandler(processor.NewInmemProcess(), orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+
+	link, err := h.Handle(context.Background(), command.CreatePayment{Order: testOrder()})
+	require.NoError(t, err)
+	assert.NotEmpty(t, link)
+	require.Len(t, orders.updated, 1)
+	assert.Equal(t, constants.OrderStatusWaitingForPayment, orders.updated[0].Status)
+	assert.Equal(t, link, orders.updated[0].PaymentLink)
+}
+
+// the order was cancelled before its link was made, the link is closed and the msg is not retried
+func TestCreatePayment_OrderMovedOn(t *testing.T) {
+	p := processor.NewInmemProcess()
+	orders := &fakeOrderService{err: fmt.Errorf("%w: cannot transit", domain.ErrOrderMovedOn)}
+	h := command.NewCreatePaymentHandler(p, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+
+	link, err := h.Handle(context.Background(), command.CreatePayment{Order: testOrder()})
+	require.NoError(t, err)
+	assert.Empty(t, link)
+	assert.Equal(t, []string{"order-1"}, p.Expired())
+}
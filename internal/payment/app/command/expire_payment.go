@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/payment/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type ExpirePayment struct {
+	Order *entity.Order
+}
+
+type ExpirePaymentHandler decorator.CommandHandler[ExpirePayment, interface{}]
+
+type expirePaymentHandler struct {
+	processor domain.Processor
+}
+
+func (e expirePaymentHandler) Handle(ctx context.Context, cmd ExpirePayment) (interface{}, error) {
+	// 还没生成 link 的订单无需处理
+	if cmd.Order.PaymentLink == "" {
+		logrus.Tracef("No payment link order_id=%s", cmd.Order.ID)
+		return nil, nil
+	}
+	if err := e.processor.ExpirePaymentLink(ctx, cmd.Order); err != nil {
+		return nil, err
+	}
+	logrus.Trace("ExpirePaymentLink ok")
+	return nil, nil
+}
+
+func NewExpirePaymentHandler(
+	processor domain.Processor,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) ExpirePaymentHandler {
+
+	return decorator.ApplyCommandDecorators[ExpirePayment, interface{}](
+		expirePaymentHandler{processor: processor},
+		logger,
+		metrics,
+	)
+}
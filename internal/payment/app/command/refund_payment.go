@@ -0,0 +1,83 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/payment/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type RefundPayment struct {
+	Order  *entity.Order
+	Amount int64 // 0 退还剩下的全部
+	Reason string
+}
+
+type RefundPaymentHandler decorator.CommandHandler[RefundPayment, *domain.Refund]
+
+type refundPaymentHandler struct {
+	processor domain.Processor
+	orderGRPC OrderService
+}
+
+// 没有支付过的订单返回 nil, 退款后把订单改为 refunded / partially_refunded
+func (r refundPaymentHandler) Handle(ctx context.Context, cmd RefundPayment) (*domain.Refund, error) {
+	if cmd.Reason == "" {
+		return nil, errors.New("empty refund reason")
+	}
+	if cmd.Order.PaymentLink == "" {
+		logrus.Tracef("No payment link order_id=%s", cmd.Order.ID)
+		return nil, nil
+	}
+	refund, err := r.processor.Refund(ctx, cmd.Order, cmd.Amount, cmd.Reason)
+	if errors.Is(err, domain.ErrNotPaid) {
+		logrus.WithContext(ctx).WithField("order_id", cmd.Order.ID).Info("Order not paid, nothing to refund")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"order_id":  cmd.Order.ID,
+		"refund_id": refund.ID,
+		"amount":    refund.Amount,
+		"remaining": refund.Remaining,
+	}).Info("Refund ok")
+
+	status := constants.OrderStatusPartiallyRefunded
+	if refund.Remaining == 0 {
+		status = constants.OrderStatusRefunded
+	}
+	o, err := entity.NewValidOrder(cmd.Order.ID, cmd.Order.CustomerID, status, cmd.Order.PaymentLink, cmd.Order.Items)
+	if err != nil {
+		return nil, err
+	}
+	// 重试时退款不会重复, 见 domain.Processor
+	if err = r.orderGRPC.UpdateOrder(ctx, convert.OrderEntityToProto(o)); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func NewRefundPaymentHandler(
+	processor domain.Processor,
+	orderGRPC OrderService,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) RefundPaymentHandler {
+
+	return decorator.ApplyCommandDecorators[RefundPayment, *domain.Refund](
+		refundPaymentHandler{
+			processor: processor,
+			orderGRPC: orderGRPC,
+		},
+		logger,
+		metrics,
+	)
+}
@@ -0,0 +1,62 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	"github.com/peiyouyao/gorder/payment/app/command"
+	"github.com/peiyouyao/gorder/payment/infrastructure/processor"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rejectedOrder() *entity.Order {
+	return entity.NewOrder("order-1", "customer-1", constants.OrderStatusRejected, "inmem-payment-link", []*entity.Item{
+		{ID: "item-1", Name: "item", Quantity: 1, PriceID: "price-1"},
+	})
+}
+
+func TestRefundPayment(t *testing.T) {
+	ctx := context.Background()
+	p := processor.NewInmemProcess()
+	orders := &fakeOrderService{}
+	h := command.NewRefundPaymentHandler(p, orders, logrus.NewEntry(logrus.StandardLogger()), metrics.NoMetrics{})
+
+	_, err := h.Handle(ctx, command.RefundPayment{Order: rejectedOrder()})
+	assert.Error(t, err, "empty reason")
+
+	// 没有支付过的订单什么也不做
+	noLink := rejectedOrder()
+	noLink.PaymentLink = ""
+	refund, err := h.Handle(ctx, command.RefundPayment{Order: noLink, Reason: "order.cancelled"})
+	require.NoError(t, err)
+	assert.Nil(t, refund)
+	refund, err = h.Handle(ctx, command.RefundPayment{Order: rejectedOrder(), Reason: "order.rejected"})
+	require.NoError(t, err)
+	assert.Nil(t, refund)
+	assert.Empty(t, orders.updated)
+
+	p.MarkPaid("order-1")
+	refund, err = h.Handle(ctx, command.RefundPayment{Order: rejectedOrder(), Amount: 300, Reason: "damaged"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(700), refund.Remaining)
+	require.Len(t, orders.updated, 1)
+	assert.Equal(t, constants.OrderStatusPartiallyRefunded, orders.updated[0].Status)
+
+	// order 更新失败时消息会重投, 重投不会再退一次
+	orders.err = errors.New("order down")
+	_, err = h.Handle(ctx, command.RefundPayment{Order: rejectedOrder(), Reason: "order.rejected"})
+	require.Error(t, err)
+	orders.err = nil
+	refund, err = h.Handle(ctx, command.RefundPayment{Order: rejectedOrder(), Reason: "order.rejected"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(700), refund.Amount)
+	assert.Zero(t, refund.Remaining)
+	require.Len(t, orders.updated, 2)
+	assert.Equal(t, constants.OrderStatusRefunded, orders.updated[1].Status)
+}
@@ -2,12 +2,30 @@ package domain
 
 import (
 	"context"
+	"errors"
 
 	"github.com/peiyouyao/gorder/common/entity"
 )
 
 type Processor interface {
 	CreatePaymentLink(context.Context, *entity.Order) (string, error)
+	// ExpirePaymentLink makes the link of an unpaid order unusable, it is a no-op if the link is already closed.
+	ExpirePaymentLink(context.Context, *entity.Order) error
+	// Refund gives amount (in the smallest currency unit, 0 means whatever is left) of the order's payment back,
+	// it fails with ErrNotPaid when the order was never paid. Repeating the same refund only refunds once.
+	Refund(ctx context.Context, order *entity.Order, amount int64, reason string) (*Refund, error)
+}
+
+var (
+	ErrNotPaid = errors.New("order is not paid")
+	// ErrOrderMovedOn is returned by the order service when the order can not take the update any more, e.g. it was cancelled.
+	ErrOrderMovedOn = errors.New("order moved on")
+)
+
+type Refund struct {
+	ID        string // empty when there was nothing left to refund
+	Amount    int64  // refunded this time
+	Remaining int64  // still not refunded, 0 means fully refunded
 }
 
 type Order struct {
@@ -0,0 +1,80 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/convert"
+	"github.com/peiyouyao/gorder/payment/app/command"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// 这些事件之后订单不会再被制作, 已经付的钱要退还
+var closeEvents = []string{broker.EventOrderCancelled, broker.EventOrderExpired, broker.EventOrderRejected}
+
+/*
+订单取消或过期后关闭 stripe checkout session, 防止用户继续支付;
+关闭前已经付了款 (支付和过期同时发生), 或被厨房拒绝的订单, 全额退款.
+*/
+func (c *Consumer) ListenOrderClosed(ctx context.Context, sub broker.Subscriber) error {
+	errs := make(chan error, len(closeEvents))
+	for _, event := range closeEvents {
+		go func() {
+			errs <- sub.Subscribe(ctx, broker.Subscription{
+				Queue:    "payment." + event,
+				Exchange: event,
+			}, c.handleOrderClosed)
+		}()
+	}
+	var err error
+	for range closeEvents {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (c *Consumer) handleOrderClosed(d broker.Delivery) {
+	msg := d.Message()
+	// 队列名是 payment.<event>
+	event := strings.TrimPrefix(d.Queue(), "payment.")
+	logrus.WithFields(logrus.Fields{
+		"from_q": d.Queue(),
+		"msg_id": msg.ID,
+	}).Infof("Receive %s msg", event)
+
+	ctx := broker.ExtractRabbitMQHeaders(context.Background(), msg.Headers)
+	tr := otel.Tracer("rabbitmq")
+	_, span := tr.Start(ctx, fmt.Sprintf("rabbitmq.%s.consume", d.Queue()))
+	defer span.End()
+
+	c.dedup.Handle(ctx, d, func(ctx context.Context) error {
+		env, err := broker.DecodeEvent(msg, event)
+		if err != nil {
+			logrus.Warnf("Decode event fail err=%s", err.Error())
+			return broker.Unprocessable(err)
+		}
+		o := convert.OrderProtoToEntity(env.GetOrder())
+
+		// 被拒绝的订单已经支付, session 已经关闭; 两步都可以重复执行, 失败时整条消息重投
+		if event != broker.EventOrderRejected {
+			if _, err = c.app.Commands.ExpirePayment.Handle(ctx, command.ExpirePayment{Order: o}); err != nil {
+				logrus.Warnf("Expire payment fail order_id=%s err=%s", o.ID, err.Error())
+				return err
+			}
+		}
+		// 没有支付的订单什么也不做
+		if _, err = c.app.Commands.RefundPayment.Handle(ctx, command.RefundPayment{Order: o, Reason: event}); err != nil {
+			logrus.Warnf("Refund payment fail order_id=%s err=%s", o.ID, err.Error())
+			return err
+		}
+
+		span.AddEvent("payment.closed")
+		logrus.Infof("Consume %s ok", event)
+		return nil
+	})
+}
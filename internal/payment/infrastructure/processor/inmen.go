@@ -2,21 +2,87 @@ package processor
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/payment/domain"
 )
 
+// every in-memory payment is worth inmemAmount
+const inmemAmount = 1000
+
 // stub
 // impl domain.Processor
 type InmemProcessor struct {
+	mu       sync.Mutex
+	expired  []string                  // order ids
+	paid     map[string]bool           // order ids, see MarkPaid
+	refunded map[string]int64          // order id -> amount refunded
+	refunds  map[string]*domain.Refund // refund key -> refund
 }
 
 func NewInmemProcess() *InmemProcessor {
+	return &InmemProcessor{
+		paid:     make(map[string]bool),
+		refunded: make(map[string]int64),
+		refunds:  make(map[string]*domain.Refund),
+	}
+}
 
-	return &InmemProcessor{}
+func (i *InmemProcessor) CreatePaymentLink(ctx context.Context, order *entity.Order) (string, error) {
+	return "inmem-payment-link", nil
 }
 
-func (i InmemProcessor) CreatePaymentLink(ctx context.Context, order *entity.Order) (string, error) {
+func (i *InmemProcessor) ExpirePaymentLink(ctx context.Context, order *entity.Order) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.expired = append(i.expired, order.ID)
+	return nil
+}
 
-	return "inmem-payment-link", nil
+// Expired returns the orders whose link was expired, oldest first.
+func (i *InmemProcessor) Expired() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return slices.Clone(i.expired)
+}
+
+// MarkPaid pays inmemAmount for the order, there is no checkout to do it.
+func (i *InmemProcessor) MarkPaid(orderID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.paid[orderID] = true
+}
+
+// only orders passed to MarkPaid can be refunded
+func (i *InmemProcessor) Refund(ctx context.Context, order *entity.Order, amount int64, reason string) (*domain.Refund, error) {
+	if amount < 0 {
+		return nil, fmt.Errorf("negative refund amount %d", amount)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.paid[order.ID] {
+		return nil, domain.ErrNotPaid
+	}
+
+	left := inmemAmount - i.refunded[order.ID]
+	key := refundKey(order.ID, amount, reason)
+	if r, ok := i.refunds[key]; ok {
+		return &domain.Refund{ID: r.ID, Amount: r.Amount, Remaining: left}, nil
+	}
+	if amount == 0 {
+		amount = left
+	}
+	if amount == 0 {
+		return &domain.Refund{}, nil
+	}
+	if amount > left {
+		return nil, fmt.Errorf("refund %d of order %s is more than the %d left", amount, order.ID, left)
+	}
+	i.refunded[order.ID] += amount
+	r := &domain.Refund{ID: key, Amount: amount, Remaining: left - amount}
+	i.refunds[key] = r
+	return r, nil
 }
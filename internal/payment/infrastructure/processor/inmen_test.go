@@ -0,0 +1,53 @@
+package processor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peiyouyao/gorder/common/constants"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/payment/domain"
+	"github.com/peiyouyao/gorder/payment/infrastructure/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInmemProcessor_Refund(t *testing.T) {
+	ctx := context.Background()
+	p := processor.NewInmemProcess()
+	o := entity.NewOrder("order-1", "customer-1", constants.OrderStatusRejected, "inmem-payment-link", []*entity.Item{
+		{ID: "item-1", Name: "item", Quantity: 1, PriceID: "price-1"},
+	})
+
+	// 有支付链接但没有付款
+	_, err := p.Refund(ctx, o, 0, "order.rejected")
+	assert.ErrorIs(t, err, domain.ErrNotPaid)
+
+	p.MarkPaid(o.ID)
+	partial, err := p.Refund(ctx, o, 300, "damaged")
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), partial.Amount)
+	assert.Equal(t, int64(700), partial.Remaining)
+
+	// 重复的退款只退一次
+	again, err := p.Refund(ctx, o, 300, "damaged")
+	require.NoError(t, err)
+	assert.Equal(t, partial.ID, again.ID)
+	assert.Equal(t, int64(700), again.Remaining)
+
+	_, err = p.Refund(ctx, o, 800, "damaged")
+	assert.Error(t, err)
+
+	// 0 退还剩下的全部
+	rest, err := p.Refund(ctx, o, 0, "order.rejected")
+	require.NoError(t, err)
+	assert.Equal(t, int64(700), rest.Amount)
+	assert.Zero(t, rest.Remaining)
+	again, err = p.Refund(ctx, o, 0, "order.rejected")
+	require.NoError(t, err)
+	assert.Equal(t, rest.ID, again.ID)
+	assert.Zero(t, again.Remaining)
+
+	_, err = p.Refund(ctx, o, -1, "damaged")
+	assert.Error(t, err)
+}
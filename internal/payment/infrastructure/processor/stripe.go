@@ -2,13 +2,20 @@ package processor
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/peiyouyao/gorder/common/entity"
 	"github.com/peiyouyao/gorder/common/tracing"
+	"github.com/peiyouyao/gorder/payment/domain"
 	"github.com/stripe/stripe-go/v82"
 	"github.com/stripe/stripe-go/v82/checkout/session"
+	"github.com/stripe/stripe-go/v82/refund"
 )
 
 // impl domain.Processor interface
@@ -60,3 +67,109 @@ func (s StripeProcessor) CreatePaymentLink(ctx context.Context, order *entity.Or
 	}
 	return result.URL, nil
 }
+
+func (s StripeProcessor) ExpirePaymentLink(ctx context.Context, order *entity.Order) error {
+	_, span := tracing.Start(ctx, "stripe_processor.expire_payment_link")
+	defer span.End()
+
+	id, err := sessionIDFromLink(order.PaymentLink)
+	if err != nil {
+		return err
+	}
+	sess, err := session.Get(id, nil)
+	if err != nil {
+		return err
+	}
+	// paid or already expired, nothing to close
+	if sess.Status != stripe.CheckoutSessionStatusOpen {
+		return nil
+	}
+	_, err = session.Expire(id, &stripe.CheckoutSessionExpireParams{})
+	return err
+}
+
+/*
+Refund 退还订单 checkout session 对应 payment intent 的付款.
+每次退款在 metadata 里带上由 (订单, 金额, 原因) 算出的 refundKey, 重复的退款找到之前那笔直接返回,
+同时作为 idempotency key 防止并发的重复请求.
+*/
+func (s StripeProcessor) Refund(ctx context.Context, order *entity.Order, amount int64, reason string) (*domain.Refund, error) {
+	_, span := tracing.Start(ctx, "stripe_processor.refund")
+	defer span.End()
+
+	if amount < 0 {
+		return nil, fmt.Errorf("negative refund amount %d", amount)
+	}
+	id, err := sessionIDFromLink(order.PaymentLink)
+	if err != nil {
+		return nil, err
+	}
+	params := &stripe.CheckoutSessionParams{}
+	params.AddExpand("payment_intent.latest_charge")
+	sess, err := session.Get(id, params)
+	if err != nil {
+		return nil, err
+	}
+	if sess.PaymentStatus != stripe.CheckoutSessionPaymentStatusPaid || sess.PaymentIntent == nil || sess.PaymentIntent.LatestCharge == nil {
+		return nil, domain.ErrNotPaid
+	}
+	charge := sess.PaymentIntent.LatestCharge
+	left := charge.Amount - charge.AmountRefunded
+
+	key := refundKey(order.ID, amount, reason)
+	it := refund.List(&stripe.RefundListParams{PaymentIntent: stripe.String(sess.PaymentIntent.ID)})
+	for it.Next() {
+		r := it.Refund()
+		if r.Metadata["refundKey"] == key && r.Status != stripe.RefundStatusFailed && r.Status != stripe.RefundStatusCanceled {
+			return &domain.Refund{ID: r.ID, Amount: r.Amount, Remaining: left}, nil
+		}
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+
+	if amount == 0 {
+		amount = left
+	}
+	if amount == 0 {
+		// 已经全部退过了
+		return &domain.Refund{}, nil
+	}
+	if amount > left {
+		return nil, fmt.Errorf("refund %d of order %s is more than the %d left", amount, order.ID, left)
+	}
+	refundParams := &stripe.RefundParams{
+		PaymentIntent: stripe.String(sess.PaymentIntent.ID),
+		Amount:        stripe.Int64(amount),
+		Reason:        stripe.String(string(stripe.RefundReasonRequestedByCustomer)),
+		Metadata: map[string]string{
+			"orderID":   order.ID,
+			"reason":    reason,
+			"refundKey": key,
+		},
+	}
+	refundParams.SetIdempotencyKey(key)
+	r, err := refund.New(refundParams)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Refund{ID: r.ID, Amount: r.Amount, Remaining: left - r.Amount}, nil
+}
+
+func refundKey(orderID string, amount int64, reason string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", orderID, amount, reason)))
+	return "refund-" + hex.EncodeToString(sum[:16])
+}
+
+// https://checkout.stripe.com/c/pay/cs_test_xxx#yyy -> cs_test_xxx
+func sessionIDFromLink(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	id := path.Base(u.Path)
+	if !strings.HasPrefix(id, "cs_") {
+		return "", fmt.Errorf("no checkout session id in payment link %q", link)
+	}
+	return id, nil
+}
@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"os/signal"
+	"sync"
+	"syscall"
 
+	"github.com/peiyouyao/gorder/common/actor"
 	"github.com/peiyouyao/gorder/common/broker"
 	_ "github.com/peiyouyao/gorder/common/config"
 	"github.com/peiyouyao/gorder/common/logging"
@@ -21,32 +25,47 @@ func init() {
 
 func main() {
 	serviceName := viper.GetString("payment.service-name")
+	actor.SetService(serviceName)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// SIGINT / SIGTERM 后停止接收消息, 等处理中的消息 ack 完再退出
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	shutdown, err := tracing.InitJaegerProvider(viper.GetString("jaeger.url"), serviceName)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer shutdown(ctx)
+	defer shutdown(context.Background())
 
 	application, cleanup := app.NewApplication(ctx)
 	defer cleanup()
 
-	ch, closeCh := broker.Connect(
-		viper.GetString("rabbitmq.user"),
-		viper.GetString("rabbitmq.password"),
-		viper.GetString("rabbitmq.host"),
-		viper.GetString("rabbitmq.port"),
-	)
+	b, closeBroker := broker.Open(serviceName)
+	// 消费者 drain 完后才关闭
 	defer func() {
-		_ = ch.Close()
-		_ = closeCh()
+		_ = closeBroker()
 	}()
 
-	go consumer.NewConsumer(application).Listen(ch)
+	var consumers sync.WaitGroup
+	consumers.Add(2)
+	c := consumer.NewConsumer(application)
+	go func() {
+		defer consumers.Done()
+		if err := c.Listen(ctx, b); err != nil {
+			logrus.WithField("consumer", "payment."+broker.EventOrderCreated).Warnf("Consumer stopped err=%v", err)
+		}
+	}()
+	go func() {
+		defer consumers.Done()
+		if err := c.ListenOrderClosed(ctx, b); err != nil {
+			logrus.WithField("consumer", "payment.closed").Warnf("Consumer stopped err=%v", err)
+		}
+	}()
+
+	paymentHandler := ports.NewPaymentHandler(b)
+	go server.RunHTTPServer(serviceName, paymentHandler.RegisterRoutes)
 
-	paymentHandler := ports.NewPaymentHandler(ch)
-	server.RunHTTPServer(serviceName, paymentHandler.RegisterRoutes)
+	<-ctx.Done()
+	logrus.Info("Receive signal, draining consumers ...")
+	consumers.Wait()
 }
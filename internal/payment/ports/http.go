@@ -1,19 +1,21 @@
 package ports
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/peiyouyao/gorder/common/broker"
 	"github.com/peiyouyao/gorder/common/constants"
 	"github.com/peiyouyao/gorder/common/entity"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/checkout/session"
 	"github.com/stripe/stripe-go/v82/webhook"
 	"go.opentelemetry.io/otel"
 )
@@ -22,11 +24,14 @@ import (
 暴露.../api/webhook 接口, 供stripe调用(POST)
 */
 type PaymentHandler struct {
-	channel *amqp.Channel
+	publisher broker.Publisher
 }
 
-func NewPaymentHandler(ch *amqp.Channel) *PaymentHandler {
-	return &PaymentHandler{channel: ch}
+func NewPaymentHandler(publisher broker.Publisher) *PaymentHandler {
+	if publisher == nil {
+		panic("nil publisher")
+	}
+	return &PaymentHandler{publisher: publisher}
 }
 
 // stripe listen --forward-to localhost:8284/api/webhook
@@ -69,7 +74,7 @@ func (h *PaymentHandler) handleWebhook(c *gin.Context) {
 	case stripe.EventTypeCheckoutSessionCompleted:
 		logrus.Trace("User paid")
 		var session stripe.CheckoutSession
-		if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
+		if err = json.Unmarshal(event.Data.Raw, &session); err != nil {
 			logrus.Errorf("Unmarshal event.Data.Raw fail err = %v", err)
 			c.JSON(http.StatusBadRequest, err.Error())
 			return
@@ -84,7 +89,11 @@ func (h *PaymentHandler) handleWebhook(c *gin.Context) {
 			defer span.End()
 
 			var items []*entity.Item
-			_ = json.Unmarshal([]byte(session.Metadata["items"]), &items)
+			if err = json.Unmarshal([]byte(session.Metadata["items"]), &items); err != nil {
+				logrus.Errorf("Unmarshal session items fail err = %v", err)
+				c.JSON(http.StatusBadRequest, err.Error())
+				return
+			}
 
 			o := entity.NewOrder(
 				session.Metadata["orderID"],
@@ -96,18 +105,93 @@ func (h *PaymentHandler) handleWebhook(c *gin.Context) {
 			logrus.Tracef("Upate paymentLink and Status order=%v", o)
 
 			logrus.Trace("broker.PublishEvent")
-			err = broker.PublishEvent(ctx, &broker.PublishEventReq{
-				Channel:  h.channel,
-				Routing:  broker.Fanout,
-				Exchange: broker.EventOrderPaid,
-				Queue:    "",
-				Body:     *o,
-			})
-			if err != nil {
-				logrus.Trace("broker.PublishEvent fail")
+			if err = h.publish(ctx, broker.EventOrderPaid, event, o); err != nil {
+				c.JSON(publishFailStatus(err), err.Error())
+				return
 			}
 			logrus.Trace("broker.PublishEvent success")
 		}
+
+	// 包括 RefundPayment 发起的和在 stripe 后台直接做的退款
+	case stripe.EventTypeChargeRefunded:
+		var charge stripe.Charge
+		if err = json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			logrus.Errorf("Unmarshal event.Data.Raw fail err = %v", err)
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx, span := otel.Tracer("rabbitmq").Start(
+			c.Request.Context(),
+			fmt.Sprintf("rabbitmq.%s.publish", broker.EventOrderRefunded),
+		)
+		defer span.End()
+
+		var o *entity.Order
+		if o, err = refundedOrder(&charge); err != nil {
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		if o == nil {
+			// 不是 checkout session 的付款, 与订单无关
+			logrus.Infof("No checkout session for charge_id=%s", charge.ID)
+			break
+		}
+		if err = h.publish(ctx, broker.EventOrderRefunded, event, o); err != nil {
+			c.JSON(publishFailStatus(err), err.Error())
+			return
+		}
 	}
 	c.JSON(http.StatusOK, nil)
 }
+
+func (h *PaymentHandler) publish(ctx context.Context, exchange string, event stripe.Event, o *entity.Order) error {
+	return broker.PublishEvent(ctx, &broker.PublishEventReq{
+		Publisher: h.publisher,
+		Routing:   broker.Fanout,
+		Exchange:  exchange,
+		Queue:     "",
+		Body:      *o,
+		Producer:  viper.GetString("payment.service-name"),
+		// stripe 可能重复推送同一个 event
+		MessageID:  event.ID,
+		OccurredAt: time.Unix(event.Created, 0),
+		Mandatory:  true,
+	})
+}
+
+// 返回 5xx 让 stripe 稍后重新推送
+func publishFailStatus(err error) int {
+	if broker.IsUnroutable(err) {
+		// 没有服务在监听这个事件
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// refundedOrder 通过 payment intent 找到 checkout session, 订单信息在 session 的 metadata 里
+func refundedOrder(charge *stripe.Charge) (*entity.Order, error) {
+	if charge.PaymentIntent == nil {
+		return nil, nil
+	}
+	it := session.List(&stripe.CheckoutSessionListParams{PaymentIntent: stripe.String(charge.PaymentIntent.ID)})
+	if !it.Next() {
+		return nil, it.Err()
+	}
+	sess := it.CheckoutSession()
+
+	var items []*entity.Item
+	if err := json.Unmarshal([]byte(sess.Metadata["items"]), &items); err != nil {
+		return nil, fmt.Errorf("unmarshal items of session %s: %w", sess.ID, err)
+	}
+	status := constants.OrderStatusPartiallyRefunded
+	if charge.Refunded {
+		status = constants.OrderStatusRefunded
+	}
+	return entity.NewOrder(
+		sess.Metadata["orderID"],
+		sess.Metadata["customerID"],
+		status,
+		sess.Metadata["paymentLink"],
+		items,
+	), nil
+}
@@ -2,10 +2,13 @@ package adapters
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/peiyouyao/gorder/common/entity"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
 	"github.com/peiyouyao/gorder/stock/infrastructure/persistent"
 	"github.com/peiyouyao/gorder/stock/infrastructure/persistent/builder"
 	"github.com/sirupsen/logrus"
@@ -60,6 +63,161 @@ func (m StockRepositoryMySQL) UpdateStock(
 	})
 }
 
+func (m StockRepositoryMySQL) ReserveStock(ctx context.Context, orderID string, data []*entity.ItemWithQuantity, expiresAt time.Time) error {
+	return m.db.StartTransaction(func(tx *gorm.DB) (err error) {
+		defer func() {
+			if err != nil {
+				logrus.Warnf("Transaction fail err = %v", err)
+			}
+		}()
+		existing, err := m.db.GetReservations(ctx, tx, builder.NewReservation().OrderIDs(orderID).ForUpdate())
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing reservation")
+		}
+		if len(existing) > 0 {
+			return nil
+		}
+
+		dest, err := m.db.LockBatchByID(ctx, tx, builder.NewStock().ProductIDs(getIDFromEntities(data)...))
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing stock")
+		}
+		if err = checkEnough(m.unmarshalFromDatabase(dest), data); err != nil {
+			return err
+		}
+
+		var reservations []persistent.ReservationModel
+		for _, d := range data {
+			if err = m.db.Update(ctx, tx,
+				builder.NewStock().ProductIDs(d.ID).QuantityGT(d.Quantity),
+				map[string]any{"quantity": gorm.Expr("quantity - ?", d.Quantity)},
+			); err != nil {
+				return errors.Wrapf(err, "unable to reserve stock for product %s", d.ID)
+			}
+			reservations = append(reservations, persistent.ReservationModel{
+				OrderID:   orderID,
+				ProductID: d.ID,
+				Quantity:  d.Quantity,
+				Status:    domain.ReservationStatusReserved,
+				ExpiresAt: expiresAt,
+			})
+		}
+		return m.db.CreateReservations(ctx, tx, reservations)
+	})
+}
+
+func (m StockRepositoryMySQL) CommitReservation(ctx context.Context, orderID string) error {
+	return m.db.StartTransaction(func(tx *gorm.DB) (err error) {
+		defer func() {
+			if err != nil {
+				logrus.Warnf("Transaction fail err = %v", err)
+			}
+		}()
+		existing, err := m.db.GetReservations(ctx, tx, builder.NewReservation().OrderIDs(orderID).ForUpdate())
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing reservation")
+		}
+		if len(existing) == 0 {
+			return domain.ReservationNotFoundError{OrderID: orderID}
+		}
+		for _, r := range existing {
+			if r.Status == domain.ReservationStatusReleased {
+				return domain.ReservationReleasedError{OrderID: orderID}
+			}
+		}
+		return m.db.UpdateReservations(ctx, tx,
+			builder.NewReservation().OrderIDs(orderID).Statuses(domain.ReservationStatusReserved),
+			map[string]any{"status": domain.ReservationStatusCommitted},
+		)
+	})
+}
+
+func (m StockRepositoryMySQL) ReleaseReservation(ctx context.Context, orderID string) error {
+	return m.db.StartTransaction(func(tx *gorm.DB) (err error) {
+		defer func() {
+			if err != nil {
+				logrus.Warnf("Transaction fail err = %v", err)
+			}
+		}()
+		reserved, err := m.db.GetReservations(ctx, tx,
+			builder.NewReservation().OrderIDs(orderID).Statuses(domain.ReservationStatusReserved).ForUpdate())
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing reservation")
+		}
+		for _, r := range reserved {
+			if err = m.db.Update(ctx, tx,
+				builder.NewStock().ProductIDs(r.ProductID),
+				map[string]any{"quantity": gorm.Expr("quantity + ?", r.Quantity)},
+			); err != nil {
+				return errors.Wrapf(err, "unable to release stock for product %s", r.ProductID)
+			}
+		}
+		if len(reserved) == 0 {
+			return nil
+		}
+		return m.db.UpdateReservations(ctx, tx,
+			builder.NewReservation().OrderIDs(orderID).Statuses(domain.ReservationStatusReserved),
+			map[string]any{"status": domain.ReservationStatusReleased},
+		)
+	})
+}
+
+func (m StockRepositoryMySQL) ReleaseExpiredReservations(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	expired, err := m.db.GetReservations(ctx, nil,
+		builder.NewReservation().Statuses(domain.ReservationStatusReserved).ExpiresBefore(before).Limit(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get expired reservations")
+	}
+	var released []string
+	for _, r := range expired {
+		if slices.Contains(released, r.OrderID) {
+			continue
+		}
+		// ReleaseReservation re-checks the status under lock, a concurrent commit wins
+		if err = m.ReleaseReservation(ctx, r.OrderID); err != nil {
+			return released, err
+		}
+		released = append(released, r.OrderID)
+	}
+	return released, nil
+}
+
+func checkEnough(existing, query []*entity.ItemWithQuantity) error {
+	have := make(map[string]int32)
+	for _, e := range existing {
+		have[e.ID] += e.Quantity
+	}
+	var (
+		missing  []string
+		failedOn []struct {
+			ID   string
+			Want int32
+			Have int32
+		}
+	)
+	for _, q := range query {
+		h, ok := have[q.ID]
+		if !ok {
+			missing = append(missing, q.ID)
+			continue
+		}
+		if q.Quantity > h {
+			failedOn = append(failedOn, struct {
+				ID   string
+				Want int32
+				Have int32
+			}{ID: q.ID, Want: q.Quantity, Have: h})
+		}
+	}
+	if len(missing) > 0 {
+		return domain.NotFoundError{Missing: missing}
+	}
+	if len(failedOn) > 0 {
+		return domain.ExceedStockError{FailedOn: failedOn}
+	}
+	return nil
+}
+
 // 悲观锁 (排他锁) SELECT * FROM o_stock WHERE product_id IN ? FOR UPDATE
 func (m StockRepositoryMySQL) updateWithPessimisticLock(
 	ctx context.Context,
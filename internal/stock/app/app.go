@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/peiyouyao/gorder/stock/adapters"
+	"github.com/peiyouyao/gorder/stock/app/command"
 	"github.com/peiyouyao/gorder/stock/app/query"
 	"github.com/peiyouyao/gorder/stock/infrastructure/intergration"
 	"github.com/peiyouyao/gorder/stock/infrastructure/persistent"
@@ -18,7 +19,12 @@ type Application struct {
 	Queries  Queries
 }
 
-type Commands struct{}
+type Commands struct {
+	ReserveItems               command.ReserveItemsHandler
+	CommitReservation          command.CommitReservationHandler
+	ReleaseReservation         command.ReleaseReservationHandler
+	ReleaseExpiredReservations command.ReleaseExpiredReservationsHandler
+}
 
 type Queries struct {
 	CheckIfItemsInStock query.CheckIfItemsInStockHandler
@@ -35,7 +41,12 @@ func NewApplication(ctx context.Context) Application {
 		ServiceName: viper.GetString("stock.service-name"),
 	})
 	return Application{
-		Commands: Commands{},
+		Commands: Commands{
+			ReserveItems:               command.NewReserveItemsHandler(stockRepo, logger, metrics),
+			CommitReservation:          command.NewCommitReservationHandler(stockRepo, logger, metrics),
+			ReleaseReservation:         command.NewReleaseReservationHandler(stockRepo, logger, metrics),
+			ReleaseExpiredReservations: command.NewReleaseExpiredReservationsHandler(stockRepo, logger, metrics),
+		},
 		Queries: Queries{
 			CheckIfItemsInStock: query.NewCheckIfItemsInStockHandler(stockRepo, stripeAPI, logger, metrics),
 			GetItems:            query.NewGetItemsHandler(stockRepo, logger, metrics),
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
+	"github.com/sirupsen/logrus"
+)
+
+type CommitReservation struct {
+	OrderID string
+}
+
+type CommitReservationHandler decorator.CommandHandler[CommitReservation, interface{}]
+
+type commitReservationHandler struct {
+	stockRepo domain.Repository
+}
+
+func NewCommitReservationHandler(
+	stockRepo domain.Repository,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) CommitReservationHandler {
+	if stockRepo == nil {
+		panic("nil stockRepo")
+	}
+	return decorator.ApplyCommandDecorators[CommitReservation, interface{}](
+		commitReservationHandler{stockRepo: stockRepo},
+		logger,
+		metrics,
+	)
+}
+
+func (h commitReservationHandler) Handle(ctx context.Context, cmd CommitReservation) (interface{}, error) {
+	if err := h.stockRepo.CommitReservation(ctx, cmd.OrderID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
+	"github.com/sirupsen/logrus"
+)
+
+type ReleaseExpiredReservations struct {
+	Before time.Time
+	Limit  int
+}
+
+// returns the order IDs whose reservation was released
+type ReleaseExpiredReservationsHandler decorator.CommandHandler[ReleaseExpiredReservations, []string]
+
+type releaseExpiredReservationsHandler struct {
+	stockRepo domain.Repository
+}
+
+func NewReleaseExpiredReservationsHandler(
+	stockRepo domain.Repository,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) ReleaseExpiredReservationsHandler {
+	if stockRepo == nil {
+		panic("nil stockRepo")
+	}
+	return decorator.ApplyCommandDecorators[ReleaseExpiredReservations, []string](
+		releaseExpiredReservationsHandler{stockRepo: stockRepo},
+		logger,
+		metrics,
+	)
+}
+
+func (h releaseExpiredReservationsHandler) Handle(ctx context.Context, cmd ReleaseExpiredReservations) ([]string, error) {
+	return h.stockRepo.ReleaseExpiredReservations(ctx, cmd.Before, cmd.Limit)
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
+	"github.com/sirupsen/logrus"
+)
+
+type ReleaseReservation struct {
+	OrderID string
+}
+
+type ReleaseReservationHandler decorator.CommandHandler[ReleaseReservation, interface{}]
+
+type releaseReservationHandler struct {
+	stockRepo domain.Repository
+}
+
+func NewReleaseReservationHandler(
+	stockRepo domain.Repository,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) ReleaseReservationHandler {
+	if stockRepo == nil {
+		panic("nil stockRepo")
+	}
+	return decorator.ApplyCommandDecorators[ReleaseReservation, interface{}](
+		releaseReservationHandler{stockRepo: stockRepo},
+		logger,
+		metrics,
+	)
+}
+
+// 归还订单预占的库存
+func (h releaseReservationHandler) Handle(ctx context.Context, cmd ReleaseReservation) (interface{}, error) {
+	if err := h.stockRepo.ReleaseReservation(ctx, cmd.OrderID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/decorator"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/common/metrics"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ReserveItems struct {
+	OrderID string
+	Items   []*entity.ItemWithQuantity
+}
+
+// returns when the reservation expires
+type ReserveItemsHandler decorator.CommandHandler[ReserveItems, time.Time]
+
+type reserveItemsHandler struct {
+	stockRepo domain.Repository
+	ttl       time.Duration
+}
+
+func NewReserveItemsHandler(
+	stockRepo domain.Repository,
+	logger *logrus.Entry,
+	metrics metrics.MetricsClient,
+) ReserveItemsHandler {
+	if stockRepo == nil {
+		panic("nil stockRepo")
+	}
+	return decorator.ApplyCommandDecorators[ReserveItems, time.Time](
+		reserveItemsHandler{
+			stockRepo: stockRepo,
+			ttl:       viper.GetDuration("stock.reservation.ttl") * time.Second,
+		},
+		logger,
+		metrics,
+	)
+}
+
+// 预占库存, 到期未 commit 则由 sweeper 归还
+func (h reserveItemsHandler) Handle(ctx context.Context, cmd ReserveItems) (time.Time, error) {
+	expiresAt := time.Now().Add(h.ttl)
+	if err := h.stockRepo.ReserveStock(ctx, cmd.OrderID, cmd.Items, expiresAt); err != nil {
+		return time.Time{}, err
+	}
+	return expiresAt, nil
+}
@@ -140,29 +140,8 @@ func (h checkIfItemsInStockHandler) checkStock(ctx context.Context, queryItems [
 	if !ok {
 		return domain.ExceedStockError{FailedOn: failedOn}
 	}
-	return h.stockRepo.UpdateStock(
-		ctx,
-		queryItems,
-		func(
-			ctx context.Context,
-			existing []*entity.ItemWithQuantity,
-			query []*entity.ItemWithQuantity,
-		) ([]*entity.ItemWithQuantity, error) {
-			var newItems []*entity.ItemWithQuantity
-			for _, e := range existing {
-				for _, q := range query {
-					if e.ID == q.ID {
-						itq, err := entity.NewValidItemWithQuantity(e.ID, e.Quantity-q.Quantity)
-						if err != nil {
-							return nil, err
-						}
-						newItems = append(newItems, itq)
-					}
-				}
-			}
-			return newItems, nil
-		},
-	)
+	// 只检查, 扣减交给 ReserveItems
+	return nil
 }
 
 func (h checkIfItemsInStockHandler) tidyItems(items []*entity.ItemWithQuantity) (res map[string]int32) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/peiyouyao/gorder/common/entity"
 )
@@ -20,8 +21,24 @@ type Repository interface {
 			query []*entity.ItemWithQuantity,
 		) ([]*entity.ItemWithQuantity, error),
 	) error
+	// ReserveStock takes the quantities out of stock and holds them for orderID until expiresAt,
+	// reserving twice for the same order is a no-op.
+	ReserveStock(ctx context.Context, orderID string, data []*entity.ItemWithQuantity, expiresAt time.Time) error
+	// CommitReservation makes the reservation of a paid order permanent.
+	CommitReservation(ctx context.Context, orderID string) error
+	// ReleaseReservation gives a reservation that was not committed back to stock, releasing twice is a no-op.
+	ReleaseReservation(ctx context.Context, orderID string) error
+	// ReleaseExpiredReservations releases reservations of at most limit orders expired before `before`,
+	// returning the released order IDs.
+	ReleaseExpiredReservations(ctx context.Context, before time.Time, limit int) ([]string, error)
 }
 
+const (
+	ReservationStatusReserved  = "reserved"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+)
+
 type NotFoundError struct {
 	Missing []string
 }
@@ -41,3 +58,20 @@ type ExceedStockError struct {
 func (e ExceedStockError) Error() string {
 	return fmt.Sprintf("not enough stock for %v", e.FailedOn)
 }
+
+type ReservationNotFoundError struct {
+	OrderID string
+}
+
+func (e ReservationNotFoundError) Error() string {
+	return fmt.Sprintf("no stock reservation for order %s", e.OrderID)
+}
+
+// ReservationReleasedError means the stock went back on sale before the order was paid.
+type ReservationReleasedError struct {
+	OrderID string
+}
+
+func (e ReservationReleasedError) Error() string {
+	return fmt.Sprintf("stock reservation for order %s is already released", e.OrderID)
+}
@@ -0,0 +1,93 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/stock/app"
+	"github.com/peiyouyao/gorder/stock/app/command"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// 这些事件都意味着订单不会再被支付
+var releaseEvents = []string{broker.EventOrderCancelled, broker.EventOrderExpired}
+
+/*
+消费 mq 中 order.cancelled / order.expired 消息, 归还订单预占的库存
+*/
+type Consumer struct {
+	app   app.Application
+	dedup *broker.Dedup
+}
+
+func NewConsumer(app app.Application) *Consumer {
+	return &Consumer{
+		app:   app,
+		dedup: broker.NewDedup("stock"),
+	}
+}
+
+// 两个队列一起消费到 ctx 结束
+func (c *Consumer) Listen(ctx context.Context, sub broker.Subscriber) error {
+	errs := make(chan error, len(releaseEvents))
+	for _, event := range releaseEvents {
+		go func() {
+			errs <- c.listen(ctx, sub, event)
+		}()
+	}
+	var err error
+	for range releaseEvents {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (c *Consumer) listen(ctx context.Context, sub broker.Subscriber, event string) error {
+	// 具名持久队列, stock 重启期间的消息不会丢
+	return sub.Subscribe(ctx, broker.Subscription{
+		Queue:    "stock." + event,
+		Exchange: event,
+	}, c.handleMessage)
+}
+
+func (c *Consumer) handleMessage(d broker.Delivery) {
+	msg := d.Message()
+	logrus.WithFields(logrus.Fields{
+		"from_q": d.Queue(),
+		"msg_id": msg.ID,
+	}).Info("Receive order release msg")
+
+	tr := otel.Tracer("rabbitmq")
+	ctx, span := tr.Start(
+		broker.ExtractRabbitMQHeaders(context.Background(), msg.Headers),
+		fmt.Sprintf("rabbitmq.%s.consume", d.Queue()),
+	)
+	defer span.End()
+
+	c.dedup.Handle(ctx, d, func(ctx context.Context) error {
+		// 队列名是 stock.<event>
+		env, err := broker.DecodeEvent(msg, strings.TrimPrefix(d.Queue(), "stock."))
+		if err != nil {
+			logrus.WithField("err", err.Error()).Warn("Decode event fail")
+			return broker.Unprocessable(err)
+		}
+		o := env.GetOrder()
+
+		if _, err = c.app.Commands.ReleaseReservation.Handle(ctx, command.ReleaseReservation{OrderID: o.ID}); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"order_id": o.ID,
+				"err":      err.Error(),
+			}).Error("Release reservation fail")
+			return err
+		}
+
+		span.AddEvent("stock.released")
+		logrus.Info("Consume order release ok")
+		return nil
+	})
+}
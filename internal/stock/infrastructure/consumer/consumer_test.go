@@ -0,0 +1,91 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peiyouyao/gorder/common/broker"
+	"github.com/peiyouyao/gorder/common/entity"
+	"github.com/peiyouyao/gorder/stock/app"
+	"github.com/peiyouyao/gorder/stock/app/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 记录归还过库存的订单, fail 次数内返回错误
+type fakeRelease struct {
+	mu       sync.Mutex
+	fail     int
+	released []string
+}
+
+func (f *fakeRelease) Handle(_ context.Context, cmd command.ReleaseReservation) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail > 0 {
+		f.fail--
+		return nil, errors.New("mysql down")
+	}
+	f.released = append(f.released, cmd.OrderID)
+	return nil, nil
+}
+
+func (f *fakeRelease) orders() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.released...)
+}
+
+func TestConsumer_Listen(t *testing.T) {
+	m := broker.NewMemory()
+	m.Workers = 1
+	m.MaxRetry = 3
+	m.Backoff = func(int64) time.Duration { return time.Millisecond }
+
+	release := &fakeRelease{fail: 1}
+	c := &Consumer{
+		app:   app.Application{Commands: app.Commands{ReleaseReservation: release}},
+		dedup: broker.NewMemoryDedup("stock"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Listen(ctx, m)
+	}()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}()
+
+	publish := func(event, id, orderID string) error {
+		return broker.PublishEvent(ctx, &broker.PublishEventReq{
+			Publisher: m,
+			Routing:   broker.Fanout,
+			Exchange:  event,
+			Body:      &entity.Order{ID: orderID},
+			Producer:  "order",
+			MessageID: id,
+			Mandatory: true,
+		})
+	}
+	// 队列绑定好之前的消息无法路由
+	require.Eventually(t, func() bool {
+		return publish(broker.EventOrderCancelled, "msg-1", "order-1") == nil
+	}, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool {
+		return publish(broker.EventOrderExpired, "msg-2", "order-2") == nil
+	}, time.Second, time.Millisecond)
+	// 重复的消息只处理一次
+	require.NoError(t, publish(broker.EventOrderCancelled, "msg-1", "order-1"))
+
+	// 第一次归还失败的消息重投后成功
+	require.Eventually(t, func() bool {
+		return len(release.orders()) == 2
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"order-1", "order-2"}, release.orders())
+	assert.Empty(t, m.DeadLetters())
+}
@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Reservation struct {
+	OrderID_       []string   `json:"order_id,omitempty"`
+	Status_        []string   `json:"status,omitempty"`
+	ExpiresBefore_ *time.Time `json:"expires_before,omitempty"`
+
+	// extend fields
+	Limit_     int  `json:"limit,omitempty"`
+	ForUpdate_ bool `json:"for_update,omitempty"`
+}
+
+func NewReservation() *Reservation {
+	return &Reservation{}
+}
+
+// implement ArgFormatter interface
+func (r *Reservation) FormatArg() (string, error) {
+	bytes, err := json.Marshal(r)
+	return string(bytes), err
+}
+
+func (r *Reservation) Fill(db *gorm.DB) *gorm.DB {
+	db = r.fillWhere(db)
+	if r.Limit_ > 0 {
+		db = db.Limit(r.Limit_)
+	}
+	return db
+}
+
+func (r *Reservation) fillWhere(db *gorm.DB) *gorm.DB {
+	if len(r.OrderID_) > 0 {
+		db = db.Where("order_id in (?)", r.OrderID_)
+	}
+	if len(r.Status_) > 0 {
+		db = db.Where("status in (?)", r.Status_)
+	}
+	if r.ExpiresBefore_ != nil {
+		db = db.Where("expires_at < ?", *r.ExpiresBefore_)
+	}
+
+	if r.ForUpdate_ {
+		db = db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+	}
+	return db
+}
+
+func (r *Reservation) OrderIDs(v ...string) *Reservation {
+	r.OrderID_ = v
+	return r
+}
+
+func (r *Reservation) Statuses(v ...string) *Reservation {
+	r.Status_ = v
+	return r
+}
+
+func (r *Reservation) ExpiresBefore(v time.Time) *Reservation {
+	r.ExpiresBefore_ = &v
+	return r
+}
+
+func (r *Reservation) Limit(v int) *Reservation {
+	r.Limit_ = v
+	return r
+}
+
+func (r *Reservation) ForUpdate() *Reservation {
+	r.ForUpdate_ = true
+	return r
+}
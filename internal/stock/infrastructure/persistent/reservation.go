@@ -0,0 +1,56 @@
+package persistent
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/stock/infrastructure/persistent/builder"
+	"gorm.io/gorm"
+)
+
+// 一个订单的每个商品一行, (order_id, product_id) 唯一
+type ReservationModel struct {
+	ID        int64     `gorm:"column:id"`
+	OrderID   string    `gorm:"column:order_id"`
+	ProductID string    `gorm:"column:product_id"`
+	Quantity  int32     `gorm:"column:quantity"`
+	Status    string    `gorm:"column:status"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (m ReservationModel) TableName() string {
+	return "o_stock_reservation"
+}
+
+// LockBatchByID locks the stock rows inside tx, unlike GetBatchByID which always reads outside of it.
+func (d MySQL) LockBatchByID(ctx context.Context, tx *gorm.DB, query *builder.Stock) (res []StockModel, err error) {
+	_, dlog := logMySQL(ctx, "LockBatchByID", query)
+	defer dlog(res, &err)
+
+	err = query.ForUpdate().Fill(d.useTransaction(tx).WithContext(ctx)).Find(&res).Error
+	return
+}
+
+func (d MySQL) GetReservations(ctx context.Context, tx *gorm.DB, query *builder.Reservation) (res []ReservationModel, err error) {
+	_, dlog := logMySQL(ctx, "GetReservations", query)
+	defer dlog(res, &err)
+
+	err = query.Fill(d.useTransaction(tx).WithContext(ctx)).Find(&res).Error
+	return
+}
+
+func (d MySQL) CreateReservations(ctx context.Context, tx *gorm.DB, create []ReservationModel) (err error) {
+	_, dlog := logMySQL(ctx, "CreateReservations", create)
+	defer dlog(nil, &err)
+
+	return d.useTransaction(tx).WithContext(ctx).Create(&create).Error
+}
+
+func (d MySQL) UpdateReservations(ctx context.Context, tx *gorm.DB, cond *builder.Reservation, update map[string]any) (err error) {
+	_, dlog := logMySQL(ctx, "UpdateReservations", cond)
+	defer dlog(nil, &err)
+
+	return cond.Fill(d.useTransaction(tx).WithContext(ctx).Model(&ReservationModel{})).Updates(update).Error
+}
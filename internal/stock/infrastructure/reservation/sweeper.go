@@ -0,0 +1,56 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"github.com/peiyouyao/gorder/stock/app"
+	"github.com/peiyouyao/gorder/stock/app/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+定时归还过期未 commit 的预占库存, 兜底 order 没能 release 的情况
+*/
+type Sweeper struct {
+	app       app.Application
+	interval  time.Duration
+	batchSize int
+}
+
+func NewSweeper(app app.Application) *Sweeper {
+	return &Sweeper{
+		app:       app,
+		interval:  viper.GetDuration("stock.reservation.sweep-interval") * time.Second,
+		batchSize: viper.GetInt("stock.reservation.batch-size"),
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	logrus.WithField("interval", s.interval).Info("Reservation sweeper started")
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Reservation sweeper stopped")
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	released, err := s.app.Commands.ReleaseExpiredReservations.Handle(ctx, command.ReleaseExpiredReservations{
+		Before: time.Now(),
+		Limit:  s.batchSize,
+	})
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("Release expired reservations fail err=%v", err)
+	}
+	if len(released) > 0 {
+		logrus.WithContext(ctx).WithField("order_ids", released).Info("Released expired reservations")
+	}
+}
@@ -2,7 +2,12 @@ package main
 
 import (
 	"context"
+	"os/signal"
+	"sync"
+	"syscall"
 
+	"github.com/peiyouyao/gorder/common/actor"
+	"github.com/peiyouyao/gorder/common/broker"
 	_ "github.com/peiyouyao/gorder/common/config"
 	"github.com/peiyouyao/gorder/common/discovery"
 	"github.com/peiyouyao/gorder/common/genproto/stockpb"
@@ -10,6 +15,8 @@ import (
 	"github.com/peiyouyao/gorder/common/server"
 	"github.com/peiyouyao/gorder/common/tracing"
 	"github.com/peiyouyao/gorder/stock/app"
+	"github.com/peiyouyao/gorder/stock/infrastructure/consumer"
+	"github.com/peiyouyao/gorder/stock/infrastructure/reservation"
 	"github.com/peiyouyao/gorder/stock/ports"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -22,15 +29,17 @@ func init() {
 
 func main() {
 	serviceName := viper.GetString("stock.service-name")
+	actor.SetService(serviceName)
 
-	ctx, cancal := context.WithCancel(context.Background())
+	// SIGINT / SIGTERM 后停止接收消息, 等处理中的消息 ack 完再退出
+	ctx, cancal := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancal()
 
 	shutdown, err := tracing.InitJaegerProvider(viper.GetString("jaeger.url"), serviceName)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer shutdown(ctx)
+	defer shutdown(context.Background())
 
 	application := app.NewApplication(ctx)
 
@@ -42,8 +51,27 @@ func main() {
 		_ = deregisterFn()
 	}()
 
-	server.RunGRPCServer(serviceName, func(server *grpc.Server) {
+	b, closeBroker := broker.Open(serviceName)
+	// 消费者 drain 完后才关闭
+	defer func() {
+		_ = closeBroker()
+	}()
+	var consumers sync.WaitGroup
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		if err := consumer.NewConsumer(application).Listen(ctx, b); err != nil {
+			logrus.WithField("consumer", "stock").Warnf("Consumer stopped err=%v", err)
+		}
+	}()
+	go reservation.NewSweeper(application).Run(ctx)
+
+	go server.RunGRPCServer(serviceName, func(server *grpc.Server) {
 		svc := ports.NewGRPCServer(application)
 		stockpb.RegisterStockServiceServer(server, svc)
 	})
+
+	<-ctx.Done()
+	logrus.Info("Receive signal, draining consumers ...")
+	consumers.Wait()
 }
@@ -2,13 +2,18 @@ package ports
 
 import (
 	context "context"
+	"errors"
 
 	"github.com/peiyouyao/gorder/common/convert"
 	"github.com/peiyouyao/gorder/common/genproto/stockpb"
 	"github.com/peiyouyao/gorder/common/tracing"
 	"github.com/peiyouyao/gorder/stock/app"
+	"github.com/peiyouyao/gorder/stock/app/command"
 	"github.com/peiyouyao/gorder/stock/app/query"
+	domain "github.com/peiyouyao/gorder/stock/domain/stock"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // impl stockpb.StockServiceServer
@@ -47,3 +52,57 @@ func (G GRPCServer) CheckIfItemsInStock(ctx context.Context, request *stockpb.Ch
 		Items:   convert.ItemEntitiesToProtos(items),
 	}, nil
 }
+
+func (G GRPCServer) ReserveItems(ctx context.Context, request *stockpb.ReserveItemsRequest) (*stockpb.ReserveItemsResponse, error) {
+	_, span := tracing.Start(ctx, "ReserveItems")
+	defer span.End()
+
+	expiresAt, err := G.app.Commands.ReserveItems.Handle(ctx, command.ReserveItems{
+		OrderID: request.OrderID,
+		Items:   convert.ItemWithQuantityProtosToEntities(request.Items),
+	})
+	if err != nil {
+		return nil, reservationStatus(err)
+	}
+	return &stockpb.ReserveItemsResponse{
+		OrderID:   request.OrderID,
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
+
+func (G GRPCServer) CommitReservation(ctx context.Context, request *stockpb.CommitReservationRequest) (*emptypb.Empty, error) {
+	_, span := tracing.Start(ctx, "CommitReservation")
+	defer span.End()
+
+	if _, err := G.app.Commands.CommitReservation.Handle(ctx, command.CommitReservation{OrderID: request.OrderID}); err != nil {
+		return nil, reservationStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (G GRPCServer) ReleaseReservation(ctx context.Context, request *stockpb.ReleaseReservationRequest) (*emptypb.Empty, error) {
+	_, span := tracing.Start(ctx, "ReleaseReservation")
+	defer span.End()
+
+	if _, err := G.app.Commands.ReleaseReservation.Handle(ctx, command.ReleaseReservation{OrderID: request.OrderID}); err != nil {
+		return nil, reservationStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func reservationStatus(err error) error {
+	var (
+		notFound    domain.NotFoundError
+		exceed      domain.ExceedStockError
+		noReserve   domain.ReservationNotFoundError
+		releasedErr domain.ReservationReleasedError
+	)
+	switch {
+	case errors.As(err, &notFound), errors.As(err, &noReserve):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &exceed), errors.As(err, &releasedErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -49,5 +49,6 @@ function gen() {
 }
 
 gen internal/order/ports ports order
+gen internal/kitchen/ports ports kitchen
 
 log_success "openapi generate success!"
\ No newline at end of file